@@ -0,0 +1,134 @@
+package block
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	configMaxTransactionsByAddress = 1000
+)
+
+// accountIndex maps an address to hashes of transactions which are sent or
+// received by the address. Internal ICX transfers are included.
+//	address => number of transactions
+//	address + index(8 bytes big endian) => transaction hash
+type accountIndex struct {
+	bk *bucket
+}
+
+func newAccountIndex(database db.Database) (*accountIndex, error) {
+	bk := newBucket(database, db.TransactionHashByAddress, nil)
+	if bk == nil {
+		return nil, errors.CriticalIOError.New("fail to get bucket for account index")
+	}
+	return &accountIndex{bk: bk}, nil
+}
+
+func keyForAccountEntry(addr []byte, idx int64) []byte {
+	key := make([]byte, len(addr)+8)
+	copy(key, addr)
+	binary.BigEndian.PutUint64(key[len(addr):], uint64(idx))
+	return key
+}
+
+func (ai *accountIndex) count(addr module.Address) (int64, error) {
+	var cnt int64
+	if err := ai.bk.get(raw(addr.Bytes()), &cnt); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return cnt, nil
+}
+
+func (ai *accountIndex) add(addr module.Address, id []byte) error {
+	cnt, err := ai.count(addr)
+	if err != nil {
+		return err
+	}
+	if err := ai.bk.set(raw(keyForAccountEntry(addr.Bytes(), cnt)), raw(id)); err != nil {
+		return err
+	}
+	return ai.bk.set(raw(addr.Bytes()), cnt+1)
+}
+
+func (ai *accountIndex) get(addr module.Address, start int64, limit int) ([][]byte, error) {
+	cnt, err := ai.count(addr)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > configMaxTransactionsByAddress {
+		limit = configMaxTransactionsByAddress
+	}
+	ids := make([][]byte, 0)
+	for idx := start; idx < cnt && len(ids) < limit; idx++ {
+		id, err := ai.bk.getBytes(raw(keyForAccountEntry(addr.Bytes(), idx)))
+		if err != nil {
+			return nil, errors.InvalidStateError.Wrapf(err,
+				"fail to get transaction addr=%s idx=%d", addr, idx)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// addTransactions adds transactions in the list with their receipts in the
+// receipt list to the index.
+func (ai *accountIndex) addTransactions(txs module.TransactionList, rl module.ReceiptList) error {
+	for it := txs.Iterator(); it.Has(); it.Next() {
+		tx, idx, err := it.Get()
+		if err != nil {
+			return err
+		}
+		rct, err := rl.Get(idx)
+		if err != nil {
+			return err
+		}
+		for _, addr := range accountsOf(tx, rct) {
+			if err := ai.add(addr, tx.ID()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// accountsOf returns addresses related to the transaction without
+// duplication.
+func accountsOf(tx module.Transaction, rct module.Receipt) []module.Address {
+	var addrs []module.Address
+	appendAddr := func(addr module.Address) {
+		if addr == nil {
+			return
+		}
+		for _, a := range addrs {
+			if a.Equal(addr) {
+				return
+			}
+		}
+		addrs = append(addrs, addr)
+	}
+	appendAddr(tx.From())
+	appendAddr(rct.To())
+	for it := rct.EventLogIterator(); it.Has(); it.Next() {
+		ev, err := it.Get()
+		if err != nil {
+			break
+		}
+		indexed := ev.Indexed()
+		if len(indexed) != 4 ||
+			!bytes.Equal(indexed[0], []byte(txresult.EventLogICXTransfer)) {
+			continue
+		}
+		appendAddr(newAddress(indexed[1]))
+		appendAddr(newAddress(indexed[2]))
+	}
+	return addrs
+}
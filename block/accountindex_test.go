@@ -0,0 +1,47 @@
+package block
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestAccountIndex_Basic(t *testing.T) {
+	ai, err := newAccountIndex(db.NewMapDB())
+	assert.NoError(t, err)
+
+	addr1 := common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.NewAddressFromString("hx0000000000000000000000000000000000000002")
+
+	cnt, err := ai.count(addr1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, cnt)
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, ai.add(addr1, []byte(fmt.Sprintf("tx%d", i))))
+	}
+	assert.NoError(t, ai.add(addr2, []byte("tx0")))
+
+	cnt, err = ai.count(addr1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, cnt)
+	cnt, err = ai.count(addr2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, cnt)
+
+	ids, err := ai.get(addr1, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("tx1"), []byte("tx2")}, ids)
+
+	ids, err = ai.get(addr1, 3, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("tx3"), []byte("tx4")}, ids)
+
+	ids, err = ai.get(addr1, 5, 10)
+	assert.NoError(t, err)
+	assert.Len(t, ids, 0)
+}
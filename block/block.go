@@ -7,6 +7,12 @@ import (
 	"github.com/icon-project/goloop/module"
 )
 
+// VerifyBlock verifies the block is linked to the previous block with the
+// votes signed by the validators.
+func VerifyBlock(b module.BlockData, prev module.BlockData, validators module.ValidatorList) error {
+	return verifyBlock(b, prev, validators)
+}
+
 func verifyBlock(b module.BlockData, prev module.BlockData, validators module.ValidatorList) error {
 	if b.Height() != prev.Height()+1 {
 		return errors.New("bad height")
@@ -0,0 +1,88 @@
+package block
+
+import (
+	"encoding/binary"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie/mta"
+)
+
+// blockMTA is the Merkle tree accumulator of IDs of finalized blocks. The
+// state of the accumulator is kept for each height, so the witness of a block
+// can be made for the accumulator at any later height. It's local to the
+// node, and it's not committed in block headers.
+//	height(8 bytes big endian) => state of the accumulator
+//	hash of node => node of the accumulator
+type blockMTA struct {
+	bucket db.Bucket
+}
+
+func newBlockMTA(database db.Database) (*blockMTA, error) {
+	bk, err := database.GetBucket(db.BlockMTA)
+	if err != nil {
+		return nil, errors.CriticalIOError.Wrap(err, "fail to get bucket for block MTA")
+	}
+	return &blockMTA{bucket: bk}, nil
+}
+
+func keyForMTAState(height int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(height))
+	return key
+}
+
+func (bm *blockMTA) has(height int64) bool {
+	return bm.bucket.Has(keyForMTAState(height))
+}
+
+// accumulatorAt returns the accumulator after the block at the height is
+// added. The accumulator is empty if there is no state for the height.
+func (bm *blockMTA) accumulatorAt(height int64) (*mta.Accumulator, error) {
+	acc := &mta.Accumulator{
+		KeyForState: keyForMTAState(height),
+		Bucket:      bm.bucket,
+	}
+	if err := acc.Recover(); err != nil {
+		return nil, errors.CriticalFormatError.Wrapf(err,
+			"fail to recover block MTA height=%d", height)
+	}
+	return acc, nil
+}
+
+// add adds the block ID to the accumulator at the previous height and stores
+// the state for the height. It starts a new accumulator if there is no state
+// for the previous height.
+func (bm *blockMTA) add(height int64, id []byte) error {
+	acc := &mta.Accumulator{Bucket: bm.bucket}
+	if height > genesisHeight {
+		var err error
+		if acc, err = bm.accumulatorAt(height - 1); err != nil {
+			return err
+		}
+	}
+	acc.KeyForState = keyForMTAState(height)
+	acc.AddHash(id)
+	return acc.Flush()
+}
+
+// witnessFor returns the index of the block at the height in the accumulator
+// at atHeight and hashes of the witness for the block.
+func (bm *blockMTA) witnessFor(height, atHeight int64) (int64, [][]byte, error) {
+	acc, err := bm.accumulatorAt(atHeight)
+	if err != nil {
+		return 0, nil, err
+	}
+	first := atHeight - acc.Len() + 1
+	if acc.Len() == 0 || height < first || height > atHeight {
+		return 0, nil, errors.NotFoundError.Errorf(
+			"NoBlockInAccumulator(height=%d,at=%d)", height, atHeight)
+	}
+	idx := height - first
+	w, err := acc.WitnessFor(idx)
+	if err != nil {
+		return 0, nil, errors.CriticalFormatError.Wrapf(err,
+			"fail to get witness height=%d at=%d", height, atHeight)
+	}
+	return idx, mta.WitnessesToHashes(w), nil
+}
@@ -0,0 +1,57 @@
+package block
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie/mta"
+)
+
+func testBlockID(height int64) []byte {
+	return crypto.SHA3Sum256([]byte(fmt.Sprintf("block%d", height)))
+}
+
+func TestBlockMTA_Basic(t *testing.T) {
+	for _, from := range []int64{0, 5} {
+		t.Run(fmt.Sprint("from", from), func(t *testing.T) {
+			bm, err := newBlockMTA(db.NewMapDB())
+			assert.NoError(t, err)
+
+			const to = 20
+			for h := from; h <= to; h++ {
+				assert.False(t, bm.has(h))
+				assert.NoError(t, bm.add(h, testBlockID(h)))
+				assert.True(t, bm.has(h))
+			}
+
+			for at := from; at <= to; at++ {
+				acc, err := bm.accumulatorAt(at)
+				assert.NoError(t, err)
+				assert.EqualValues(t, at-from+1, acc.Len())
+				for h := from; h <= at; h++ {
+					idx, hashes, err := bm.witnessFor(h, at)
+					assert.NoError(t, err)
+					assert.EqualValues(t, h-from, idx)
+					w := mta.HashesToWitness(hashes, idx)
+					assert.NoError(t, acc.Verify(w, testBlockID(h)),
+						"height=%d at=%d", h, at)
+					assert.Error(t, acc.Verify(w, testBlockID(h+1)))
+				}
+			}
+
+			_, _, err = bm.witnessFor(to, to-1)
+			assert.True(t, errors.NotFoundError.Equals(err))
+			_, _, err = bm.witnessFor(to, to+1)
+			assert.True(t, errors.NotFoundError.Equals(err))
+			if from > 0 {
+				_, _, err = bm.witnessFor(from-1, to)
+				assert.True(t, errors.NotFoundError.Equals(err))
+			}
+		})
+	}
+}
@@ -243,6 +243,13 @@ func (b voteSetBuilder) OnData(value []byte, builder merkle.Builder) error {
 	return nil
 }
 
+// NewBlockWithBuilder returns the block for the hash, whose header, votes,
+// validators and transactions are requested to the builder. The block is
+// available after the requests are resolved.
+func NewBlockWithBuilder(builder merkle.Builder, vld module.CommitVoteSetDecoder, hash []byte) module.Block {
+	return newBlockWithBuilder(builder, vld, hash)
+}
+
 func newBlockWithBuilder(builder merkle.Builder, vld module.CommitVoteSetDecoder, hash []byte) module.Block {
 	blk := new(blockV2)
 	blk._id = hash
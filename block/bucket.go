@@ -71,6 +71,14 @@ func (b *bucket) set(key interface{}, value interface{}) error {
 	return b.dbBucket.Set(keyBS, valueBS)
 }
 
+func (b *bucket) delete(key interface{}) error {
+	keyBS, err := b._marshal(key)
+	if err != nil {
+		return err
+	}
+	return b.dbBucket.Delete(keyBS)
+}
+
 func (b *bucket) put(value interface{}) error {
 	valueBS, err := b._marshal(value)
 	if err != nil {
@@ -29,9 +29,10 @@ const (
 var dbCodec = codec.BC
 
 const (
-	keyLastBlockHeight = "block.lastHeight"
-	genesisHeight      = 0
-	configCacheCap     = 10
+	keyLastBlockHeight    = "block.lastHeight"
+	keyAccountIndexHeight = "block.accountIndexHeight"
+	genesisHeight         = 0
+	configCacheCap        = 10
 )
 
 type transactionLocator struct {
@@ -82,6 +83,7 @@ type manager struct {
 	finalized       *bnode
 	finalizationCBs []finalizationCB
 	timestamper     module.Timestamper
+	accountIndex    *accountIndex
 }
 
 func (m *manager) db() db.Database {
@@ -544,6 +546,13 @@ func NewManager(chain module.Chain, timestamper module.Timestamper) (module.Bloc
 	m.chainContext.trtr.Logger = chain.Logger().WithFields(log.Fields{
 		log.FieldKeyModule: "BM|TRANS",
 	})
+	if chain.AccountIndex() {
+		ai, err := newAccountIndex(m.db())
+		if err != nil {
+			return nil, err
+		}
+		m.accountIndex = ai
+	}
 	chainPropBucket, err := m.bucketFor(db.ChainProperty)
 	if err != nil {
 		return nil, err
@@ -594,6 +603,15 @@ func NewManager(chain module.Chain, timestamper module.Timestamper) (module.Bloc
 			cid, m.chain.CID())
 	}
 
+	if err := m.initBlockMTA(height); err != nil {
+		return nil, err
+	}
+	if m.accountIndex != nil {
+		if err := m.initAccountIndex(height); err != nil {
+			return nil, err
+		}
+	}
+
 	mtr, _ := m.sm.CreateInitialTransition(lastFinalized.Result(), lastFinalized.NextValidators())
 	if mtr == nil {
 		return nil, err
@@ -825,6 +843,10 @@ func (m *manager) finalizePrunedBlock() error {
 		}
 	}
 
+	if err := m.initBlockMTA(blk.Height()); err != nil {
+		return err
+	}
+
 	mtr, _ := m.sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
 	if mtr == nil {
 		return err
@@ -941,8 +963,8 @@ func (m *manager) Commit(block module.BlockCandidate) error {
 	return nil
 }
 
-func (m *manager) bucketFor(id db.BucketID) (*bucket, error) {
-	b, err := m.db().GetBucket(id)
+func bucketOf(database db.Database, id db.BucketID) (*bucket, error) {
+	b, err := database.GetBucket(id)
 	if err != nil {
 		return nil, err
 	}
@@ -952,6 +974,10 @@ func (m *manager) bucketFor(id db.BucketID) (*bucket, error) {
 	}, nil
 }
 
+func (m *manager) bucketFor(id db.BucketID) (*bucket, error) {
+	return bucketOf(m.db(), id)
+}
+
 func (m *manager) Finalize(block module.BlockCandidate) error {
 	m.syncer.begin()
 	defer m.syncer.end()
@@ -968,7 +994,9 @@ func (m *manager) finalize(bn *bnode) error {
 	// TODO update nmap
 	block := bn.block
 
+	var prev module.Block
 	if m.finalized != nil {
+		prev = m.finalized.block
 		m.removeNodeExcept(m.finalized, bn)
 		err := m.sm.Finalize(
 			bn.in.mtransition(),
@@ -990,74 +1018,274 @@ func (m *manager) finalize(bn *bnode) error {
 	}
 
 	if blockV2, ok := block.(*blockV2); ok {
-		hb, err := m.bucketFor(db.BytesByHash)
-		if err != nil {
+		// Indexes of the block are written at once, so the database keeps
+		// them consistent with the last block height on a crash.
+		ldb := db.NewLayerDB(m.db())
+		if err := m.writeBlockIndexes(ldb, prev, blockV2); err != nil {
+			ldb.Flush(false)
 			return err
 		}
-		if err = hb.put(blockV2._headerFormat()); err != nil {
+		if err := ldb.Flush(true); err != nil {
 			return err
 		}
-		if err = hb.set(raw(block.Votes().Hash()), raw(block.Votes().Bytes())); err != nil {
+	}
+	m.logger.Debugf("Finalize(%x)\n", block.ID())
+	for i := 0; i < len(m.finalizationCBs); {
+		cb := m.finalizationCBs[i]
+		if cb(block) {
+			last := len(m.finalizationCBs) - 1
+			m.finalizationCBs[i] = m.finalizationCBs[last]
+			m.finalizationCBs[last] = nil
+			m.finalizationCBs = m.finalizationCBs[:last]
+			continue
+		}
+		i++
+	}
+	return nil
+}
+
+// writeBlockIndexes writes the header, votes and indexes of the finalized
+// block to the database.
+func (m *manager) writeBlockIndexes(database db.Database, prev module.Block, block *blockV2) error {
+	hb, err := bucketOf(database, db.BytesByHash)
+	if err != nil {
+		return err
+	}
+	if err = hb.put(block._headerFormat()); err != nil {
+		return err
+	}
+	if err = hb.set(raw(block.Votes().Hash()), raw(block.Votes().Bytes())); err != nil {
+		return err
+	}
+	lb, err := bucketOf(database, db.TransactionLocatorByHash)
+	if err != nil {
+		return err
+	}
+	for it := block.PatchTransactions().Iterator(); it.Has(); it.Next() {
+		tr, i, err := it.Get()
+		if err != nil {
+			return err
+		}
+		trLoc := transactionLocator{
+			BlockHeight:      block.Height(),
+			TransactionGroup: module.TransactionGroupPatch,
+			IndexInGroup:     i,
+		}
+		if err = lb.set(raw(tr.ID()), trLoc); err != nil {
 			return err
 		}
-		lb, err := m.bucketFor(db.TransactionLocatorByHash)
+	}
+	for it := block.NormalTransactions().Iterator(); it.Has(); it.Next() {
+		tr, i, err := it.Get()
 		if err != nil {
 			return err
 		}
-		for it := block.PatchTransactions().Iterator(); it.Has(); it.Next() {
-			tr, i, err := it.Get()
-			if err != nil {
-				return err
-			}
-			trLoc := transactionLocator{
-				BlockHeight:      block.Height(),
-				TransactionGroup: module.TransactionGroupPatch,
-				IndexInGroup:     i,
-			}
-			if err = lb.set(raw(tr.ID()), trLoc); err != nil {
-				return err
-			}
+		trLoc := transactionLocator{
+			BlockHeight:      block.Height(),
+			TransactionGroup: module.TransactionGroupNormal,
+			IndexInGroup:     i,
 		}
-		for it := block.NormalTransactions().Iterator(); it.Has(); it.Next() {
-			tr, i, err := it.Get()
-			if err != nil {
-				return err
-			}
-			trLoc := transactionLocator{
-				BlockHeight:      block.Height(),
-				TransactionGroup: module.TransactionGroupNormal,
-				IndexInGroup:     i,
-			}
-			if err = lb.set(raw(tr.ID()), trLoc); err != nil {
-				return err
-			}
+		if err = lb.set(raw(tr.ID()), trLoc); err != nil {
+			return err
+		}
+	}
+	if m.accountIndex != nil {
+		if err = m.indexAccounts(database, prev, block); err != nil {
+			return err
 		}
-		b, err := m.bucketFor(db.BlockHeaderHashByHeight)
+	}
+	b, err := bucketOf(database, db.BlockHeaderHashByHeight)
+	if err != nil {
+		return err
+	}
+	if err = b.set(block.Height(), raw(block.ID())); err != nil {
+		return err
+	}
+	bm, err := newBlockMTA(database)
+	if err != nil {
+		return err
+	}
+	if err = bm.add(block.Height(), block.ID()); err != nil {
+		return err
+	}
+	chainProp, err := bucketOf(database, db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	if err = chainProp.set(raw(keyLastBlockHeight), block.Height()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// indexAccounts adds transactions to the account index. Receipts of normal
+// transactions are available on the next block, so it indexes normal
+// transactions of the previous block and patch transactions of the block.
+func (m *manager) indexAccounts(database db.Database, prev module.Block, blk module.Block) error {
+	ai, err := newAccountIndex(database)
+	if err != nil {
+		return err
+	}
+	if prev != nil {
+		rl, err := m.sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
 		if err != nil {
 			return err
 		}
-		if err = b.set(block.Height(), raw(block.ID())); err != nil {
+		if err := ai.addTransactions(prev.NormalTransactions(), rl); err != nil {
 			return err
 		}
-		chainProp, err := m.bucketFor(db.ChainProperty)
+	}
+	rl, err := m.sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupPatch)
+	if err != nil {
+		return err
+	}
+	if err := ai.addTransactions(blk.PatchTransactions(), rl); err != nil {
+		return err
+	}
+	chainProp, err := bucketOf(database, db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return chainProp.set(raw(keyAccountIndexHeight), blk.Height())
+}
+
+// initAccountIndex indexes transactions of the blocks up to the height,
+// which are finalized while the account index is disabled. It continues from
+// the last indexed block, or starts from the oldest block in the database if
+// the index is not built yet. On the first build, it skips old blocks until
+// it finds the first block with receipts, because receipts of old blocks may
+// be pruned.
+func (m *manager) initAccountIndex(height int64) error {
+	chainProp, err := m.bucketFor(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	var indexed int64
+	from := height
+	if err := chainProp.get(raw(keyAccountIndexHeight), &indexed); err == nil {
+		if indexed >= height {
+			return nil
+		}
+		from = indexed + 1
+	} else if errors.NotFoundError.Equals(err) {
+		indexed = -1
+		for from > genesisHeight {
+			if _, err := m.getBlockByHeight(from - 1); err != nil {
+				break
+			}
+			from--
+		}
+	} else {
+		return err
+	}
+	m.logger.Infof("Build account index from=%d to=%d", from, height)
+	for h := from; h <= height; h++ {
+		blk, err := m.getBlockByHeight(h)
 		if err != nil {
 			return err
 		}
-		if err = chainProp.set(raw(keyLastBlockHeight), block.Height()); err != nil {
+		var prev module.Block
+		if h > from || indexed >= 0 {
+			if prev, err = m.getBlockByHeight(h - 1); err != nil {
+				return err
+			}
+		}
+		ldb := db.NewLayerDB(m.db())
+		if err := m.indexAccounts(ldb, prev, blk); err != nil {
+			ldb.Flush(false)
+			if indexed < 0 {
+				m.logger.Warnf("Skip block for account index height=%d err=%v", h, err)
+				continue
+			}
+			return errors.InvalidStateError.Wrapf(err,
+				"fail to build account index height=%d", h)
+		}
+		if err := ldb.Flush(true); err != nil {
 			return err
 		}
+		indexed = h
 	}
-	m.logger.Debugf("Finalize(%x)\n", block.ID())
-	for i := 0; i < len(m.finalizationCBs); {
-		cb := m.finalizationCBs[i]
-		if cb(block) {
-			last := len(m.finalizationCBs) - 1
-			m.finalizationCBs[i] = m.finalizationCBs[last]
-			m.finalizationCBs[last] = nil
-			m.finalizationCBs = m.finalizationCBs[:last]
-			continue
+	return nil
+}
+
+func (m *manager) GetTransactionCountByAddress(addr module.Address) (int64, error) {
+	m.syncer.begin()
+	defer m.syncer.end()
+
+	if m.accountIndex == nil {
+		return 0, errors.UnsupportedError.New("AccountIndexDisabled")
+	}
+	return m.accountIndex.count(addr)
+}
+
+func (m *manager) GetTransactionsByAddress(addr module.Address, start int64, limit int) ([][]byte, error) {
+	m.syncer.begin()
+	defer m.syncer.end()
+
+	if m.accountIndex == nil {
+		return nil, errors.UnsupportedError.New("AccountIndexDisabled")
+	}
+	return m.accountIndex.get(addr, start, limit)
+}
+
+func (m *manager) GetBlockProof(height, atHeight int64) (int64, [][]byte, error) {
+	m.syncer.begin()
+	defer m.syncer.end()
+
+	if m.finalized == nil {
+		return 0, nil, errors.InvalidStateError.New("NoFinalizedBlock")
+	}
+	if height < 0 || height > atHeight {
+		return 0, nil, errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(height=%d,at=%d)", height, atHeight)
+	}
+	if last := m.finalized.block.Height(); atHeight > last {
+		return 0, nil, errors.NotFoundError.Errorf(
+			"NotFinalized(at=%d,last=%d)", atHeight, last)
+	}
+	bm, err := newBlockMTA(m.db())
+	if err != nil {
+		return 0, nil, err
+	}
+	return bm.witnessFor(height, atHeight)
+}
+
+// initBlockMTA builds the accumulator of block IDs up to the height if it's
+// not built yet. It happens on the database written before the accumulator
+// is introduced, or on the database imported from pruned genesis. It resumes
+// from the last height of the accumulator, and the new accumulator starts
+// from the oldest block available in the database.
+func (m *manager) initBlockMTA(height int64) error {
+	bm, err := newBlockMTA(m.db())
+	if err != nil {
+		return err
+	}
+	if bm.has(height) {
+		return nil
+	}
+	hb, err := m.bucketFor(db.BlockHeaderHashByHeight)
+	if err != nil {
+		return err
+	}
+	from := height
+	for from > genesisHeight {
+		if bm.has(from - 1) {
+			break
+		}
+		if _, err := hb.getBytes(from - 1); err != nil {
+			break
+		}
+		from--
+	}
+	m.logger.Infof("Build block MTA from=%d to=%d", from, height)
+	for h := from; h <= height; h++ {
+		id, err := hb.getBytes(h)
+		if err != nil {
+			return errors.InvalidStateError.Wrapf(err, "NoBlockID(height=%d)", h)
+		}
+		if err := bm.add(h, id); err != nil {
+			return err
 		}
-		i++
 	}
 	return nil
 }
@@ -1426,6 +1654,9 @@ func (m *manager) WaitForBlock(height int64) (<-chan module.Block, error) {
 		return bch, nil
 	} else if !errors.NotFoundError.Equals(err) {
 		return nil, err
+	} else if m.finalized != nil && height <= m.finalized.block.Height() {
+		// the block is removed by the pruner
+		return nil, err
 	}
 
 	m.finalizationCBs = append(m.finalizationCBs, func(blk module.Block) bool {
@@ -1655,3 +1886,58 @@ func GetLastHeightOf(dbase db.Database) int64 {
 	}
 	return height
 }
+
+// RemoveTransactionLocators removes locators of the transactions in the
+// finalized block from the database.
+func RemoveTransactionLocators(dbase db.Database, blk module.Block) error {
+	lb, err := bucketOf(dbase, db.TransactionLocatorByHash)
+	if err != nil {
+		return err
+	}
+	for _, txs := range []module.TransactionList{
+		blk.PatchTransactions(), blk.NormalTransactions(),
+	} {
+		for it := txs.Iterator(); it.Has(); it.Next() {
+			tx, _, err := it.Get()
+			if err != nil {
+				return err
+			}
+			if err := lb.delete(raw(tx.ID())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RemoveBlock removes the header, the votes and the height index of the
+// finalized block from the database. Votes are not shared, because they
+// have the ID of the voted block. Validators are kept, because they are
+// shared by other blocks.
+func RemoveBlock(dbase db.Database, blk module.Block) error {
+	hb, err := bucketOf(dbase, db.BytesByHash)
+	if err != nil {
+		return err
+	}
+	if err := hb.delete(raw(blk.ID())); err != nil {
+		return err
+	}
+	if err := hb.delete(raw(blk.Votes().Hash())); err != nil {
+		return err
+	}
+	b, err := bucketOf(dbase, db.BlockHeaderHashByHeight)
+	if err != nil {
+		return err
+	}
+	return b.delete(blk.Height())
+}
+
+// GetBlockIDOf returns ID of the finalized block at the height in the
+// database.
+func GetBlockIDOf(dbase db.Database, height int64) ([]byte, error) {
+	hb, err := bucketOf(dbase, db.BlockHeaderHashByHeight)
+	if err != nil {
+		return nil, err
+	}
+	return hb.getBytes(height)
+}
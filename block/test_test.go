@@ -43,6 +43,10 @@ func (c *testChain) MaxWaitTimeout() time.Duration {
 	return 0
 }
 
+func (c *testChain) AccountIndex() bool {
+	return false
+}
+
 func (c *testChain) Database() db.Database {
 	return c.database
 }
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Block archive is a gzip compressed stream of records. Each record is
+// encoded with codec.BC and prefixed with its length as 4 bytes big endian
+// integer. The first record is ArchiveHeader, and ArchiveRecord for each
+// block follows in order of height.
+const (
+	archiveMagic         = "goloop-blocks"
+	archiveVersion       = 1
+	archiveMaxRecordSize = 64 * 1024 * 1024
+)
+
+type ArchiveHeader struct {
+	Magic    string
+	Version  int
+	NID      int
+	From     int64
+	To       int64
+	Receipts bool
+}
+
+// ArchiveRecord has a block with votes for it. Receipts has receipts of
+// normal transactions in the block if the archive includes them.
+type ArchiveRecord struct {
+	Block    []byte
+	Votes    []byte
+	Receipts [][]byte
+}
+
+type archiveWriter struct {
+	gz *gzip.Writer
+	bw *bufio.Writer
+}
+
+func newArchiveWriter(w io.Writer) *archiveWriter {
+	gz := gzip.NewWriter(w)
+	return &archiveWriter{
+		gz: gz,
+		bw: bufio.NewWriter(gz),
+	}
+}
+
+func (w *archiveWriter) Write(v interface{}) error {
+	bs, err := codec.BC.MarshalToBytes(v)
+	if err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(bs)))
+	if _, err := w.bw.Write(size[:]); err != nil {
+		return err
+	}
+	_, err = w.bw.Write(bs)
+	return err
+}
+
+func (w *archiveWriter) Close() error {
+	if err := w.bw.Flush(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+type archiveReader struct {
+	gz *gzip.Reader
+	br *bufio.Reader
+}
+
+func newArchiveReader(r io.Reader) (*archiveReader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidArchive")
+	}
+	return &archiveReader{
+		gz: gz,
+		br: bufio.NewReader(gz),
+	}, nil
+}
+
+// Read reads a record. It returns io.EOF if there is no more records.
+func (r *archiveReader) Read(v interface{}) error {
+	var size [4]byte
+	if _, err := io.ReadFull(r.br, size[:]); err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return errors.IllegalArgumentError.Wrap(err, "InvalidArchive")
+	}
+	sz := binary.BigEndian.Uint32(size[:])
+	if sz > archiveMaxRecordSize {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidArchive(size=%d)", sz)
+	}
+	bs := make([]byte, sz)
+	if _, err := io.ReadFull(r.br, bs); err != nil {
+		return errors.IllegalArgumentError.Wrap(err, "InvalidArchive")
+	}
+	if _, err := codec.BC.UnmarshalFromBytes(bs, v); err != nil {
+		return errors.IllegalArgumentError.Wrap(err, "InvalidArchive")
+	}
+	return nil
+}
+
+func (r *archiveReader) ReadHeader() (*ArchiveHeader, error) {
+	h := new(ArchiveHeader)
+	if err := r.Read(h); err != nil {
+		if err == io.EOF {
+			return nil, errors.IllegalArgumentError.New("EmptyArchive")
+		}
+		return nil, err
+	}
+	if h.Magic != archiveMagic || h.Version != archiveVersion {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"UnknownArchive(magic=%q,version=%d)", h.Magic, h.Version)
+	}
+	return h, nil
+}
+
+func (r *archiveReader) Close() error {
+	return r.gz.Close()
+}
@@ -0,0 +1,346 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// BackupIncrementFile is the name of the entry in an incremental backup,
+// which has the database entries added, changed or deleted since the base.
+const BackupIncrementFile = "increment"
+
+// BackupRef refers to a backup file which an incremental backup depends on.
+type BackupRef struct {
+	Name     string          `json:"name"`
+	Height   int64           `json:"height"`
+	Checksum common.HexBytes `json:"checksum"`
+}
+
+// IsIncremental returns whether the backup is an incremental backup.
+func (info *BackupInfo) IsIncremental() bool {
+	return len(info.Base) > 0
+}
+
+// ChecksumOfFile returns SHA-256 hash of the file.
+func ChecksumOfFile(f string) ([]byte, error) {
+	fd, err := os.Open(f)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, fd); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// BackupRefsOf returns the manifest of backups for an incremental backup
+// based on the backup file. It's the manifest of the base followed by
+// the base itself.
+func BackupRefsOf(file string) ([]BackupRef, *BackupInfo, error) {
+	info, err := GetBackupInfoOf(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	sum, err := ChecksumOfFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs := make([]BackupRef, 0, len(info.Base)+1)
+	refs = append(refs, info.Base...)
+	refs = append(refs, BackupRef{
+		Name:     path.Base(file),
+		Height:   info.Height,
+		Checksum: sum,
+	})
+	return refs, info, nil
+}
+
+// VerifyBackupRefs checks the backup files in the directory against
+// the manifest.
+func VerifyBackupRefs(dir string, refs []BackupRef) error {
+	for _, ref := range refs {
+		sum, err := ChecksumOfFile(path.Join(dir, ref.Name))
+		if err != nil {
+			return errors.NotFoundError.Wrapf(err,
+				"BaseBackupNotFound(name=%s)", ref.Name)
+		}
+		if !bytes.Equal(sum, ref.Checksum) {
+			return errors.InvalidStateError.Errorf(
+				"ChecksumMismatch(name=%s,exp=%s,real=%#x)",
+				ref.Name, ref.Checksum, sum)
+		}
+	}
+	return nil
+}
+
+// ExtractZipFile extracts the file under the directory. If overwrite is
+// true, it replaces existing file.
+func ExtractZipFile(file *zip.File, dir string, overwrite bool) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	target := path.Join(dir, file.Name)
+	mode := file.Mode()
+	if mode.IsDir() {
+		return os.MkdirAll(target, mode.Perm())
+	}
+
+	if !file.Mode().IsRegular() {
+		return nil
+	}
+
+	if err := os.MkdirAll(path.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	flag := os.O_CREATE | os.O_RDWR | os.O_TRUNC
+	if !overwrite {
+		flag |= os.O_EXCL
+	}
+	fd, err := os.OpenFile(target, flag, mode.Perm())
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	_, err = io.Copy(fd, rc)
+
+	return err
+}
+
+func databaseNameOf(nid int) string {
+	return strconv.FormatInt(int64(nid), 16)
+}
+
+func openDumper(dbDir, dbType, name string) (db.Database, db.Dumper, error) {
+	database, err := db.Open(dbDir, dbType, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	dumper, ok := database.(db.Dumper)
+	if !ok {
+		database.Close()
+		return nil, nil, errors.UnsupportedError.Errorf(
+			"IncrementalBackupNotSupported(type=%s)", dbType)
+	}
+	return database, dumper, nil
+}
+
+// backupEntry is an entry of the increment. Value is nil for the entry
+// deleted since the base.
+type backupEntry struct {
+	Key   []byte
+	Value []byte
+}
+
+// writeBackupIncrement writes entries of the database, which are not in
+// the base database or different from the base, followed by tombstones for
+// the entries of the base deleted from the database. It returns number of
+// entries written.
+func writeBackupIncrement(w io.Writer, database, base db.Dumper) (int, error) {
+	bw := bufio.NewWriter(w)
+	enc := codec.BC.NewEncoder(bw)
+	cnt := 0
+	err := database.Dump(func(key, value []byte) error {
+		if old, err := base.GetRaw(key); err != nil {
+			return err
+		} else if old != nil && bytes.Equal(old, value) {
+			return nil
+		}
+		cnt += 1
+		return enc.Encode(&backupEntry{key, value})
+	})
+	if err != nil {
+		return 0, err
+	}
+	err = base.Dump(func(key, value []byte) error {
+		if v, err := database.GetRaw(key); err != nil || v != nil {
+			return err
+		}
+		cnt += 1
+		return enc.Encode(&backupEntry{Key: key})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return cnt, bw.Flush()
+}
+
+func applyBackupIncrement(r io.Reader, database db.Dumper) error {
+	dec := codec.BC.NewDecoder(bufio.NewReader(r))
+	for {
+		var e backupEntry
+		if err := dec.Decode(&e); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "InvalidIncrement")
+		}
+		var err error
+		if e.Value == nil {
+			err = database.DeleteRaw(e.Key)
+		} else {
+			err = database.SetRaw(e.Key, e.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// checkPrunedSince returns an error if the pruner removed data of the
+// database since the base.
+func checkPrunedSince(database, base db.Database) error {
+	for _, key := range []string{keyPrunedState, keyPrunedReceipts} {
+		height, err := prunedHeightOf(database, key)
+		if err != nil {
+			return err
+		}
+		baseHeight, err := prunedHeightOf(base, key)
+		if err != nil {
+			return err
+		}
+		if height != baseHeight {
+			return errors.InvalidStateError.Errorf(
+				"PrunedSinceBase(key=%s,height=%d,base=%d)",
+				key, height, baseHeight)
+		}
+	}
+	return nil
+}
+
+// ApplyBackupIncrement applies database entries in the incremental backup
+// to the database in the chain directory.
+func ApplyBackupIncrement(zr *zip.Reader, chainDir string, cfg *Config) error {
+	return applyBackupIncrementTo(zr, chainDir, cfg.DBType, databaseNameOf(cfg.NID))
+}
+
+func applyBackupIncrementTo(zr *zip.Reader, chainDir, dbType, dbName string) error {
+	var entry *zip.File
+	for _, f := range zr.File {
+		if f.Name == BackupIncrementFile {
+			entry = f
+			break
+		}
+	}
+	if entry == nil {
+		return errors.IllegalArgumentError.New("NoIncrementEntry")
+	}
+	rc, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	database, dumper, err := openDumper(path.Join(chainDir, DefaultDBDir),
+		dbType, dbName)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	return applyBackupIncrement(rc, dumper)
+}
+
+// restoreBackupDatabase restores the database of the backups in the manifest
+// under the directory.
+func restoreBackupDatabase(backupDir string, refs []BackupRef, chainDir string, dbType, dbName string) error {
+	for idx, ref := range refs {
+		err := func() error {
+			zr, err := zip.OpenReader(path.Join(backupDir, ref.Name))
+			if err != nil {
+				return err
+			}
+			defer zr.Close()
+
+			if idx == 0 {
+				for _, f := range zr.File {
+					if strings.HasPrefix(f.Name, DefaultDBDir+"/") {
+						if err := ExtractZipFile(f, chainDir, false); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			}
+			return applyBackupIncrementTo(&zr.Reader, chainDir, dbType, dbName)
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filesOfBackups returns names of the files in the backups of the manifest.
+func filesOfBackups(backupDir string, refs []BackupRef) (map[string]bool, error) {
+	names := make(map[string]bool)
+	for _, ref := range refs {
+		zr, err := zip.OpenReader(path.Join(backupDir, ref.Name))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			names[f.Name] = true
+		}
+		zr.Close()
+	}
+	return names, nil
+}
+
+// listFiles returns names of regular files under the name in the directory.
+func listFiles(dir, name string) ([]string, error) {
+	var names []string
+	root := path.Join(dir, name)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return names, err
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/service"
 	"github.com/icon-project/goloop/service/eeproxy"
+	ssync "github.com/icon-project/goloop/service/sync"
 )
 
 type State int
@@ -99,6 +100,18 @@ type singleChain struct {
 	task       chainTask
 	termWaiter *sync.Cond
 
+	// dbMtx protects the database from being released while it's used by
+	// online backup.
+	dbMtx        sync.RWMutex
+	onlineBackup *taskBackup
+
+	// tracker tracks writes on the database for the pruner. It's nil if
+	// the chain doesn't prune.
+	tracker *writeTracker
+
+	// stateMtx serializes removals of the pruner and the snapshotter.
+	stateMtx sync.Mutex
+
 	// monitor
 	metricCtx context.Context
 }
@@ -109,6 +122,7 @@ const (
 	DefaultContractDir = "contract"
 	DefaultCacheDir    = "cache"
 	DefaultTmpDBDir    = "tmp"
+	DefaultPrunerDir   = "pruner"
 )
 
 func (c *singleChain) Database() db.Database {
@@ -204,6 +218,54 @@ func (c *singleChain) MaxBlockTxBytes() int {
 	return ConfigDefaultMaxBlockTxBytes
 }
 
+func (c *singleChain) AccountIndex() bool {
+	return c.cfg.AccountIndex
+}
+
+func (c *singleChain) MaxLogsRange() int {
+	if c.cfg.MaxLogsRange > 0 {
+		return c.cfg.MaxLogsRange
+	}
+	return ConfigDefaultMaxLogsBlockRange
+}
+
+// PrunedStateHeight returns the lowest height whose world state is not
+// pruned yet.
+func (c *singleChain) PrunedStateHeight() (int64, error) {
+	return prunedHeightOf(c.database, keyPrunedState)
+}
+
+func (c *singleChain) MaxLogsResult() int {
+	if c.cfg.MaxLogsResult > 0 {
+		return c.cfg.MaxLogsResult
+	}
+	return ConfigDefaultMaxLogsResult
+}
+
+func (c *singleChain) TxPoolMaxPerSender() int {
+	return c.cfg.TxPoolMaxPerSender
+}
+
+func (c *singleChain) TxPoolAllowList() []module.Address {
+	addrs, err := ParseAddressList(c.cfg.TxPoolAllowList)
+	if err != nil {
+		c.logger.Warnf("Ignore invalid tx_pool_allow_list err=%+v", err)
+	}
+	return addrs
+}
+
+func (c *singleChain) TxPoolDenyList() []module.Address {
+	addrs, err := ParseAddressList(c.cfg.TxPoolDenyList)
+	if err != nil {
+		c.logger.Warnf("Ignore invalid tx_pool_deny_list err=%+v", err)
+	}
+	return addrs
+}
+
+func (c *singleChain) TxRateLimit() int {
+	return c.cfg.TxRateLimit
+}
+
 func (c *singleChain) DefaultWaitTimeout() time.Duration {
 	if c.cfg.DefWaitTimeout > 0 {
 		return time.Duration(c.cfg.DefWaitTimeout) * time.Millisecond
@@ -331,7 +393,7 @@ func (c *singleChain) openDatabase(dbDir, dbType string) (db.Database, error) {
 			return nil, errors.Wrapf(err, "fail to make directory dir=%s", dbDir)
 		}
 	}
-	DBName := strconv.FormatInt(int64(c.cfg.NID), 16)
+	DBName := databaseNameOf(c.cfg.NID)
 	if cdb, err := db.Open(dbDir, dbType, DBName); err != nil {
 		return nil, errors.Wrapf(err,
 			"fail to open database dir=%s type=%s name=%s", dbDir, c.cfg.DBType, DBName)
@@ -370,6 +432,14 @@ func (c *singleChain) prepareDatabase(chainDir string) error {
 		cdb.Close()
 		return errors.Errorf("Unknown cache strategy:%s", c.cfg.NodeCache)
 	}
+	c.dbMtx.Lock()
+	defer c.dbMtx.Unlock()
+	if c.cfg.StateRetention > 0 || c.cfg.ReceiptRetention > 0 || c.cfg.BlockRetention > 0 {
+		c.tracker = newWriteTracker(cdb)
+		cdb = c.tracker
+	} else {
+		c.tracker = nil
+	}
 	if mLevel > 0 || fLevel > 0 {
 		cacheDir := path.Join(chainDir, DefaultCacheDir)
 		c.database = cache.AttachManager(cdb, cacheDir, mLevel, fLevel)
@@ -379,7 +449,41 @@ func (c *singleChain) prepareDatabase(chainDir string) error {
 	return nil
 }
 
+// snapshotDatabase writes the snapshot of the database under the directory
+// with the same layout as the chain directory, and returns the last height
+// of the snapshot.
+func (c *singleChain) snapshotDatabase(dir string) (int64, error) {
+	c.dbMtx.RLock()
+	defer c.dbMtx.RUnlock()
+
+	if c.database == nil {
+		return 0, errors.InvalidStateError.New("DatabaseNotOpened")
+	}
+	s, ok := c.database.(db.Snapshotter)
+	if !ok {
+		return 0, errors.UnsupportedError.Errorf(
+			"SnapshotNotSupported(type=%s)", c.cfg.DBType)
+	}
+	DBDir := path.Join(dir, DefaultDBDir)
+	if err := os.MkdirAll(DBDir, 0700); err != nil {
+		return 0, errors.Wrapf(err, "fail to make directory dir=%s", DBDir)
+	}
+	DBName := databaseNameOf(c.cfg.NID)
+	if err := s.Snapshot(DBDir, DBName); err != nil {
+		return 0, errors.Wrapf(err, "fail to make snapshot dir=%s", DBDir)
+	}
+
+	sdb, err := db.Open(DBDir, c.cfg.DBType, DBName)
+	if err != nil {
+		return 0, err
+	}
+	defer sdb.Close()
+	return block.GetLastHeightOf(sdb), nil
+}
+
 func (c *singleChain) releaseDatabase() {
+	c.dbMtx.Lock()
+	defer c.dbMtx.Unlock()
 	if c.database != nil {
 		c.database.Close()
 		c.database = nil
@@ -413,9 +517,18 @@ func (c *singleChain) _init() error {
 	return nil
 }
 
-func (c *singleChain) prepareManagers() error {
+// newNetworkManager returns the network manager configured with peers
+// in the chain configuration.
+func (c *singleChain) newNetworkManager() module.NetworkManager {
 	pr := network.PeerRoleFlag(c.cfg.Role)
-	c.nm = network.NewManager(c, c.nt, c.cfg.SeedAddr, pr.ToRoles()...)
+	nm := network.NewManager(c, c.nt, c.cfg.SeedAddr, pr.ToRoles()...)
+	nm.SetStaticPeers(c.cfg.StaticPeers)
+	nm.SetPeerAllowList(c.cfg.AllowedPeers)
+	return nm
+}
+
+func (c *singleChain) prepareManagers() error {
+	c.nm = c.newNetworkManager()
 
 	chainDir := c.cfg.AbsBaseDir()
 	ContractDir := path.Join(chainDir, DefaultContractDir)
@@ -425,6 +538,8 @@ func (c *singleChain) prepareManagers() error {
 	if err != nil {
 		return err
 	}
+	service.SetSnapshotStore(c.sm,
+		ssync.NewSnapshotDir(path.Join(chainDir, DefaultSnapshotDir)))
 	c.bm, err = block.NewManager(c, ts)
 	if err != nil {
 		return err
@@ -535,6 +650,21 @@ func (c *singleChain) Import(src string, height int64) error {
 	return c._runTask(task, false)
 }
 
+func (c *singleChain) ImportArchive(file string) error {
+	task := newTaskImportArchive(c, file)
+	return c._runTask(task, false)
+}
+
+func (c *singleChain) ImportSnapshot(file string) error {
+	task := newTaskImportSnapshot(c, file)
+	return c._runTask(task, false)
+}
+
+func (c *singleChain) Export(file string, from, to int64, receipts bool) error {
+	task := newTaskExport(c, file, from, to, receipts)
+	return c._runTask(task, false)
+}
+
 func (c *singleChain) Prune(gsfile string, dbtype string, height int64) error {
 	if dbtype == "" {
 		dbtype = c.cfg.DBType
@@ -543,11 +673,44 @@ func (c *singleChain) Prune(gsfile string, dbtype string, height int64) error {
 	return c._runTask(task, false)
 }
 
-func (c *singleChain) Backup(file string, extra []string) error {
-	task := newTaskBackup(c, file, extra)
+func (c *singleChain) Backup(file string, extra []string, online bool, base string) error {
+	task := newTaskBackup(c, file, extra, online, base)
+	if task.online {
+		return c._runOnlineBackup(task)
+	}
 	return c._runTask(task, false)
 }
 
+// _runOnlineBackup runs the backup task besides the current task of the chain.
+func (c *singleChain) _runOnlineBackup(task *taskBackup) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	switch c.state {
+	case Created, Initializing, InitializeFailed, Terminating, Terminated:
+		return errors.InvalidStateError.Errorf("InvalidState(state=%s)", c.state.String())
+	}
+	if c.onlineBackup != nil {
+		return errors.InvalidStateError.Errorf(
+			"AlreadyRunning(task=%s)", c.onlineBackup.String())
+	}
+	if err := task.Start(); err != nil {
+		c.logger.Infof("Fail to start %s err=%v", task.String(), err)
+		return err
+	}
+	c.logger.Infof("STARTED %s", task.String())
+	c.onlineBackup = task
+	go func() {
+		err := task.Wait()
+		c.logger.Infof("DONE %s err=%v", task.String(), err)
+
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		c.onlineBackup = nil
+	}()
+	return nil
+}
+
 func (c *singleChain) _handleTerminateInLock() {
 	if c.state != Terminating {
 		c.logger.Panicf("InvalidStateForTerminate(state=%s)", c.state.String())
@@ -560,6 +723,9 @@ func (c *singleChain) _handleTerminateInLock() {
 }
 
 func (c *singleChain) _terminate() {
+	if c.onlineBackup != nil {
+		c.onlineBackup.Stop()
+	}
 	c.releaseDatabase()
 }
 
@@ -583,6 +749,19 @@ func (c *singleChain) Term() error {
 	return nil
 }
 
+func (c *singleChain) FindOrphans(limit int) (int64, [][]byte, error) {
+	task := newTaskFindOrphans(c, limit)
+	if err := c._runTask(task, true); err != nil {
+		return 0, nil, err
+	}
+	return task.count, task.orphans, nil
+}
+
+func (c *singleChain) Check(file string, repair bool) error {
+	task := newTaskCheck(c, file, repair)
+	return c._runTask(task, false)
+}
+
 func (c *singleChain) Verify() error {
 	return errors.UnsupportedError.New("UnsupportedFeatureVerify")
 }
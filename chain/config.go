@@ -5,15 +5,20 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 )
 
 const (
-	ConfigDefaultNormalTxPoolSize = 5000
-	ConfigDefaultPatchTxPoolSize  = 1000
-	ConfigDefaultMaxBlockTxBytes  = 1024 * 1024
+	ConfigDefaultNormalTxPoolSize  = 5000
+	ConfigDefaultPatchTxPoolSize   = 1000
+	ConfigDefaultMaxBlockTxBytes   = 1024 * 1024
+	ConfigDefaultMaxLogsBlockRange = 1000
+	ConfigDefaultMaxLogsResult     = 1000
 )
 
 const (
@@ -34,6 +39,8 @@ type Config struct {
 
 	// static
 	SeedAddr         string `json:"seed_addr"`
+	StaticPeers      string `json:"static_peers,omitempty"`
+	AllowedPeers     string `json:"allowed_peers,omitempty"`
 	Role             uint   `json:"role"`
 	ConcurrencyLevel int    `json:"concurrency_level,omitempty"`
 	NormalTxPoolSize int    `json:"normal_tx_pool,omitempty"`
@@ -41,6 +48,19 @@ type Config struct {
 	MaxBlockTxBytes  int    `json:"max_block_tx_bytes,omitempty"`
 	NodeCache        string `json:"node_cache,omitempty"`
 	AutoStart        bool   `json:"auto_start,omitempty"`
+	AccountIndex     bool   `json:"account_index,omitempty"`
+	MaxLogsRange     int    `json:"max_logs_range,omitempty"`
+	MaxLogsResult    int    `json:"max_logs_result,omitempty"`
+
+	TxPoolMaxPerSender int    `json:"tx_pool_max_per_sender,omitempty"`
+	TxPoolAllowList    string `json:"tx_pool_allow_list,omitempty"`
+	TxPoolDenyList     string `json:"tx_pool_deny_list,omitempty"`
+	TxRateLimit        int    `json:"tx_rate_limit,omitempty"`
+
+	StateRetention   int64 `json:"state_retention,omitempty"`
+	ReceiptRetention int64 `json:"receipt_retention,omitempty"`
+	BlockRetention   int64 `json:"block_retention,omitempty"`
+	SnapshotInterval int64 `json:"snapshot_interval,omitempty"`
 
 	// runtime
 	Channel        string `json:"channel"`
@@ -101,6 +121,23 @@ func (c *Config) GetChannel() string {
 	return GetChannel(c.Channel, c.NID)
 }
 
+// ParseAddressList parses comma separated addresses.
+func ParseAddressList(s string) ([]module.Address, error) {
+	var addrs []module.Address
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if len(v) == 0 {
+			continue
+		}
+		addr := new(common.Address)
+		if err := addr.SetString(v); err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidAddress(%s)", v)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
 func GetChannel(channel string, nid int) string {
 	if channel == "" {
 		return strconv.FormatInt(int64(nid), 16)
@@ -36,6 +36,10 @@ func (d *writerDatabase) Delete(key []byte) error {
 	panic("unsupported")
 }
 
+func (d *writerDatabase) NewIterator(start, limit []byte) db.Iterator {
+	return db.NewErrorIterator(errors.UnsupportedError.New("GSWriterUnsupportIteration"))
+}
+
 func (d *writerDatabase) GetBucket(id db.BucketID) (db.Bucket, error) {
 	if id == db.BytesByHash || id == db.MerkleTrie {
 		return d, nil
@@ -47,6 +51,10 @@ func (d *writerDatabase) Close() error {
 	return nil
 }
 
+func (d *writerDatabase) NewBatch() db.Batch {
+	return db.NewBucketBatch(d)
+}
+
 func NewDatabaseWithWriter(w module.GenesisStorageWriter) db.Database {
 	return &writerDatabase{w}
 }
@@ -60,6 +68,10 @@ func (d *readerDatabase) Close() error {
 	return nil
 }
 
+func (d *readerDatabase) NewBatch() db.Batch {
+	return db.NewBucketBatch(d)
+}
+
 func (d *readerDatabase) Get(key []byte) ([]byte, error) {
 	return d.s.Get(key)
 }
@@ -77,6 +89,10 @@ func (d *readerDatabase) Delete(key []byte) error {
 	return errors.UnsupportedError.Errorf("GenesisStorageIsReadOnly")
 }
 
+func (d *readerDatabase) NewIterator(start, limit []byte) db.Iterator {
+	return db.NewErrorIterator(errors.UnsupportedError.New("GenesisStorageUnsupportIteration"))
+}
+
 func (d *readerDatabase) GetBucket(id db.BucketID) (db.Bucket, error) {
 	if id == db.BytesByHash || id == db.MerkleTrie {
 		return d, nil
@@ -0,0 +1,775 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+)
+
+const (
+	// MinRetention is the minimum number of blocks to keep world states,
+	// receipts or blocks for.
+	MinRetention = 16
+
+	pruneBatchSize     = 1024
+	pruneMinBlocks     = 100
+	pruneCheckInterval = 10 * time.Second
+	pruneWaitInterval  = time.Second
+
+	keyPrunedState    = "pruner.state"
+	keyPrunedReceipts = "pruner.receipts"
+	keyPrunedBlocks   = "pruner.blocks"
+	keyKeptMarks      = "pruner.kept"
+)
+
+// writeTracker records keys of merkle trie nodes written while it's
+// tracking. The pruner uses it to avoid removing nodes written again by
+// the blocks processed during the pruning.
+type writeTracker struct {
+	db.Database
+
+	lock    sync.RWMutex
+	mtx     sync.Mutex
+	written map[string]bool
+}
+
+func (t *writeTracker) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := t.Database.GetBucket(id)
+	if err != nil || id != db.MerkleTrie {
+		return bk, err
+	}
+	return &trackedBucket{Bucket: bk, tracker: t}, nil
+}
+
+func (t *writeTracker) NewBatch() db.Batch {
+	return &trackedBatch{Batch: t.Database.NewBatch(), tracker: t}
+}
+
+func (t *writeTracker) Snapshot(dir, name string) error {
+	if s, ok := t.Database.(db.Snapshotter); ok {
+		return s.Snapshot(dir, name)
+	}
+	return errors.ErrUnsupported
+}
+
+func (t *writeTracker) begin() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.written = make(map[string]bool)
+}
+
+func (t *writeTracker) end() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.written = nil
+}
+
+func (t *writeTracker) onWrite(key []byte) {
+	if t.written != nil {
+		t.mtx.Lock()
+		t.written[string(key)] = true
+		t.mtx.Unlock()
+	}
+}
+
+// deleteNodes deletes merkle trie nodes except ones written after begin,
+// and returns number of deleted nodes.
+func (t *writeTracker) deleteNodes(keys [][]byte) (int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	bk, err := t.Database.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return 0, err
+	}
+	cnt := 0
+	for _, key := range keys {
+		if t.written[string(key)] {
+			continue
+		}
+		if err := bk.Delete(key); err != nil {
+			return cnt, err
+		}
+		cnt += 1
+	}
+	return cnt, nil
+}
+
+func newWriteTracker(database db.Database) *writeTracker {
+	return &writeTracker{Database: database}
+}
+
+type trackedBucket struct {
+	db.Bucket
+	tracker *writeTracker
+}
+
+func (b *trackedBucket) Set(key []byte, value []byte) error {
+	b.tracker.lock.RLock()
+	defer b.tracker.lock.RUnlock()
+	b.tracker.onWrite(key)
+	return b.Bucket.Set(key, value)
+}
+
+// trackedBatch records keys of merkle trie nodes in the batch as written
+// when the batch is written.
+type trackedBatch struct {
+	db.Batch
+	tracker *writeTracker
+	keys    [][]byte
+}
+
+func (b *trackedBatch) Set(id db.BucketID, key []byte, value []byte) error {
+	if id == db.MerkleTrie {
+		b.keys = append(b.keys, append([]byte(nil), key...))
+	}
+	return b.Batch.Set(id, key, value)
+}
+
+func (b *trackedBatch) Write() error {
+	b.tracker.lock.RLock()
+	defer b.tracker.lock.RUnlock()
+	for _, key := range b.keys {
+		b.tracker.onWrite(key)
+	}
+	return b.Batch.Write()
+}
+
+// markDatabase is the database for marking nodes reachable from the
+// retained blocks. While sweeping, nodes newly added to it are not
+// reachable from the retained blocks, so they are removed from the chain.
+// Nodes in kept are regarded as marked.
+type markDatabase struct {
+	db.Database
+	kept    db.Database
+	pruner  *pruner
+	sweep   bool
+	pending [][]byte
+}
+
+func (m *markDatabase) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := m.Database.GetBucket(id)
+	if err != nil || id != db.MerkleTrie {
+		return bk, err
+	}
+	mbk := &markBucket{Bucket: bk, database: m}
+	if m.kept != nil {
+		if mbk.kept, err = m.kept.GetBucket(id); err != nil {
+			return nil, err
+		}
+	}
+	return mbk, nil
+}
+
+func (m *markDatabase) NewBatch() db.Batch {
+	return db.NewBucketBatch(m)
+}
+
+func (m *markDatabase) onMark(key []byte) error {
+	if m.pruner.isStopped() {
+		return errors.ErrInterrupted
+	}
+	if !m.sweep {
+		return nil
+	}
+	m.pending = append(m.pending, key)
+	if len(m.pending) >= pruneBatchSize {
+		return m.flush()
+	}
+	return nil
+}
+
+func (m *markDatabase) flush() error {
+	if len(m.pending) == 0 {
+		return nil
+	}
+	m.pruner.chain.stateMtx.Lock()
+	cnt, err := m.pruner.tracker.deleteNodes(m.pending)
+	m.pruner.chain.stateMtx.Unlock()
+	atomic.AddInt64(&m.pruner.removed, int64(cnt))
+	m.pending = m.pending[:0]
+	return err
+}
+
+type markBucket struct {
+	db.Bucket
+	kept     db.Bucket
+	database *markDatabase
+}
+
+func (b *markBucket) Get(key []byte) ([]byte, error) {
+	value, err := b.Bucket.Get(key)
+	if err != nil || value != nil || b.kept == nil {
+		return value, err
+	}
+	return b.kept.Get(key)
+}
+
+func (b *markBucket) Has(key []byte) bool {
+	return b.Bucket.Has(key) || (b.kept != nil && b.kept.Has(key))
+}
+
+func (b *markBucket) Set(key []byte, value []byte) error {
+	if err := b.Bucket.Set(key, value); err != nil {
+		return err
+	}
+	return b.database.onMark(key)
+}
+
+// pruner removes merkle trie nodes of world states, receipts and
+// transaction lists, which are not reachable from the retained blocks. It
+// also removes the blocks older than the block retention.
+type pruner struct {
+	chain   *singleChain
+	tracker *writeTracker
+
+	stop chan struct{}
+	done chan struct{}
+
+	mtx      sync.Mutex
+	target   string
+	from     int64
+	to       int64
+	current  int64
+	removed  int64
+	stopping int32
+}
+
+func (p *pruner) String() string {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.target == "" {
+		return ""
+	}
+	return fmt.Sprintf("prune %s %d/%d removed=%d", p.target,
+		p.to-atomic.LoadInt64(&p.current), p.to-p.from+1,
+		atomic.LoadInt64(&p.removed))
+}
+
+func (p *pruner) setProgress(target string, from, to int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.target = target
+	p.from = from
+	p.to = to
+	atomic.StoreInt64(&p.current, to)
+}
+
+func (p *pruner) isStopped() bool {
+	return atomic.LoadInt32(&p.stopping) != 0
+}
+
+func (p *pruner) Start() {
+	go p.run()
+}
+
+func (p *pruner) Stop() {
+	atomic.StoreInt32(&p.stopping, 1)
+	close(p.stop)
+	<-p.done
+}
+
+func (p *pruner) run() {
+	defer close(p.done)
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(pruneCheckInterval):
+		}
+		if err := p._prune(); err != nil {
+			if errors.InterruptedError.Equals(err) {
+				return
+			}
+			p.chain.logger.Warnf("Fail to prune err=%+v", err)
+		}
+		p.setProgress("", 0, 0)
+	}
+}
+
+func retentionOf(n int64) int64 {
+	if n > 0 && n < MinRetention {
+		return MinRetention
+	}
+	return n
+}
+
+// prunedHeightOf returns the lowest height whose data for the key are not
+// pruned yet.
+func prunedHeightOf(database db.Database, key string) (int64, error) {
+	bk, err := database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return 0, err
+	}
+	bs, err := bk.Get([]byte(key))
+	if err != nil || bs == nil {
+		return 0, err
+	}
+	var height int64
+	_, err = codec.BC.UnmarshalFromBytes(bs, &height)
+	return height, err
+}
+
+func (p *pruner) prunedHeight(key string) (int64, error) {
+	return prunedHeightOf(p.chain.database, key)
+}
+
+func (p *pruner) setPrunedHeight(key string, height int64) error {
+	bk, err := p.chain.database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return bk.Set([]byte(key), codec.BC.MustMarshalToBytes(height))
+}
+
+func (p *pruner) lastHeight() (int64, error) {
+	blk, err := p.chain.bm.GetLastBlock()
+	if err != nil {
+		return 0, err
+	}
+	return blk.Height(), nil
+}
+
+// waitHeight waits until the last block reaches the height.
+func (p *pruner) waitHeight(height int64) error {
+	for {
+		last, err := p.lastHeight()
+		if err != nil {
+			return err
+		}
+		if last >= height {
+			return nil
+		}
+		select {
+		case <-p.stop:
+			return errors.ErrInterrupted
+		case <-time.After(pruneWaitInterval):
+		}
+	}
+}
+
+type copyFunc func(src db.Database, blk module.Block, dst db.Database) error
+
+// copyState copies the world state of the result if it's available.
+// States of the blocks before the state sync are not available.
+func copyState(src db.Database, result []byte, dst db.Database) error {
+	if ok, err := service.HasWorldState(src, result); err != nil || !ok {
+		return err
+	}
+	return service.CopyWorldState(src, result, dst)
+}
+
+// copyReceipts copies the receipt lists of the result if they are available.
+func copyReceipts(src db.Database, result []byte, dst db.Database) error {
+	_, patchHash, normalHash, err := service.ParseResult(result)
+	if err != nil {
+		return err
+	}
+	bk, err := src.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	for _, h := range [][]byte{patchHash, normalHash} {
+		if len(h) > 0 && !bk.Has(h) {
+			return nil
+		}
+	}
+	return service.CopyReceipts(src, result, dst)
+}
+
+func copyStateOf(src db.Database, blk module.Block, dst db.Database) error {
+	return copyState(src, blk.Result(), dst)
+}
+
+func copyReceiptsOf(src db.Database, blk module.Block, dst db.Database) error {
+	return copyReceipts(src, blk.Result(), dst)
+}
+
+// copyRange copies nodes for the blocks in the range to the database
+// from the last.
+func (p *pruner) copyRange(target string, from, to int64, f copyFunc, dst db.Database) error {
+	p.setProgress(target, from, to)
+	for h := to; h >= from; h-- {
+		if p.isStopped() {
+			return errors.ErrInterrupted
+		}
+		blk, err := p.chain.bm.GetBlockByHeight(h)
+		if errors.NotFoundError.Equals(err) {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := f(p.chain.database, blk, dst); err != nil {
+			return err
+		}
+		atomic.StoreInt64(&p.current, h-1)
+	}
+	return nil
+}
+
+func (p *pruner) removeLocators(src db.Database, blk module.Block, _ db.Database) error {
+	return block.RemoveTransactionLocators(src, blk)
+}
+
+// removeBlock removes the transaction lists of the block through dst, and
+// removes the block.
+func (p *pruner) removeBlock(src db.Database, blk module.Block, dst db.Database) error {
+	if err := copyTransactions(src, blk, dst); err != nil {
+		return err
+	}
+	p.chain.stateMtx.Lock()
+	defer p.chain.stateMtx.Unlock()
+	return block.RemoveBlock(src, blk)
+}
+
+// nextRange returns the range of blocks to prune with the retention.
+// It returns false if there are not enough blocks to prune.
+func (p *pruner) nextRange(key string, retention, last int64) (int64, int64, bool, error) {
+	if retention <= 0 {
+		return 0, 0, false, nil
+	}
+	from, err := p.prunedHeight(key)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	to := last - retention
+	return from, to, to-from+1 >= pruneMinBlocks, nil
+}
+
+// pruneTarget is a kind of data for the blocks. Data older than the
+// retention are removed, and all data are kept if the retention is zero.
+type pruneTarget struct {
+	name      string
+	key       string
+	retention int64
+	mark      copyFunc
+	sweep     copyFunc
+
+	from  int64
+	to    int64
+	prune bool
+}
+
+// lowest returns the lowest height of the retained data.
+func (t *pruneTarget) lowest() int64 {
+	if t.prune {
+		return t.to + 1
+	}
+	return t.from
+}
+
+func (p *pruner) targets() []*pruneTarget {
+	cfg := p.chain.cfg
+	sr := retentionOf(cfg.StateRetention)
+	rr := retentionOf(cfg.ReceiptRetention)
+	br := retentionOf(cfg.BlockRetention)
+	// Keep states and blocks for the snapshotter to make the next snapshot.
+	if si := cfg.SnapshotInterval; si > 0 {
+		if sr > 0 && sr < si {
+			sr = si
+		}
+		if br > 0 && br < si {
+			br = si
+		}
+	}
+	// States and receipts of the removed blocks are not reachable.
+	if br > 0 {
+		if sr == 0 || sr > br {
+			sr = br
+		}
+		if rr == 0 || rr > br {
+			rr = br
+		}
+	}
+	return []*pruneTarget{
+		{name: "state", key: keyPrunedState, retention: sr,
+			mark: copyStateOf, sweep: copyStateOf},
+		{name: "receipts", key: keyPrunedReceipts, retention: rr,
+			mark: copyReceiptsOf, sweep: copyReceiptsOf},
+		{name: "blocks", key: keyPrunedBlocks, retention: br,
+			mark: copyTransactions, sweep: p.removeBlock},
+	}
+}
+
+// nextRanges updates ranges of the targets to remove for the last height.
+// It returns false if there is nothing to remove.
+func (p *pruner) nextRanges(targets []*pruneTarget, last int64) (bool, error) {
+	var blocks *pruneTarget
+	for _, t := range targets {
+		from, to, ok, err := p.nextRange(t.key, t.retention, last)
+		if err != nil {
+			return false, err
+		}
+		t.from, t.to, t.prune = from, to, ok
+		if t.key == keyPrunedBlocks {
+			blocks = t
+		}
+	}
+	if blocks != nil && blocks.prune {
+		// The genesis block is required to start the chain.
+		if gh := p.chain.GenesisStorage().Height(); blocks.from <= gh {
+			blocks.from = gh + 1
+			blocks.prune = blocks.to-blocks.from+1 >= pruneMinBlocks
+		}
+	}
+	if blocks != nil && blocks.prune {
+		// States and receipts of the removed blocks are removed together.
+		for _, t := range targets {
+			if t != blocks && t.from <= blocks.to {
+				t.prune = true
+			}
+		}
+	}
+	for _, t := range targets {
+		if t.prune {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// keptMarks is the record of the marks for the kept data. Nodes reachable
+// from the data are marked up to the height.
+type keptMarks struct {
+	Targets []string
+	Height  int64
+	BlockID []byte
+}
+
+func (p *pruner) readKeptMarks(kdb db.Database, names []string) (*keptMarks, error) {
+	bk, err := kdb.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := bk.Get([]byte(keyKeptMarks))
+	if err != nil || bs == nil {
+		return nil, err
+	}
+	rec := new(keptMarks)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, rec); err != nil {
+		return nil, nil
+	}
+	if strings.Join(rec.Targets, ",") != strings.Join(names, ",") {
+		return nil, nil
+	}
+	// The database may be replaced (ex. restored from the backup).
+	blk, err := p.chain.bm.GetBlockByHeight(rec.Height)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(blk.ID(), rec.BlockID) {
+		return nil, nil
+	}
+	return rec, nil
+}
+
+// openKept opens the database of the marks for the kept data, and marks
+// the data of the blocks finalized after the last marking. The marks are
+// kept over the runs, so the kept data are marked only once. It returns nil
+// if there are no kept data.
+func (p *pruner) openKept(targets []*pruneTarget, last int64) (db.Database, error) {
+	dir := path.Join(p.chain.cfg.AbsBaseDir(), DefaultPrunerDir)
+	if len(targets) == 0 {
+		return nil, os.RemoveAll(dir)
+	}
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		names = append(names, t.name)
+	}
+
+	kdb, err := db.Open(dir, string(db.GoLevelDBBackend), "kept")
+	if err != nil {
+		return nil, err
+	}
+	rec, err := p.readKeptMarks(kdb, names)
+	if err != nil {
+		kdb.Close()
+		return nil, err
+	}
+	if rec == nil {
+		kdb.Close()
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+		if kdb, err = db.Open(dir, string(db.GoLevelDBBackend), "kept"); err != nil {
+			return nil, err
+		}
+		rec = &keptMarks{Targets: names, Height: -1}
+	}
+
+	err = p.copyRange("mark kept", rec.Height+1, last,
+		func(src db.Database, blk module.Block, dst db.Database) error {
+			for _, t := range targets {
+				if err := t.mark(src, blk, dst); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, kdb)
+	if err == nil {
+		err = p.writeKeptMarks(kdb, rec, last)
+	}
+	if err != nil {
+		kdb.Close()
+		return nil, err
+	}
+	return kdb, nil
+}
+
+func (p *pruner) writeKeptMarks(kdb db.Database, rec *keptMarks, last int64) error {
+	blk, err := p.chain.bm.GetBlockByHeight(last)
+	if err != nil {
+		return err
+	}
+	rec.Height = last
+	rec.BlockID = blk.ID()
+	bk, err := kdb.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return bk.Set([]byte(keyKeptMarks), codec.BC.MustMarshalToBytes(rec))
+}
+
+func (p *pruner) _prune() error {
+	targets := p.targets()
+	last, err := p.lastHeight()
+	if err != nil {
+		return err
+	}
+	if ok, err := p.nextRanges(targets, last); err != nil || !ok {
+		return err
+	}
+
+	p.tracker.begin()
+	defer p.tracker.end()
+
+	// Nodes for the blocks being processed may be written before the
+	// tracking. They are included in the blocks finalized after next one.
+	if err := p.waitHeight(last + 2); err != nil {
+		return err
+	}
+	if last, err = p.lastHeight(); err != nil {
+		return err
+	}
+	if ok, err := p.nextRanges(targets, last); err != nil || !ok {
+		return err
+	}
+
+	var kept []*pruneTarget
+	for _, t := range targets {
+		if t.retention <= 0 {
+			kept = append(kept, t)
+		}
+	}
+	kdb, err := p.openKept(kept, last)
+	if err != nil {
+		return err
+	}
+	if kdb != nil {
+		defer kdb.Close()
+	}
+
+	dir, err := ioutil.TempDir(p.chain.cfg.AbsBaseDir(), ".pruner")
+	if err != nil {
+		return errors.Wrap(err, "FailToMakeTempDir")
+	}
+	defer os.RemoveAll(dir)
+	mdb, err := db.Open(dir, string(db.GoLevelDBBackend), "mark")
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+	mark := &markDatabase{Database: mdb, kept: kdb, pruner: p}
+
+	// Tries of states, receipts and transactions are in the same bucket, and
+	// they may share nodes. So all retained data are marked even if they are
+	// not removed this time.
+	atomic.StoreInt64(&p.removed, 0)
+	for _, t := range targets {
+		if t.retention > 0 {
+			if err := p.copyRange("mark "+t.name, t.lowest(), last, t.mark, mark); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Pruned heights are updated before sweeping. Interrupted sweeping
+	// may leave some unreachable nodes, but it never breaks the retained
+	// blocks.
+	mark.sweep = true
+	var pruned []string
+	for _, t := range targets {
+		if t.prune {
+			if err := p.setPrunedHeight(t.key, t.to+1); err != nil {
+				return err
+			}
+			pruned = append(pruned, fmt.Sprintf("%s=%d", t.name, t.to))
+		}
+	}
+	for _, t := range targets {
+		if !t.prune {
+			continue
+		}
+		if t.key == keyPrunedBlocks {
+			// Transaction lists are required to remove the locators, so the
+			// locators are removed before sweeping the lists.
+			if err := p.copyRange("locators", t.from, t.to, p.removeLocators, nil); err != nil {
+				return err
+			}
+		}
+		if err := p.copyRange(t.name, t.from, t.to, t.sweep, mark); err != nil {
+			return err
+		}
+	}
+	if err := mark.flush(); err != nil {
+		return err
+	}
+	p.chain.logger.Infof("Pruned %s removed=%d",
+		strings.Join(pruned, " "), atomic.LoadInt64(&p.removed))
+	return nil
+}
+
+// newPruner returns a pruner for the chain. It returns nil if the chain
+// doesn't prune.
+func newPruner(c *singleChain) *pruner {
+	if c.tracker == nil {
+		return nil
+	}
+	return &pruner{
+		chain:   c,
+		tracker: c.tracker,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
@@ -0,0 +1,291 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+	ssync "github.com/icon-project/goloop/service/sync"
+)
+
+const (
+	DefaultSnapshotDir = "snapshot"
+
+	// SnapshotGenesisSuffix is the suffix of the pruned genesis made with
+	// the snapshot. It doesn't have the world state, so the chain joined
+	// with it should import the snapshot before it starts.
+	SnapshotGenesisSuffix = ".zip"
+
+	snapshotKeep          = 2
+	snapshotCheckInterval = 10 * time.Second
+)
+
+// SnapshotGenesisName returns the name of the pruned genesis file made
+// with the snapshot of the height.
+func SnapshotGenesisName(height int64) string {
+	return strconv.FormatInt(height, 10) + SnapshotGenesisSuffix
+}
+
+// stateFilterWriter drops data of the world state from the genesis
+// storage.
+type stateFilterWriter struct {
+	module.GenesisStorageWriter
+	state db.Database
+}
+
+func (w *stateFilterWriter) WriteData(value []byte) ([]byte, error) {
+	key := crypto.SHA3Sum256(value)
+	for _, id := range []db.BucketID{db.MerkleTrie, db.BytesByHash} {
+		bk, err := w.state.GetBucket(id)
+		if err != nil {
+			return nil, err
+		}
+		if bk.Has(key) {
+			return key, nil
+		}
+	}
+	return w.GenesisStorageWriter.WriteData(value)
+}
+
+// snapshotter makes snapshots of the world state every interval blocks in
+// the snapshot directory of the chain.
+type snapshotter struct {
+	chain *singleChain
+	dir   string
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopping int32
+
+	height  int64
+	entries int64
+}
+
+func (s *snapshotter) String() string {
+	height := atomic.LoadInt64(&s.height)
+	if height == 0 {
+		return ""
+	}
+	return fmt.Sprintf("snapshot %d entries=%d",
+		height, atomic.LoadInt64(&s.entries))
+}
+
+func (s *snapshotter) isStopped() bool {
+	return atomic.LoadInt32(&s.stopping) != 0
+}
+
+func (s *snapshotter) Start() {
+	go s.run()
+}
+
+func (s *snapshotter) Stop() {
+	atomic.StoreInt32(&s.stopping, 1)
+	close(s.stop)
+	<-s.done
+}
+
+func (s *snapshotter) run() {
+	defer close(s.done)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(snapshotCheckInterval):
+		}
+		if err := s._snapshot(); err != nil {
+			if errors.InterruptedError.Equals(err) {
+				return
+			}
+			s.chain.logger.Warnf("Fail to make snapshot err=%+v", err)
+		}
+		atomic.StoreInt64(&s.height, 0)
+	}
+}
+
+// heights returns heights of the snapshots in the directory from the
+// latest.
+func (s *snapshotter) heights() ([]int64, error) {
+	fis, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var heights []int64
+	for _, fi := range fis {
+		name := fi.Name()
+		if !fi.Mode().IsRegular() || !strings.HasSuffix(name, ssync.SnapshotFileSuffix) {
+			continue
+		}
+		h, err := strconv.ParseInt(strings.TrimSuffix(name, ssync.SnapshotFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+	return heights, nil
+}
+
+func (s *snapshotter) _snapshot() error {
+	c := s.chain
+	interval := c.cfg.SnapshotInterval
+	last, err := c.bm.GetLastBlock()
+	if err != nil {
+		return err
+	}
+	// The next block is required for the votes of the genesis.
+	height := (last.Height() - 1) / interval * interval
+	if height <= 0 {
+		return nil
+	}
+	heights, err := s.heights()
+	if err != nil {
+		return err
+	}
+	if len(heights) > 0 && heights[0] >= height {
+		return nil
+	}
+
+	c.stateMtx.Lock()
+	defer c.stateMtx.Unlock()
+
+	atomic.StoreInt64(&s.height, height)
+	atomic.StoreInt64(&s.entries, 0)
+	// The pruner doesn't remove them while it holds the lock.
+	for _, key := range []string{keyPrunedState, keyPrunedBlocks} {
+		if pruned, err := prunedHeightOf(c.database, key); err != nil {
+			return err
+		} else if height < pruned {
+			return errors.NotFoundError.Errorf(
+				"Pruned(height=%d,key=%s,pruned=%d)", height, key, pruned)
+		}
+	}
+	blk, err := c.bm.GetBlockByHeight(height)
+	if err != nil {
+		return err
+	}
+	if ok, err := service.HasWorldState(c.database, blk.Result()); err != nil {
+		return err
+	} else if !ok {
+		return errors.NotFoundError.Errorf("NoWorldState(height=%d)", height)
+	}
+	stateHash, _, _, err := service.ParseResult(blk.Result())
+	if err != nil {
+		return err
+	}
+	if err := s._exportGenesis(blk); err != nil {
+		return err
+	}
+	mf := &ssync.SnapshotManifest{
+		NID:       c.NID(),
+		Height:    height,
+		BlockID:   blk.ID(),
+		StateHash: stateHash,
+	}
+	file := path.Join(s.dir, ssync.SnapshotFileName(height))
+	err = ssync.WriteSnapshot(c.database, mf, file, func(entries int) error {
+		if s.isStopped() {
+			return errors.ErrInterrupted
+		}
+		atomic.AddInt64(&s.entries, int64(entries))
+		return nil
+	})
+	if err != nil {
+		os.Remove(path.Join(s.dir, SnapshotGenesisName(height)))
+		return err
+	}
+	c.logger.Infof("Made %s", mf)
+
+	heights = append([]int64{height}, heights...)
+	for _, h := range heights[snapshotKeep:] {
+		os.Remove(path.Join(s.dir, ssync.SnapshotFileName(h)))
+		os.Remove(path.Join(s.dir, SnapshotGenesisName(h)))
+	}
+	return nil
+}
+
+// _exportGenesis writes the pruned genesis of the block without its world
+// state.
+func (s *snapshotter) _exportGenesis(blk module.Block) (rerr error) {
+	tmpDir, err := ioutil.TempDir(s.dir, ".state")
+	if err != nil {
+		return errors.Wrap(err, "FailToMakeTempDir")
+	}
+	defer os.RemoveAll(tmpDir)
+	sdb, err := db.Open(tmpDir, string(db.GoLevelDBBackend), "state")
+	if err != nil {
+		return err
+	}
+	defer sdb.Close()
+	if err := service.CopyWorldState(s.chain.database, blk.Result(), sdb); err != nil {
+		return err
+	}
+
+	file := path.Join(s.dir, SnapshotGenesisName(blk.Height()))
+	tmp := file + ".tmp"
+	fd, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		fd.Close()
+		if rerr != nil {
+			os.Remove(tmp)
+		}
+	}()
+	gsw := gs.NewGenesisStorageWriter(fd)
+	if err := s.chain.bm.ExportGenesis(blk, &stateFilterWriter{gsw, sdb}); err != nil {
+		return errors.Wrap(err, "fail on exporting genesis storage")
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// newSnapshotter returns a snapshotter for the chain. It returns nil if
+// the chain doesn't make snapshots.
+func newSnapshotter(c *singleChain) (*snapshotter, error) {
+	if c.cfg.SnapshotInterval <= 0 {
+		return nil, nil
+	}
+	dir := path.Join(c.cfg.AbsBaseDir(), DefaultSnapshotDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &snapshotter{
+		chain: c,
+		dir:   dir,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}, nil
+}
@@ -40,6 +40,7 @@ type BackupInfo struct {
 	Channel string          `json:"channel"`
 	Height  int64           `json:"height"`
 	Codec   string          `json:"codec"`
+	Base    []BackupRef     `json:"base,omitempty"`
 }
 
 var backupStates = map[State]string{
@@ -53,6 +54,9 @@ type taskBackup struct {
 	chain   *singleChain
 	file    string
 	extra   []string
+	online  bool
+	base    string
+	refs    []BackupRef
 	fd      io.WriteCloser
 	zw      *zip.Writer
 	current int32
@@ -62,6 +66,12 @@ type taskBackup struct {
 }
 
 func (t *taskBackup) String() string {
+	if t.base != "" {
+		return fmt.Sprintf("Backup(file=%s,base=%s)", path.Base(t.file), path.Base(t.base))
+	}
+	if t.online {
+		return fmt.Sprintf("Backup(file=%s,online)", path.Base(t.file))
+	}
 	return fmt.Sprintf("Backup(file=%s)", path.Base(t.file))
 }
 
@@ -84,7 +94,35 @@ func (t *taskBackup) DetailOf(s State) string {
 	}
 }
 
+func (t *taskBackup) backupInfo(height int64) *BackupInfo {
+	return &BackupInfo{
+		NID:     common.HexInt32{Value: int32(t.chain.NID())},
+		CID:     common.HexInt32{Value: int32(t.chain.CID())},
+		Channel: t.chain.Channel(),
+		Height:  height,
+		Codec:   codec.BC.Name(),
+		Base:    t.refs,
+	}
+}
+
 func (t *taskBackup) Start() (ret error) {
+	if t.base != "" {
+		refs, info, err := BackupRefsOf(t.base)
+		if err != nil {
+			return errors.IllegalArgumentError.Wrapf(err,
+				"InvalidBaseBackup(base=%s)", t.base)
+		}
+		if int(info.CID.Value) != t.chain.CID() || info.Codec != codec.BC.Name() {
+			return errors.IllegalArgumentError.Errorf(
+				"IncompatibleBaseBackup(base=%s,cid=%s,codec=%s)",
+				path.Base(t.base), info.CID, info.Codec)
+		}
+		if err := VerifyBackupRefs(path.Dir(t.base), info.Base); err != nil {
+			return err
+		}
+		t.refs = refs
+	}
+
 	tmp, err := ioutil.TempFile(path.Dir(t.file), TemporalBackupFile)
 	if err != nil {
 		return errors.Wrap(err, "Fail to make temporal file")
@@ -102,20 +140,21 @@ func (t *taskBackup) Start() (ret error) {
 	t.fd = tmp
 	t.zw = zip.NewWriter(tmp)
 
-	if err := writeBackupInfo(t.zw, &BackupInfo{
-		NID:     common.HexInt32{Value: int32(t.chain.NID())},
-		CID:     common.HexInt32{Value: int32(t.chain.CID())},
-		Channel: t.chain.Channel(),
-		Height:  t.chain.lastBlockHeight(),
-		Codec:   codec.BC.Name(),
-	}); err != nil {
-		return err
+	// Online backup records the height of the snapshot after it's taken.
+	if !t.online {
+		if err := writeBackupInfo(t.zw, t.backupInfo(t.chain.lastBlockHeight())); err != nil {
+			return err
+		}
+		t.chain.releaseDatabase()
 	}
 
-	t.chain.releaseDatabase()
-
 	go func() {
-		err := t._backup()
+		var err error
+		if t.online {
+			err = t._backupOnline()
+		} else {
+			err = t._backup()
+		}
 		if err == nil {
 			err = os.Rename(tmp.Name(), t.file)
 		}
@@ -231,6 +270,15 @@ func (t *taskBackup) OnWrite(int64) error {
 	return nil
 }
 
+func (t *taskBackup) _writeFiles(dir string, names []string) error {
+	for _, name := range names {
+		if err := zipWrite(t.zw, dir, name, t.OnWrite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (t *taskBackup) _backup() error {
 	defer t.chain.ensureDatabase()
 	defer t.fd.Close()
@@ -247,13 +295,122 @@ func (t *taskBackup) _backup() error {
 		t.total = int32(cnt)
 	}
 
-	for _, name := range names {
-		if err := zipWrite(t.zw, chainDir, name, t.OnWrite); err != nil {
-			return err
+	return t._writeFiles(chainDir, names)
+}
+
+// _backupOnline writes the snapshot of the database instead of the database
+// in use. The consensus WAL is not included because it keeps changing while
+// the chain is running, and the chain can start without it.
+func (t *taskBackup) _backupOnline() error {
+	defer t.fd.Close()
+	defer t.zw.Close()
+
+	snapDir, err := ioutil.TempDir(path.Dir(t.file), TemporalBackupFile)
+	if err != nil {
+		return errors.Wrap(err, "Fail to make temporal directory")
+	}
+	defer os.RemoveAll(snapDir)
+
+	height, err := t.chain.snapshotDatabase(snapDir)
+	if err != nil {
+		return err
+	}
+	if err := writeBackupInfo(t.zw, t.backupInfo(height)); err != nil {
+		return err
+	}
+
+	if len(t.refs) > 0 {
+		return t._writeIncrement(snapDir, height)
+	}
+
+	dbNames := []string{DefaultDBDir}
+	names := append([]string{DefaultContractDir}, t.extra...)
+
+	chainDir := t.chain.cfg.AbsBaseDir()
+	if cnt, err := t._countFiles(snapDir, dbNames); err != nil {
+		return err
+	} else if cnt2, err := t._countFiles(chainDir, names); err != nil {
+		return err
+	} else {
+		atomic.StoreInt32(&t.total, int32(cnt+cnt2))
+	}
+
+	if err := t._writeFiles(snapDir, dbNames); err != nil {
+		return err
+	}
+	return t._writeFiles(chainDir, names)
+}
+
+// _writeIncrement writes database entries of the snapshot, which are
+// added, changed or deleted since the base, and the files added since
+// the base. It fails if the pruner removed data since the base.
+func (t *taskBackup) _writeIncrement(snapDir string, height int64) error {
+	last := t.refs[len(t.refs)-1]
+	if height < last.Height {
+		return errors.InvalidStateError.Errorf(
+			"InvalidHeight(height=%d,base=%d)", height, last.Height)
+	}
+
+	backupDir := path.Dir(t.base)
+	known, err := filesOfBackups(backupDir, t.refs)
+	if err != nil {
+		return err
+	}
+	files, err := listFiles(t.chain.cfg.AbsBaseDir(), DefaultContractDir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, name := range files {
+		if !known[name] {
+			names = append(names, name)
 		}
 	}
+	names = append(names, t.extra...)
+	atomic.StoreInt32(&t.total, int32(len(names)+1))
 
-	return nil
+	baseDir, err := ioutil.TempDir(backupDir, TemporalBackupFile)
+	if err != nil {
+		return errors.Wrap(err, "Fail to make temporal directory")
+	}
+	defer os.RemoveAll(baseDir)
+
+	dbType := t.chain.cfg.DBType
+	dbName := databaseNameOf(t.chain.cfg.NID)
+	if err := restoreBackupDatabase(backupDir, t.refs, baseDir, dbType, dbName); err != nil {
+		return err
+	}
+	baseDB, base, err := openDumper(path.Join(baseDir, DefaultDBDir), dbType, dbName)
+	if err != nil {
+		return err
+	}
+	defer baseDB.Close()
+	snapDB, snap, err := openDumper(path.Join(snapDir, DefaultDBDir), dbType, dbName)
+	if err != nil {
+		return err
+	}
+	defer snapDB.Close()
+	if err := checkPrunedSince(snapDB, baseDB); err != nil {
+		return err
+	}
+
+	zf, err := t.zw.CreateHeader(&zip.FileHeader{
+		Name:   BackupIncrementFile,
+		Method: zip.Deflate,
+	})
+	if err != nil {
+		return err
+	}
+	cnt, err := writeBackupIncrement(zf, snap, base)
+	if err != nil {
+		return err
+	}
+	t.chain.logger.Infof("Backup increment entries=%d height=%d base=%d",
+		cnt, height, last.Height)
+	if err := t.OnWrite(0); err != nil {
+		return err
+	}
+	return t._writeFiles(t.chain.cfg.AbsBaseDir(), names)
 }
 
 func (t *taskBackup) Stop() {
@@ -264,11 +421,13 @@ func (t *taskBackup) Wait() error {
 	return t.result.Wait()
 }
 
-func newTaskBackup(chain *singleChain, file string, extra []string) chainTask {
+func newTaskBackup(chain *singleChain, file string, extra []string, online bool, base string) *taskBackup {
 	return &taskBackup{
-		chain: chain,
-		file:  file,
-		extra: extra,
+		chain:  chain,
+		file:   file,
+		extra:  extra,
+		online: online || base != "",
+		base:   base,
 	}
 }
 
@@ -0,0 +1,433 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+)
+
+var checkStates = map[State]string{
+	Starting: "check starting",
+	Stopping: "check stopping",
+	Failed:   "check failed",
+	Finished: "check done",
+}
+
+const (
+	reasonMissing   = "missing"
+	reasonCorrupted = "corrupted"
+)
+
+// checkIssue is a problem found by the integrity check.
+type checkIssue struct {
+	Height   int64           `json:"height"`
+	Target   string          `json:"target"`
+	Key      common.HexBytes `json:"key,omitempty"`
+	Reason   string          `json:"reason"`
+	Repaired bool            `json:"repaired,omitempty"`
+}
+
+type failedRequest struct {
+	ids    []db.BucketID
+	reason string
+}
+
+// taskCheck verifies blocks of the chain with their world states and
+// receipts. Every node is resolved with merkle.Builder like importing them
+// from the database. It records missing or corrupted nodes instead of
+// failing, so the rest of the chain is checked. With repair, the nodes are
+// fetched from the peers through the state sync protocol.
+type taskCheck struct {
+	chain   *singleChain
+	file    string
+	repair  bool
+	from    int64
+	last    int64
+	current int64
+	stop    chan struct{}
+	result  resultStore
+
+	mark     db.Database
+	failed   map[string]bool
+	mtx      sync.Mutex
+	issues   []*checkIssue
+	repaired int
+}
+
+func (t *taskCheck) String() string {
+	return fmt.Sprintf("Check(file=%s,repair=%v)", t.file, t.repair)
+}
+
+func (t *taskCheck) DetailOf(s State) string {
+	switch s {
+	case Started:
+		t.mtx.Lock()
+		defer t.mtx.Unlock()
+		return fmt.Sprintf("check %d/%d issues=%d repaired=%d",
+			atomic.LoadInt64(&t.current), t.last, len(t.issues), t.repaired)
+	default:
+		if st, ok := checkStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskCheck) genesisHeight() (int64, error) {
+	c := t.chain
+	if gt, err := c.cfg.GenesisStorage.Type(); err != nil {
+		return 0, err
+	} else if gt != module.GenesisPruned {
+		return 0, nil
+	}
+	g := new(gs.PrunedGenesis)
+	if err := json.Unmarshal(c.cfg.GenesisStorage.Genesis(), g); err != nil {
+		return 0, errors.IllegalArgumentError.Wrap(err, "InvalidGenesis")
+	}
+	return g.Height.Value, nil
+}
+
+func (t *taskCheck) Start() error {
+	c := t.chain
+	from, err := t.genesisHeight()
+	if err != nil {
+		return err
+	}
+	t.from = from
+	t.last = block.GetLastHeightOf(c.database)
+	t.current = from
+
+	// Only the managers for the state sync are required for the repair.
+	if t.repair {
+		c.nm = c.newNetworkManager()
+		c.sm, err = service.NewManager(c, c.nm, c.pm,
+			path.Join(c.cfg.AbsBaseDir(), DefaultContractDir))
+		if err != nil {
+			c.releaseManagers()
+			return err
+		}
+		if err := c.nm.Start(); err != nil {
+			c.releaseManagers()
+			return err
+		}
+	}
+
+	go func() {
+		err := t._check()
+		c.releaseManagers()
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskCheck) isStopped() bool {
+	select {
+	case <-t.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *taskCheck) addIssue(height int64, target string, key []byte, reason string, repaired bool) {
+	t.chain.logger.Warnf("Check height=%d target=%s key=%#x reason=%s repaired=%v",
+		height, target, key, reason, repaired)
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if repaired {
+		t.repaired += 1
+	}
+	t.issues = append(t.issues, &checkIssue{
+		Height:   height,
+		Target:   target,
+		Key:      key,
+		Reason:   reason,
+		Repaired: repaired,
+	})
+}
+
+func (t *taskCheck) get(ids []db.BucketID, key []byte) ([]byte, error) {
+	for _, id := range ids {
+		bk, err := t.chain.database.GetBucket(id)
+		if err != nil {
+			return nil, err
+		}
+		if value, err := bk.Get(key); err != nil || value != nil {
+			return value, err
+		}
+	}
+	return nil, nil
+}
+
+// _resolve resolves requests of the builder with the database, and returns
+// requests failed. Requests failed before are skipped.
+func (t *taskCheck) _resolve(bd merkle.Builder, failed map[string]*failedRequest) error {
+	for progress := true; progress; {
+		progress = false
+		for itr := bd.Requests(); itr.Next(); {
+			if t.isStopped() {
+				return errors.ErrInterrupted
+			}
+			key := itr.Key()
+			if _, ok := failed[string(key)]; ok || t.failed[string(key)] {
+				continue
+			}
+			value, err := t.get(itr.BucketIDs(), key)
+			if err != nil {
+				return err
+			}
+			var reason string
+			if value == nil {
+				reason = reasonMissing
+			} else if !bytes.Equal(crypto.SHA3Sum256(value), key) {
+				reason = reasonCorrupted
+			} else if err := bd.OnData(value); err != nil {
+				reason = fmt.Sprintf("invalid(%v)", err)
+			}
+			if len(reason) > 0 {
+				failed[string(key)] = &failedRequest{
+					ids:    append([]db.BucketID{}, itr.BucketIDs()...),
+					reason: reason,
+				}
+				continue
+			}
+			progress = true
+		}
+	}
+	return nil
+}
+
+// _repair fetches missing or corrupted nodes from the peers, and writes
+// them to the database. It returns the keys of the nodes written.
+func (t *taskCheck) _repair(failed map[string]*failedRequest) ([]string, error) {
+	var keys [][]byte
+	for key, f := range failed {
+		if f.reason == reasonMissing || f.reason == reasonCorrupted {
+			keys = append(keys, []byte(key))
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	values, err := service.FetchNodes(t.chain.sm, keys, t.stop)
+	if err != nil {
+		return nil, err
+	}
+	var repaired []string
+	for _, key := range keys {
+		value, ok := values[string(key)]
+		if !ok {
+			continue
+		}
+		bk, err := t.chain.database.GetBucket(failed[string(key)].ids[0])
+		if err != nil {
+			return nil, err
+		}
+		if err := bk.Set(key, value); err != nil {
+			return nil, err
+		}
+		repaired = append(repaired, string(key))
+	}
+	return repaired, nil
+}
+
+// resolve resolves all requests of the builder, and returns whether all
+// of them are resolved.
+func (t *taskCheck) resolve(bd merkle.Builder, height int64, target string) (bool, error) {
+	failed := make(map[string]*failedRequest)
+	for {
+		if err := t._resolve(bd, failed); err != nil {
+			return false, err
+		}
+		if !t.repair || len(failed) == 0 {
+			break
+		}
+		repaired, err := t._repair(failed)
+		if err != nil {
+			return false, err
+		}
+		if len(repaired) == 0 {
+			break
+		}
+		for _, key := range repaired {
+			t.addIssue(height, target, []byte(key), failed[key].reason, true)
+			delete(failed, key)
+		}
+	}
+	for key, f := range failed {
+		t.failed[key] = true
+		t.addIssue(height, target, []byte(key), f.reason, false)
+	}
+	return bd.UnresolvedCount() == 0, nil
+}
+
+// checkBlock checks the block at the height, and returns the block if it's
+// available. Previous blocks are used to verify the link and the votes.
+func (t *taskCheck) checkBlock(height int64, prev, pprev module.Block) (module.Block, error) {
+	c := t.chain
+	id, err := block.GetBlockIDOf(c.database, height)
+	if errors.NotFoundError.Equals(err) {
+		t.addIssue(height, "block", nil, reasonMissing, false)
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	bd := merkle.NewBuilderWithRawDatabase(t.mark)
+	blk := block.NewBlockWithBuilder(bd, c.CommitVoteSetDecoder(), id)
+	if ok, err := t.resolve(bd, height, "block"); err != nil || !ok {
+		return nil, err
+	}
+	if blk.Height() != height {
+		t.addIssue(height, "block", id,
+			fmt.Sprintf("invalid height(%d)", blk.Height()), false)
+		return nil, nil
+	}
+	if prev != nil {
+		// votes of the block next to the pruned genesis can't be verified
+		// without validators of the block before the genesis.
+		if pprev != nil || height == 1 {
+			var validators module.ValidatorList
+			if pprev != nil {
+				validators = pprev.NextValidators()
+			}
+			if err := block.VerifyBlock(blk, prev, validators); err != nil {
+				t.addIssue(height, "votes", blk.Votes().Hash(),
+					fmt.Sprintf("invalid(%v)", err), false)
+			}
+		} else if !bytes.Equal(blk.PrevID(), prev.ID()) {
+			t.addIssue(height, "block", id, "invalid(bad prev ID)", false)
+		}
+	}
+
+	for _, r := range []struct {
+		target  string
+		key     string
+		request func(merkle.Builder, []byte) error
+	}{
+		{"state", keyPrunedState, service.RequestWorldState},
+		{"receipts", keyPrunedReceipts, service.RequestReceipts},
+	} {
+		// data for the pruned blocks are not available.
+		if pruned, err := prunedHeightOf(c.database, r.key); err != nil {
+			return nil, err
+		} else if height < pruned {
+			continue
+		}
+		bd := merkle.NewBuilderWithRawDatabase(t.mark)
+		if err := r.request(bd, blk.Result()); err != nil {
+			t.addIssue(height, r.target, nil, fmt.Sprintf("invalid(%v)", err), false)
+			continue
+		}
+		if _, err := t.resolve(bd, height, r.target); err != nil {
+			return nil, err
+		}
+	}
+	return blk, nil
+}
+
+func (t *taskCheck) _check() error {
+	dir, err := ioutil.TempDir(t.chain.cfg.AbsBaseDir(), "check")
+	if err != nil {
+		return errors.Wrap(err, "Fail to make temporal directory")
+	}
+	defer os.RemoveAll(dir)
+
+	// Checked nodes are kept, so the nodes shared by the blocks are
+	// checked only once.
+	t.mark, err = db.Open(dir, string(db.GoLevelDBBackend), "mark")
+	if err != nil {
+		return err
+	}
+	defer t.mark.Close()
+
+	var prev, pprev module.Block
+	for h := t.from; h <= t.last; h++ {
+		blk, err := t.checkBlock(h, prev, pprev)
+		if err != nil {
+			return err
+		}
+		pprev, prev = prev, blk
+		atomic.StoreInt64(&t.current, h)
+	}
+	if err := t._writeReport(); err != nil {
+		return err
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.chain.logger.Infof("Checked %d blocks issues=%d repaired=%d",
+		t.last-t.from+1, len(t.issues), t.repaired)
+	if len(t.issues) > t.repaired {
+		return errors.InvalidStateError.Errorf(
+			"IntegrityCheckFailed(issues=%d,repaired=%d)", len(t.issues), t.repaired)
+	}
+	return nil
+}
+
+func (t *taskCheck) _writeReport() error {
+	if t.file == "" {
+		return nil
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	issues := t.issues
+	if issues == nil {
+		issues = []*checkIssue{}
+	}
+	bs, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.file, bs, 0644)
+}
+
+func (t *taskCheck) Stop() {
+	close(t.stop)
+}
+
+func (t *taskCheck) Wait() error {
+	return t.result.Wait()
+}
+
+func newTaskCheck(chain *singleChain, file string, repair bool) chainTask {
+	return &taskCheck{
+		chain:  chain,
+		file:   file,
+		repair: repair,
+		stop:   make(chan struct{}),
+		failed: make(map[string]bool),
+	}
+}
@@ -17,12 +17,16 @@
 package chain
 
 import (
+	"strings"
+
 	"github.com/icon-project/goloop/common/errors"
 )
 
 type taskConsensus struct {
-	chain  *singleChain
-	result resultStore
+	chain       *singleChain
+	pruner      *pruner
+	snapshotter *snapshotter
+	result      resultStore
 }
 
 var consensusStates = map[State]string{
@@ -37,6 +41,22 @@ func (t *taskConsensus) String() string {
 }
 
 func (t *taskConsensus) DetailOf(s State) string {
+	if s == Started {
+		var details []string
+		if t.pruner != nil {
+			if detail := t.pruner.String(); detail != "" {
+				details = append(details, detail)
+			}
+		}
+		if t.snapshotter != nil {
+			if detail := t.snapshotter.String(); detail != "" {
+				details = append(details, detail)
+			}
+		}
+		if len(details) > 0 {
+			return "started, " + strings.Join(details, ", ")
+		}
+	}
 	if name, ok := consensusStates[s]; ok {
 		return name
 	} else {
@@ -65,11 +85,27 @@ func (t *taskConsensus) _start(c *singleChain) error {
 	if err := c.cs.Start(); err != nil {
 		return err
 	}
+	snapshotter, err := newSnapshotter(c)
+	if err != nil {
+		return err
+	}
 	c.srv.SetChain(c.cfg.Channel, c)
+	if t.pruner = newPruner(c); t.pruner != nil {
+		t.pruner.Start()
+	}
+	if t.snapshotter = snapshotter; t.snapshotter != nil {
+		t.snapshotter.Start()
+	}
 	return nil
 }
 
 func (t *taskConsensus) Stop() {
+	if t.pruner != nil {
+		t.pruner.Stop()
+	}
+	if t.snapshotter != nil {
+		t.snapshotter.Stop()
+	}
 	t.chain.srv.RemoveChain(t.chain.cfg.Channel)
 	t.chain.releaseManagers()
 	t.result.SetValue(errors.ErrInterrupted)
@@ -0,0 +1,180 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+var exportStates = map[State]string{
+	Starting: "export starting",
+	Stopping: "export stopping",
+	Failed:   "export failed",
+	Finished: "export done",
+}
+
+type taskExport struct {
+	chain    *singleChain
+	file     string
+	from     int64
+	to       int64
+	receipts bool
+	current  int64
+	stop     int32
+	result   resultStore
+}
+
+func (t *taskExport) String() string {
+	return fmt.Sprintf("Export(file=%s,from=%d,to=%d)", t.file, t.from, t.to)
+}
+
+func (t *taskExport) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("export %d/%d", atomic.LoadInt64(&t.current), t.to)
+	default:
+		if st, ok := exportStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskExport) Start() error {
+	if err := t.chain.prepareManagers(); err != nil {
+		t.result.SetValue(err)
+		return err
+	}
+	last, err := t.chain.bm.GetLastBlock()
+	if err != nil {
+		t.chain.releaseManagers()
+		return err
+	}
+	// votes for a block are in the next block.
+	if t.from < 1 || t.from > t.to || t.to >= last.Height() {
+		t.chain.releaseManagers()
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidRange(from=%d,to=%d,last=%d)", t.from, t.to, last.Height())
+	}
+
+	go func() {
+		err := t._export()
+		t.chain.releaseManagers()
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskExport) _export() (ret error) {
+	tmp, err := ioutil.TempFile(path.Dir(t.file), path.Base(t.file))
+	if err != nil {
+		return errors.Wrap(err, "Fail to make temporal file")
+	}
+	defer func() {
+		tmp.Close()
+		if ret != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	aw := newArchiveWriter(tmp)
+	if err := aw.Write(&ArchiveHeader{
+		Magic:    archiveMagic,
+		Version:  archiveVersion,
+		NID:      t.chain.NID(),
+		From:     t.from,
+		To:       t.to,
+		Receipts: t.receipts,
+	}); err != nil {
+		return err
+	}
+
+	bm := t.chain.bm
+	sm := t.chain.sm
+	for h := t.from; h <= t.to; h++ {
+		if atomic.LoadInt32(&t.stop) != 0 {
+			return errors.ErrInterrupted
+		}
+		blk, err := bm.GetBlockByHeight(h)
+		if err != nil {
+			return err
+		}
+		next, err := bm.GetBlockByHeight(h + 1)
+		if err != nil {
+			return err
+		}
+		bs := bytes.NewBuffer(nil)
+		if err := blk.Marshal(bs); err != nil {
+			return err
+		}
+		rec := &ArchiveRecord{
+			Block: bs.Bytes(),
+			Votes: next.Votes().Bytes(),
+		}
+		if t.receipts {
+			rl, err := sm.ReceiptListFromResult(next.Result(), module.TransactionGroupNormal)
+			if err != nil {
+				return err
+			}
+			for it := rl.Iterator(); it.Has(); it.Next() {
+				r, err := it.Get()
+				if err != nil {
+					return err
+				}
+				rec.Receipts = append(rec.Receipts, r.Bytes())
+			}
+		}
+		if err := aw.Write(rec); err != nil {
+			return err
+		}
+		atomic.StoreInt64(&t.current, h)
+	}
+	if err := aw.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), t.file)
+}
+
+func (t *taskExport) Stop() {
+	atomic.StoreInt32(&t.stop, 1)
+}
+
+func (t *taskExport) Wait() error {
+	return t.result.Wait()
+}
+
+func newTaskExport(chain *singleChain, file string, from, to int64, receipts bool) chainTask {
+	return &taskExport{
+		chain:    chain,
+		file:     file,
+		from:     from,
+		to:       to,
+		receipts: receipts,
+	}
+}
@@ -25,7 +25,6 @@ import (
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
-	"github.com/icon-project/goloop/network"
 )
 
 var importStates = map[State]string{
@@ -97,8 +96,7 @@ func (t *taskImport) _import() error {
 	c := t.chain
 	chainDir := c.cfg.AbsBaseDir()
 
-	pr := network.PeerRoleFlag(c.cfg.Role)
-	c.nm = network.NewManager(c, c.nt, c.cfg.SeedAddr, pr.ToRoles()...)
+	c.nm = c.newNetworkManager()
 
 	ContractDir := path.Join(chainDir, DefaultContractDir)
 	var err error
@@ -0,0 +1,228 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+type taskImportArchive struct {
+	chain    *singleChain
+	file     string
+	receipts bool
+	to       int64
+	current  int64
+	stop     int32
+	result   resultStore
+}
+
+func (t *taskImportArchive) String() string {
+	return fmt.Sprintf("Import(archive=%s)", t.file)
+}
+
+func (t *taskImportArchive) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("import %d/%d",
+			atomic.LoadInt64(&t.current), atomic.LoadInt64(&t.to))
+	default:
+		if st, ok := importStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskImportArchive) Start() error {
+	fd, err := os.Open(t.file)
+	if err != nil {
+		return errors.IllegalArgumentError.Wrapf(err,
+			"FailToOpenArchive(file=%s)", t.file)
+	}
+	ar, err := newArchiveReader(fd)
+	if err != nil {
+		fd.Close()
+		return err
+	}
+	h, err := ar.ReadHeader()
+	if err != nil {
+		fd.Close()
+		return err
+	}
+	if h.NID != t.chain.NID() {
+		fd.Close()
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidNetwork(archive=%#x,chain=%#x)", h.NID, t.chain.NID())
+	}
+	atomic.StoreInt64(&t.to, h.To)
+	t.receipts = h.Receipts
+
+	if err := t.chain.prepareManagers(); err != nil {
+		fd.Close()
+		return err
+	}
+
+	go func() {
+		err := t._import(ar)
+		fd.Close()
+		t.chain.releaseManagers()
+		if err != nil {
+			t.chain.logger.Warnf("Fail to import err=%+v", err)
+		}
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskImportArchive) _importBlock(blk module.BlockData) (module.BlockCandidate, error) {
+	type result struct {
+		bc  module.BlockCandidate
+		err error
+	}
+	ch := make(chan result, 1)
+	_, err := t.chain.bm.ImportBlock(blk, 0, func(bc module.BlockCandidate, err error) {
+		ch <- result{bc, err}
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := <-ch
+	return r.bc, r.err
+}
+
+func (t *taskImportArchive) _verifyReceipts(blk module.Block, receipts [][]byte) error {
+	rl, err := t.chain.sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+	if err != nil {
+		return err
+	}
+	idx := 0
+	for it := rl.Iterator(); it.Has(); it.Next() {
+		r, err := it.Get()
+		if err != nil {
+			return err
+		}
+		if idx >= len(receipts) || !bytes.Equal(r.Bytes(), receipts[idx]) {
+			return errors.InvalidStateError.Errorf(
+				"InvalidReceipt(height=%d,idx=%d)", blk.Height()-1, idx)
+		}
+		idx += 1
+	}
+	if idx != len(receipts) {
+		return errors.InvalidStateError.Errorf(
+			"InvalidReceipts(height=%d,exp=%d,real=%d)",
+			blk.Height()-1, len(receipts), idx)
+	}
+	return nil
+}
+
+func (t *taskImportArchive) _import(ar *archiveReader) error {
+	bm := t.chain.bm
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt64(&t.current, last.Height())
+
+	// receipts of the block are in the result of the next block
+	var prev *ArchiveRecord
+	for {
+		if atomic.LoadInt32(&t.stop) != 0 {
+			return errors.ErrInterrupted
+		}
+		rec := new(ArchiveRecord)
+		if err := ar.Read(rec); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		blk, err := bm.NewBlockDataFromReader(bytes.NewReader(rec.Block))
+		if err != nil {
+			return errors.IllegalArgumentError.Wrap(err, "InvalidBlock")
+		}
+		if blk.Height() <= last.Height() {
+			old, err := bm.GetBlockByHeight(blk.Height())
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(old.ID(), blk.ID()) {
+				return errors.InvalidStateError.Errorf(
+					"DifferentBlock(height=%d,archive=%#x,chain=%#x)",
+					blk.Height(), blk.ID(), old.ID())
+			}
+			prev = rec
+			continue
+		}
+		if blk.Height() != last.Height()+1 {
+			return errors.InvalidStateError.Errorf(
+				"MissingBlocks(last=%d,next=%d)", last.Height(), blk.Height())
+		}
+
+		bc, err := t._importBlock(blk)
+		if err != nil {
+			return err
+		}
+		votes := t.chain.vld(rec.Votes)
+		if votes == nil {
+			bc.Dispose()
+			return errors.IllegalArgumentError.Errorf(
+				"InvalidVotes(height=%d)", blk.Height())
+		}
+		if err := votes.Verify(blk, last.NextValidators()); err != nil {
+			bc.Dispose()
+			return err
+		}
+		err = bm.Finalize(bc)
+		bc.Dispose()
+		if err != nil {
+			return err
+		}
+		if last, err = bm.GetLastBlock(); err != nil {
+			return err
+		}
+		if t.receipts && prev != nil {
+			if err := t._verifyReceipts(last, prev.Receipts); err != nil {
+				return err
+			}
+		}
+		prev = rec
+		atomic.StoreInt64(&t.current, last.Height())
+	}
+}
+
+func (t *taskImportArchive) Stop() {
+	atomic.StoreInt32(&t.stop, 1)
+}
+
+func (t *taskImportArchive) Wait() error {
+	return t.result.Wait()
+}
+
+func newTaskImportArchive(chain *singleChain, file string) chainTask {
+	return &taskImportArchive{
+		chain: chain,
+		file:  file,
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+	ssync "github.com/icon-project/goloop/service/sync"
+)
+
+// taskImportSnapshot restores the world state of the pruned genesis from
+// the snapshot file, or from the peers if the file isn't specified.
+type taskImportSnapshot struct {
+	chain   *singleChain
+	file    string
+	genesis *gs.PrunedGenesis
+	state   []byte
+	chunks  int64
+	current int64
+	stop    chan struct{}
+	result  resultStore
+}
+
+func (t *taskImportSnapshot) String() string {
+	if t.file == "" {
+		return "ImportSnapshot(peers)"
+	}
+	return fmt.Sprintf("ImportSnapshot(file=%s)", t.file)
+}
+
+func (t *taskImportSnapshot) DetailOf(s State) string {
+	switch s {
+	case Started:
+		chunks := atomic.LoadInt64(&t.chunks)
+		if chunks == 0 {
+			return "import snapshot fetching"
+		}
+		return fmt.Sprintf("import snapshot %d/%d",
+			atomic.LoadInt64(&t.current), chunks)
+	default:
+		if st, ok := importStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+// _stateHashOfGenesis returns the state hash in the result of the block of
+// the pruned genesis.
+func (t *taskImportSnapshot) _stateHashOfGenesis() ([]byte, error) {
+	c := t.chain
+	ctx := merkle.NewCopyContext(gs.NewDatabaseWithStorage(c.cfg.GenesisStorage), db.NewMapDB())
+	blk := block.NewBlockWithBuilder(ctx.Builder(), c.CommitVoteSetDecoder(), t.genesis.Block)
+	if err := ctx.Run(); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidGenesisBlock")
+	}
+	stateHash, _, _, err := service.ParseResult(blk.Result())
+	return stateHash, err
+}
+
+func (t *taskImportSnapshot) _checkManifest(mf *ssync.SnapshotManifest) error {
+	g := t.genesis
+	if mf.NID != int(g.NID.Value) || mf.Height != g.Height.Value ||
+		!bytes.Equal(mf.BlockID, g.Block) || !bytes.Equal(mf.StateHash, t.state) {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidSnapshot(snapshot=%s,genesis=%d:%#x,state=%#x)",
+			mf, g.Height.Value, []byte(g.Block), t.state)
+	}
+	return nil
+}
+
+func (t *taskImportSnapshot) Start() error {
+	c := t.chain
+	if gt, err := c.cfg.GenesisStorage.Type(); err != nil {
+		return err
+	} else if gt != module.GenesisPruned {
+		return errors.InvalidStateError.New("RequirePrunedGenesis")
+	}
+	t.genesis = new(gs.PrunedGenesis)
+	if err := json.Unmarshal(c.cfg.GenesisStorage.Genesis(), t.genesis); err != nil {
+		return errors.IllegalArgumentError.Wrap(err, "InvalidGenesis")
+	}
+	if state, err := t._stateHashOfGenesis(); err != nil {
+		return err
+	} else {
+		t.state = state
+	}
+
+	var file *ssync.SnapshotFile
+	if t.file != "" {
+		var err error
+		if file, err = ssync.OpenSnapshotFile(t.file); err != nil {
+			return errors.IllegalArgumentError.Wrapf(err,
+				"FailToOpenSnapshot(file=%s)", t.file)
+		}
+		if err := t._checkManifest(file.Manifest()); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	// The block manager can't be created before the world state of the
+	// pruned genesis is restored.
+	c.nm = c.newNetworkManager()
+	var err error
+	c.sm, err = service.NewManager(c, c.nm, c.pm,
+		path.Join(c.cfg.AbsBaseDir(), DefaultContractDir))
+	if err != nil {
+		c.releaseManagers()
+		if file != nil {
+			file.Close()
+		}
+		return err
+	}
+
+	go func() {
+		var src ssync.SnapshotSource
+		if file != nil {
+			src = file
+			defer file.Close()
+		}
+		err := t._import(src)
+		c.releaseManagers()
+		if err != nil {
+			c.logger.Warnf("Fail to import snapshot err=%+v", err)
+		}
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskImportSnapshot) _import(src ssync.SnapshotSource) error {
+	c := t.chain
+	if src == nil {
+		if err := c.nm.Start(); err != nil {
+			return err
+		}
+		var err error
+		src, err = service.FetchSnapshot(c.sm, t.genesis.Height.Value,
+			t._checkManifest, t.stop)
+		if err != nil {
+			return err
+		}
+	}
+	atomic.StoreInt64(&t.chunks, int64(len(src.Manifest().Chunks)))
+	err := ssync.RestoreSnapshot(c.database, src, c.cfg.AbsBaseDir(), func(idx int) error {
+		select {
+		case <-t.stop:
+			return errors.ErrInterrupted
+		default:
+		}
+		atomic.StoreInt64(&t.current, int64(idx+1))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Finalizing the pruned genesis verifies the world state of its block.
+	bm, err := block.NewManager(c, nil)
+	if err != nil {
+		return errors.InvalidStateError.Wrap(err, "FailToFinalizeGenesis")
+	}
+	bm.Term()
+	c.logger.Infof("Imported %s", src.Manifest())
+	return nil
+}
+
+func (t *taskImportSnapshot) Stop() {
+	close(t.stop)
+}
+
+func (t *taskImportSnapshot) Wait() error {
+	return t.result.Wait()
+}
+
+func newTaskImportSnapshot(chain *singleChain, file string) chainTask {
+	return &taskImportSnapshot{
+		chain: chain,
+		file:  file,
+		stop:  make(chan struct{}),
+	}
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+)
+
+var orphansStates = map[State]string{
+	Starting: "orphans starting",
+	Stopping: "orphans stopping",
+	Failed:   "orphans failed",
+	Finished: "orphans done",
+}
+
+// taskFindOrphans finds nodes in the merkle trie bucket, which are not
+// reachable from the world states, receipts and transactions of the blocks.
+// Reachable nodes are marked in the temporal database first, then the
+// bucket is scanned for the nodes which are not marked.
+type taskFindOrphans struct {
+	chain   *singleChain
+	limit   int
+	last    int64
+	current int64
+	scanned int64
+	stop    int32
+	result  resultStore
+
+	count   int64
+	orphans [][]byte
+}
+
+func (t *taskFindOrphans) String() string {
+	return fmt.Sprintf("FindOrphans(limit=%d)", t.limit)
+}
+
+func (t *taskFindOrphans) DetailOf(s State) string {
+	switch s {
+	case Started:
+		if scanned := atomic.LoadInt64(&t.scanned); scanned > 0 {
+			return fmt.Sprintf("orphans scan %d found=%d",
+				scanned, atomic.LoadInt64(&t.count))
+		}
+		return fmt.Sprintf("orphans mark %d/%d",
+			atomic.LoadInt64(&t.current), t.last)
+	default:
+		if st, ok := orphansStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskFindOrphans) Start() error {
+	if err := t.chain.prepareManagers(); err != nil {
+		t.result.SetValue(err)
+		return err
+	}
+	last, err := t.chain.bm.GetLastBlock()
+	if err != nil {
+		t.chain.releaseManagers()
+		return err
+	}
+	t.last = last.Height()
+	t.current = t.last
+
+	go func() {
+		err := t._find()
+		t.chain.releaseManagers()
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskFindOrphans) isStopped() bool {
+	return atomic.LoadInt32(&t.stop) != 0
+}
+
+// copyTransactions copies the transaction lists of the block if they are
+// available.
+func copyTransactions(src db.Database, blk module.Block, dst db.Database) error {
+	bk, err := src.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	for _, txs := range []module.TransactionList{
+		blk.PatchTransactions(), blk.NormalTransactions(),
+	} {
+		h := txs.Hash()
+		if len(h) == 0 || !bk.Has(h) {
+			continue
+		}
+		if err := service.CopyTransactions(src, h, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *taskFindOrphans) _mark(mdb db.Database) error {
+	c := t.chain
+	for h := t.last; h >= 0; h-- {
+		if t.isStopped() {
+			return errors.ErrInterrupted
+		}
+		blk, err := c.bm.GetBlockByHeight(h)
+		if err != nil {
+			// blocks before the pruned genesis are not available.
+			if errors.NotFoundError.Equals(err) {
+				return nil
+			}
+			return err
+		}
+		if err := copyState(c.database, blk.Result(), mdb); err != nil {
+			return err
+		}
+		if err := copyReceipts(c.database, blk.Result(), mdb); err != nil {
+			return err
+		}
+		if err := copyTransactions(c.database, blk, mdb); err != nil {
+			return err
+		}
+		atomic.StoreInt64(&t.current, h)
+	}
+	return nil
+}
+
+func (t *taskFindOrphans) _scan(mdb db.Database) error {
+	bk, err := t.chain.database.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	mbk, err := mdb.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	it := bk.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if t.isStopped() {
+			return errors.ErrInterrupted
+		}
+		atomic.AddInt64(&t.scanned, 1)
+		if mbk.Has(it.Key()) {
+			continue
+		}
+		atomic.AddInt64(&t.count, 1)
+		if len(t.orphans) < t.limit {
+			t.orphans = append(t.orphans, append([]byte{}, it.Key()...))
+		}
+	}
+	return it.Error()
+}
+
+func (t *taskFindOrphans) _find() error {
+	dir, err := ioutil.TempDir(t.chain.cfg.AbsBaseDir(), "orphans")
+	if err != nil {
+		return errors.Wrap(err, "Fail to make temporal directory")
+	}
+	defer os.RemoveAll(dir)
+
+	mdb, err := db.Open(dir, string(db.GoLevelDBBackend), "mark")
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	if err := t._mark(mdb); err != nil {
+		return err
+	}
+	if err := t._scan(mdb); err != nil {
+		return err
+	}
+	t.chain.logger.Infof("Found %d orphans in %d nodes",
+		atomic.LoadInt64(&t.count), atomic.LoadInt64(&t.scanned))
+	return nil
+}
+
+func (t *taskFindOrphans) Stop() {
+	atomic.StoreInt32(&t.stop, 1)
+}
+
+func (t *taskFindOrphans) Wait() error {
+	return t.result.Wait()
+}
+
+func newTaskFindOrphans(chain *singleChain, limit int) *taskFindOrphans {
+	return &taskFindOrphans{
+		chain: chain,
+		limit: limit,
+	}
+}
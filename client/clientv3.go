@@ -135,6 +135,22 @@ type Transaction struct {
 	TxIndex     jsonrpc.HexInt   `json:"txIndex" validate:"required,t_int"`
 }
 
+//refer server/v3/api_v3.go getLogs
+type Log struct {
+	BlockHash   jsonrpc.HexBytes `json:"blockHash"`
+	BlockHeight jsonrpc.HexInt   `json:"blockHeight"`
+	TxIndex     jsonrpc.HexInt   `json:"txIndex"`
+	TxHash      jsonrpc.HexBytes `json:"txHash"`
+	Events      []jsonrpc.HexInt `json:"events"`
+}
+
+//refer server/v3/api_v3.go getBlockProof
+type BlockProof struct {
+	Header  []byte         `json:"header"`
+	Index   jsonrpc.HexInt `json:"index"`
+	Witness [][]byte       `json:"witness"`
+}
+
 func (c *ClientV3) GetLastBlock() (*Block, error) {
 	blk := &Block{}
 	_, err := c.Do("icx_getLastBlock", nil, blk)
@@ -190,9 +206,13 @@ func (c *ClientV3) GetScoreApi(param *v3.ScoreAddressParam) ([]interface{}, erro
 	return result, nil
 }
 
-func (c *ClientV3) GetTotalSupply() (*jsonrpc.HexInt, error) {
+func (c *ClientV3) GetTotalSupply(param *v3.TotalSupplyParam) (*jsonrpc.HexInt, error) {
 	var result jsonrpc.HexInt
-	_, err := c.Do("icx_getTotalSupply", nil, &result)
+	var p interface{}
+	if param != nil {
+		p = param
+	}
+	_, err := c.Do("icx_getTotalSupply", p, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -225,6 +245,33 @@ func (c *ClientV3) GetTransactionByHash(param *v3.TransactionHashParam) (*Transa
 	return t, nil
 }
 
+func (c *ClientV3) GetTransactionsByAddress(param *v3.AccountTransactionsParam) ([]jsonrpc.HexBytes, error) {
+	var result []jsonrpc.HexBytes
+	_, err := c.Do("icx_getTransactionsByAddress", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *ClientV3) GetTransactionCountByAddress(param *v3.AddressParam) (*jsonrpc.HexInt, error) {
+	var result jsonrpc.HexInt
+	_, err := c.Do("icx_getTransactionCountByAddress", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *ClientV3) GetLogs(param *v3.LogsParam) ([]Log, error) {
+	var result []Log
+	_, err := c.Do("icx_getLogs", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 var txSerializeExcludes = map[string]bool{"signature": true}
 
 func (c *ClientV3) SendTransaction(w module.Wallet, param *v3.TransactionParam) (*jsonrpc.HexBytes, error) {
@@ -281,6 +328,24 @@ func (c *ClientV3) GetVotesByHeight(param *v3.BlockHeightParam) ([]byte, error)
 	return result, nil
 }
 
+func (c *ClientV3) GetEvidence(param *v3.EvidenceParam) ([]interface{}, error) {
+	var result []interface{}
+	_, err := c.Do("icx_getEvidence", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *ClientV3) GetBlockProof(param *v3.BlockProofParam) (*BlockProof, error) {
+	result := &BlockProof{}
+	_, err := c.Do("icx_getBlockProof", param, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 //refer common/trie/ompt/mtp.go mpt.GetProof(index)
 func (c *ClientV3) GetProofForResult(param *v3.ProofResultParam) ([][]byte, error) {
 	var result [][]byte
@@ -318,6 +383,19 @@ func (c *ClientV3) MonitorEvent(param *server.EventRequest, cb func(v *server.Ev
 	}, cancelCh)
 }
 
+// MonitorSubscription monitors events with a subscription. Set Cursor of the
+// request to the cursor of the last notification to resume the subscription.
+func (c *ClientV3) MonitorSubscription(param *server.SubscriptionRequest, cb func(v *server.SubscriptionNotification), cancelCh <-chan bool) error {
+	param.Method = server.SubscriptionMethodSubscribe
+	resp := &server.SubscriptionNotification{}
+	return c.Monitor("/subscription", param, resp, func(v interface{}) {
+		// responses for failures don't have hash
+		if sn, ok := v.(*server.SubscriptionNotification); ok && len(sn.Hash) > 0 {
+			cb(sn)
+		}
+	}, cancelCh)
+}
+
 func (c *ClientV3) Monitor(reqUrl string, reqPtr, respPtr interface{},
 	cb func(v interface{}), cancelCh <-chan bool) error {
 	if cb == nil {
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/gosuri/uitable"
@@ -94,6 +95,8 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			genesisPath, _ := fs.GetString("genesis_template")
 			param := &node.ChainConfig{}
 			param.SeedAddr, _ = fs.GetString("seed")
+			param.StaticPeers, _ = fs.GetString("static_peers")
+			param.AllowedPeers, _ = fs.GetString("allowed_peers")
 			param.Role, _ = fs.GetUint("role")
 			param.DBType, _ = fs.GetString("db_type")
 			param.ConcurrencyLevel, _ = fs.GetInt("concurrency")
@@ -107,6 +110,17 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			param.DefWaitTimeout, _ = fs.GetInt64("default_wait_timeout")
 			param.MaxWaitTimeout, _ = fs.GetInt64("max_wait_timeout")
 			param.AutoStart, _ = fs.GetBool("auto_start")
+			param.AccountIndex, _ = fs.GetBool("account_index")
+			param.MaxLogsRange, _ = fs.GetInt("max_logs_range")
+			param.MaxLogsResult, _ = fs.GetInt("max_logs_result")
+			param.TxPoolMaxPerSender, _ = fs.GetInt("tx_pool_max_per_sender")
+			param.TxPoolAllowList, _ = fs.GetString("tx_pool_allow_list")
+			param.TxPoolDenyList, _ = fs.GetString("tx_pool_deny_list")
+			param.TxRateLimit, _ = fs.GetInt("tx_rate_limit")
+			param.StateRetention, _ = fs.GetInt64("state_retention")
+			param.ReceiptRetention, _ = fs.GetInt64("receipt_retention")
+			param.BlockRetention, _ = fs.GetInt64("block_retention")
+			param.SnapshotInterval, _ = fs.GetInt64("snapshot_interval")
 
 			var buf *bytes.Buffer
 			if len(genesisZip) > 0 {
@@ -151,8 +165,10 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.String("genesis", "", "Genesis storage path")
 	joinFlags.String("genesis_template", "", "Genesis template directory or file")
 	joinFlags.String("seed", "", "List of trust-seed ip-port, Comma separated string")
+	joinFlags.String("static_peers", "", "List of ip-port of peers always connected, Comma separated string")
+	joinFlags.String("allowed_peers", "", "List of ID or ip-port of peers allowed to connect, Comma separated string (empty: all)")
 	joinFlags.Uint("role", 3, "[0:None, 1:Seed, 2:Validator, 3:Both]")
-	joinFlags.String("db_type", "goleveldb", "Name of database system(*badgerdb, goleveldb, boltdb, mapdb)")
+	joinFlags.String("db_type", "goleveldb", "Name of database system(*badgerdb, goleveldb, boltdb, mapdb, pebbledb)")
 	joinFlags.Int("concurrency", 1, "Maximum number of executors to be used for concurrency")
 	joinFlags.Int("normal_tx_pool", 0, "Size of normal transaction pool")
 	joinFlags.Int("patch_tx_pool", 0, "Size of patch transaction pool")
@@ -166,6 +182,17 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int64("default_wait_timeout", 0, "Default wait timeout in milli-second (0: disable)")
 	joinFlags.Int64("max_wait_timeout", 0, "Max wait timeout in milli-second (0: uses same value of default_wait_timeout)")
 	joinFlags.Bool("auto_start", false, "Auto start")
+	joinFlags.Bool("account_index", false, "Enable account index for transactions by address")
+	joinFlags.Int("max_logs_range", 0, "Max number of blocks for icx_getLogs (0: uses default)")
+	joinFlags.Int("max_logs_result", 0, "Max number of results for icx_getLogs (0: uses default)")
+	joinFlags.Int("tx_pool_max_per_sender", 0, "Max number of pending transactions of a sender (0: unlimited)")
+	joinFlags.String("tx_pool_allow_list", "", "Senders allowed to add transactions to the pool, Comma separated string (empty: all)")
+	joinFlags.String("tx_pool_deny_list", "", "Senders denied to add transactions to the pool, Comma separated string")
+	joinFlags.Int("tx_rate_limit", 0, "Max number of sendTransaction calls per second of a client IP (0: unlimited)")
+	joinFlags.Int64("state_retention", 0, "Number of recent blocks to keep world states for (0: keep all)")
+	joinFlags.Int64("receipt_retention", 0, "Number of recent blocks to keep receipts for (0: keep all)")
+	joinFlags.Int64("block_retention", 0, "Number of recent blocks to keep (0: keep all)")
+	joinFlags.Int64("snapshot_interval", 0, "Interval of blocks to make state snapshots (0: disabled)")
 
 	leaveCmd := &cobra.Command{
 		Use:   "leave CID",
@@ -262,13 +289,41 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 
 	importCmd := &cobra.Command{
 		Use:   "import CID",
-		Short: "Start to import legacy database",
+		Short: "Start to import legacy database, block archive or state snapshot",
 		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			fs := cmd.Flags()
 			param := &node.ChainImportParam{}
 			param.DBPath, _ = fs.GetString("db_path")
 			param.Height, _ = fs.GetInt64("height")
+			snapshot, _ := fs.GetString("snapshot")
+			param.FetchSnapshot, _ = fs.GetBool("fetch_snapshot")
+			if snapshot != "" || param.FetchSnapshot {
+				if fs.Changed("db_path") || fs.Changed("height") || fs.Changed("archive") {
+					return fmt.Errorf("--snapshot and --fetch_snapshot can't be used with --db_path, --height and --archive")
+				}
+				if snapshot != "" && param.FetchSnapshot {
+					return fmt.Errorf("--snapshot can't be used with --fetch_snapshot")
+				}
+				if snapshot != "" {
+					if p, err := filepath.Abs(snapshot); err != nil {
+						return err
+					} else {
+						param.Snapshot = p
+					}
+				}
+			} else if archive, _ := fs.GetString("archive"); archive != "" {
+				if fs.Changed("db_path") || fs.Changed("height") {
+					return fmt.Errorf("--archive can't be used with --db_path and --height")
+				}
+				if p, err := filepath.Abs(archive); err != nil {
+					return err
+				} else {
+					param.Archive = p
+				}
+			} else if !fs.Changed("db_path") || !fs.Changed("height") {
+				return fmt.Errorf("--db_path and --height are required to import legacy database")
+			}
 
 			var v string
 			reqUrl := node.UrlChain + "/" + args[0] + "/import"
@@ -284,7 +339,42 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	importFlags := importCmd.Flags()
 	importFlags.String("db_path", "", "Database path")
 	importFlags.Int64("height", 0, "Block Height")
-	MarkAnnotationRequired(importFlags, "db_path", "height")
+	importFlags.String("archive", "", "Block archive file made by export")
+	importFlags.String("snapshot", "", "State snapshot file for the pruned genesis")
+	importFlags.Bool("fetch_snapshot", false, "Fetch state snapshot for the pruned genesis from the peers")
+
+	exportCmd := &cobra.Command{
+		Use:   "export CID FILE",
+		Short: "Start to export blocks to the archive file",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainExportParam{}
+			param.From, _ = fs.GetInt64("from")
+			param.To, _ = fs.GetInt64("to")
+			param.Receipts, _ = fs.GetBool("receipts")
+			if p, err := filepath.Abs(args[1]); err != nil {
+				return err
+			} else {
+				param.File = p
+			}
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/export"
+			_, err := adminClient.PostWithJson(reqUrl, param, &v)
+			if err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(exportCmd)
+	exportFlags := exportCmd.Flags()
+	exportFlags.Int64("from", 0, "First block height")
+	exportFlags.Int64("to", 0, "Last block height")
+	exportFlags.Bool("receipts", false, "Include receipts of transactions")
+	MarkAnnotationRequired(exportFlags, "from", "to")
 
 	pruneCmd := &cobra.Command{
 		Use:   "prune CID",
@@ -317,9 +407,14 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		Short: "Start to backup the channel",
 		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainBackupParam{}
+			param.Online, _ = fs.GetBool("online")
+			param.Base, _ = fs.GetString("base")
+
 			var v string
 			reqUrl := node.UrlChain + "/" + args[0] + "/backup"
-			_, err := adminClient.PostWithJson(reqUrl, nil, &v)
+			_, err := adminClient.PostWithJson(reqUrl, param, &v)
 			if err != nil {
 				return err
 			}
@@ -328,6 +423,40 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		},
 	}
 	rootCmd.AddCommand(backupCmd)
+	backupFlags := backupCmd.Flags()
+	backupFlags.Bool("online", false, "Backup the snapshot of the database without stopping the chain")
+	backupFlags.String("base", "", "Name of the base backup for incremental backup (implies --online)")
+
+	checkCmd := &cobra.Command{
+		Use:   "check CID",
+		Short: "Start to check integrity of the database",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainCheckParam{}
+			param.Repair, _ = fs.GetBool("repair")
+			if report, _ := fs.GetString("report"); report != "" {
+				if p, err := filepath.Abs(report); err != nil {
+					return err
+				} else {
+					param.File = p
+				}
+			}
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/check"
+			_, err := adminClient.PostWithJson(reqUrl, param, &v)
+			if err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(checkCmd)
+	checkFlags := checkCmd.Flags()
+	checkFlags.Bool("repair", false, "Fetch missing or corrupted data from the peers")
+	checkFlags.String("report", "", "File to write the report of the issues")
 
 	genesisCmd := &cobra.Command{
 		Use:   "genesis CID FILE",
@@ -388,9 +517,73 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		},
 	}
 	rootCmd.AddCommand(configCmd)
+
+	NewChainPeersCmd(rootCmd, &adminClient)
 	return rootCmd, vc
 }
 
+func NewChainPeersCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "peers",
+		Short: "Manage static peers and allowed peers of the chain",
+	}
+	parent.AddCommand(rootCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "ls CID",
+		Short: "List static peers and allowed peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := &node.ChainPeersView{}
+			reqUrl := node.UrlChain + "/" + args[0] + "/peers"
+			resp, err := client.Get(reqUrl, v)
+			if err != nil {
+				return err
+			}
+			if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+				return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(listCmd)
+
+	editPeers := func(op string) func(cmd *cobra.Command, args []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			param := &node.ChainPeersParam{}
+			if static, _ := cmd.Flags().GetBool("static"); static {
+				param.Static = args[1:]
+			} else {
+				param.Allowed = args[1:]
+			}
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/peers/" + op
+			if _, err := client.PostWithJson(reqUrl, param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		}
+	}
+	addCmd := &cobra.Command{
+		Use:   "add CID PEER...",
+		Short: "Add allowed peers (ID or ip-port) or static peers (ip-port)",
+		Args:  ArgsWithDefaultErrorFunc(cobra.MinimumNArgs(2)),
+		RunE:  editPeers("add"),
+	}
+	rootCmd.AddCommand(addCmd)
+	addCmd.Flags().Bool("static", false, "Add static peers instead of allowed peers")
+
+	rmCmd := &cobra.Command{
+		Use:   "rm CID PEER...",
+		Short: "Remove allowed peers or static peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.MinimumNArgs(2)),
+		RunE:  editPeers("remove"),
+	}
+	rootCmd.AddCommand(rmCmd)
+	rmCmd.Flags().Bool("static", false, "Remove static peers instead of allowed peers")
+}
+
 func NewSystemCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command, *viper.Viper) {
 	var adminClient node.UnixDomainSockHttpClient
 	rootCmd, vc := NewCommand(parentCmd, parentVc, "system", "System info")
@@ -561,7 +754,7 @@ func NewUserCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command
 		Short: "List users",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			l := make([]string, 0)
+			l := make([]*node.User, 0)
 			reqUrl := node.UrlUser
 			resp, err := adminClient.Get(reqUrl, &l)
 			if err != nil {
@@ -572,19 +765,24 @@ func NewUserCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command
 			}
 			return nil
 		},
-	}, &cobra.Command{
+	})
+	addCmd := &cobra.Command{
 		Use:   "add ADDRESS",
 		Short: "Add user",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			reqUrl := node.UrlUser
-			param := &struct {
-				Id string `json:"id"`
-			}{Id: args[0]}
+			fs := cmd.Flags()
+			param := &node.UserParam{Id: args[0]}
+			param.Role, _ = fs.GetString("role")
+			param.Chains, _ = fs.GetStringSlice("chain")
 			addr := &common.Address{}
 			if err := addr.SetString(param.Id); err != nil {
 				return errors.Wrap(err, "invalid Address format")
 			}
+			if _, err := node.ParseRole(param.Role); err != nil {
+				return err
+			}
 			var v string
 			if _, err := adminClient.PostWithJson(reqUrl, param, &v); err != nil {
 				return err
@@ -592,7 +790,12 @@ func NewUserCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command
 			fmt.Println(v)
 			return nil
 		},
-	}, &cobra.Command{
+	}
+	rootCmd.AddCommand(addCmd)
+	addFlags := addCmd.Flags()
+	addFlags.String("role", node.RoleAdmin.String(), "Role of the user(viewer,operator,admin)")
+	addFlags.StringSlice("chain", nil, "Chain ID to apply the role(default:all chains)")
+	rootCmd.AddCommand(&cobra.Command{
 		Use:   "rm ADDRESS",
 		Short: "Remove user",
 		Args:  cobra.ExactArgs(1),
@@ -17,20 +17,24 @@
 package cli
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/node"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	v3 "github.com/icon-project/goloop/server/v3"
 )
 
 func DebugPersistentPreRunE(vc *viper.Viper, dbgClient *client.JsonRpcClient) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		if err := ValidateFlagsWithViper(vc, cmd.Flags()); err != nil {
+		if err := ValidateFlagsWithViper(vc, cmd.Flags(), "uri"); err != nil {
 			return err
 		}
 		*dbgClient = *client.NewJsonRpcClient(&http.Client{}, vc.GetString("uri"))
@@ -40,8 +44,9 @@ func DebugPersistentPreRunE(vc *viper.Viper, dbgClient *client.JsonRpcClient) fu
 
 func AddDebugRequiredFlags(c *cobra.Command) {
 	pFlags := c.PersistentFlags()
+	// "uri" is checked by DebugPersistentPreRunE instead of the annotation,
+	// because "db" commands use the node socket instead of the DEBUG API.
 	pFlags.String("uri", "", "URI of DEBUG API")
-	MarkAnnotationCustom(pFlags, "uri")
 }
 
 func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command, *viper.Viper) {
@@ -56,9 +61,12 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		Short: "Get trace of the transaction",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			param := &v3.TransactionHashParam{
+			param := &v3.TraceParam{
 				Hash: jsonrpc.HexBytes(args[0]),
 			}
+			if mode, err := cmd.Flags().GetString("mode"); err == nil {
+				param.Mode = mode
+			}
 			trace, err := debugClient.Do("debug_getTrace", param, nil)
 			if err != nil {
 				return err
@@ -66,7 +74,106 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			return JsonPrettyPrintln(os.Stdout, trace.Result)
 		},
 	}
+	traceCmd.Flags().String("mode", "",
+		"Trace mode (logs or callTree), logs by default")
 	rootCmd.AddCommand(traceCmd)
 
+	NewDebugDBCmd(rootCmd, vc)
+	return rootCmd, vc
+}
+
+func addDBRangeFlags(c *cobra.Command) {
+	flags := c.Flags()
+	flags.String("prefix", "", "Prefix of keys in hex (overrides --start and --end)")
+	flags.String("start", "", "Start key(inclusive) in hex")
+	flags.String("end", "", "End key(exclusive) in hex")
+}
+
+func dbRangeParams(cmd *cobra.Command) *url.Values {
+	params := &url.Values{}
+	for _, name := range []string{"prefix", "start", "end"} {
+		if v, _ := cmd.Flags().GetString(name); v != "" {
+			params.Add(name, v)
+		}
+	}
+	return params
+}
+
+func NewDebugDBCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command, *viper.Viper) {
+	var adminClient node.UnixDomainSockHttpClient
+	rootCmd, vc := NewCommand(parentCmd, parentVc, "db", "Inspect the database of the stopped chain")
+	rootCmd.PersistentPreRunE = AdminPersistentPreRunE(vc, &adminClient)
+	AddAdminRequiredFlags(rootCmd)
+	BindPFlags(vc, rootCmd.PersistentFlags())
+
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "ls CID",
+		Short: "List buckets",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			l := make([]*node.DBBucket, 0)
+			reqUrl := node.UrlChain + "/" + args[0] + "/db"
+			if _, err := adminClient.Get(reqUrl, &l); err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, l)
+		},
+	})
+
+	countCmd := &cobra.Command{
+		Use:   "count CID BUCKET",
+		Short: "Count keys in the bucket",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := &node.ChainDBCountView{}
+			reqUrl := node.UrlChain + "/" + args[0] + "/db/" + args[1] + "/count"
+			if _, err := adminClient.Get(reqUrl, v, dbRangeParams(cmd)); err != nil {
+				return err
+			}
+			fmt.Println(v.Count)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(countCmd)
+	addDBRangeFlags(countCmd)
+
+	dumpCmd := &cobra.Command{
+		Use:   "dump CID BUCKET",
+		Short: "Dump keys and values in the bucket",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			l := make([]*node.DBEntry, 0)
+			params := dbRangeParams(cmd)
+			limit, _ := cmd.Flags().GetInt("limit")
+			params.Add("limit", strconv.Itoa(limit))
+			reqUrl := node.UrlChain + "/" + args[0] + "/db/" + args[1]
+			if _, err := adminClient.Get(reqUrl, &l, params); err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, l)
+		},
+	}
+	rootCmd.AddCommand(dumpCmd)
+	addDBRangeFlags(dumpCmd)
+	dumpCmd.Flags().Int("limit", 100, "Maximum number of entries (0 for all)")
+
+	orphansCmd := &cobra.Command{
+		Use:   "orphans CID",
+		Short: "Find trie nodes not reachable from the blocks",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &node.ChainOrphansParam{}
+			param.Limit, _ = cmd.Flags().GetInt("limit")
+			v := &node.ChainOrphansView{}
+			reqUrl := node.UrlChain + "/" + args[0] + "/orphans"
+			if _, err := adminClient.PostWithJson(reqUrl, param, v); err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, v)
+		},
+	}
+	rootCmd.AddCommand(orphansCmd)
+	orphansCmd.Flags().Int("limit", 100, "Maximum number of keys to show")
+
 	return rootCmd, vc
 }
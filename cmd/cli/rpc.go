@@ -107,11 +107,18 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 			},
 		},
 		&cobra.Command{
-			Use:   "balance ADDRESS",
+			Use:   "balance ADDRESS [HEIGHT]",
 			Short: "GetBalance",
-			Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+			Args:  ArgsWithDefaultErrorFunc(cobra.RangeArgs(1, 2)),
 			RunE: func(cmd *cobra.Command, args []string) error {
 				param := &v3.AddressParam{Address: jsonrpc.Address(args[0])}
+				if len(args) > 1 {
+					height, err := intconv.ParseInt(args[1], 64)
+					if err != nil {
+						return err
+					}
+					param.Height = jsonrpc.HexInt(intconv.FormatInt(height))
+				}
 				balance, err := rpcClient.GetBalance(param)
 				if err != nil {
 					return err
@@ -120,11 +127,18 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 			},
 		},
 		&cobra.Command{
-			Use:   "scoreapi ADDRESS",
+			Use:   "scoreapi ADDRESS [HEIGHT]",
 			Short: "GetScoreApi",
-			Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+			Args:  ArgsWithDefaultErrorFunc(cobra.RangeArgs(1, 2)),
 			RunE: func(cmd *cobra.Command, args []string) error {
 				param := &v3.ScoreAddressParam{Address: jsonrpc.Address(args[0])}
+				if len(args) > 1 {
+					height, err := intconv.ParseInt(args[1], 64)
+					if err != nil {
+						return err
+					}
+					param.Height = jsonrpc.HexInt(intconv.FormatInt(height))
+				}
 				scoreApi, err := rpcClient.GetScoreApi(param)
 				if err != nil {
 					return err
@@ -133,11 +147,19 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 			},
 		},
 		&cobra.Command{
-			Use:   "totalsupply",
+			Use:   "totalsupply [HEIGHT]",
 			Short: "GetTotalSupply",
-			Args:  cobra.NoArgs,
+			Args:  ArgsWithDefaultErrorFunc(cobra.MaximumNArgs(1)),
 			RunE: func(cmd *cobra.Command, args []string) error {
-				supply, err := rpcClient.GetTotalSupply()
+				var param *v3.TotalSupplyParam
+				if len(args) > 0 {
+					height, err := intconv.ParseInt(args[0], 64)
+					if err != nil {
+						return err
+					}
+					param = &v3.TotalSupplyParam{Height: jsonrpc.HexInt(intconv.FormatInt(height))}
+				}
+				supply, err := rpcClient.GetTotalSupply(param)
 				if err != nil {
 					return err
 				}
@@ -170,6 +192,91 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 				return JsonPrettyPrintln(os.Stdout, tx)
 			},
 		})
+	txsByAddressCmd := &cobra.Command{
+		Use:   "txsbyaddress ADDRESS",
+		Short: "GetTransactionsByAddress",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &v3.AccountTransactionsParam{Address: jsonrpc.Address(args[0])}
+			fs := cmd.Flags()
+			if start, _ := fs.GetInt64("start"); start > 0 {
+				param.Start = jsonrpc.HexInt(intconv.FormatInt(start))
+			}
+			if limit, _ := fs.GetInt("limit"); limit > 0 {
+				param.Limit = jsonrpc.HexInt(intconv.FormatInt(int64(limit)))
+			}
+			if count, _ := fs.GetBool("count"); count {
+				cnt, err := rpcClient.GetTransactionCountByAddress(&v3.AddressParam{Address: param.Address})
+				if err != nil {
+					return err
+				}
+				return JsonPrettyPrintln(os.Stdout, cnt)
+			}
+			txs, err := rpcClient.GetTransactionsByAddress(param)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, txs)
+		},
+	}
+	rootCmd.AddCommand(txsByAddressCmd)
+	txsByAddressFlags := txsByAddressCmd.Flags()
+	txsByAddressFlags.Int64("start", 0, "Index of the first transaction")
+	txsByAddressFlags.Int("limit", 0, "Max number of transactions (0: server default)")
+	txsByAddressFlags.Bool("count", false, "Print number of transactions only")
+
+	logsCmd := &cobra.Command{
+		Use:   "logs FROM_HEIGHT [TO_HEIGHT]",
+		Short: "GetLogs",
+		Args:  ArgsWithDefaultErrorFunc(cobra.RangeArgs(1, 2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ValidateFlags(cmd.Flags()); err != nil {
+				return err
+			}
+			param := &v3.LogsParam{}
+			for i, arg := range args {
+				height, err := intconv.ParseInt(arg, 64)
+				if err != nil {
+					return err
+				}
+				if i == 0 {
+					param.FromHeight = jsonrpc.HexInt(intconv.FormatInt(height))
+				} else {
+					param.ToHeight = jsonrpc.HexInt(intconv.FormatInt(height))
+				}
+			}
+			fs := cmd.Flags()
+			param.Signature, _ = fs.GetString("event")
+			if addr, _ := fs.GetString("addr"); addr != "" {
+				param.Addr = common.NewAddressFromString(addr)
+			}
+			if evtIndexed, _ := fs.GetStringSlice("indexed"); len(evtIndexed) > 0 {
+				param.Indexed = make([]*string, len(evtIndexed))
+				for i := range evtIndexed {
+					param.Indexed[i] = &evtIndexed[i]
+				}
+			}
+			if evtData, _ := fs.GetStringSlice("data"); len(evtData) > 0 {
+				param.Data = make([]*string, len(evtData))
+				for i := range evtData {
+					param.Data[i] = &evtData[i]
+				}
+			}
+			logs, err := rpcClient.GetLogs(param)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, logs)
+		},
+	}
+	rootCmd.AddCommand(logsCmd)
+	logsFlags := logsCmd.Flags()
+	logsFlags.String("addr", "", "SCORE Address")
+	logsFlags.String("event", "", "Signature of Event")
+	logsFlags.StringSlice("indexed", nil, "Indexed Arguments of Event, comma-separated string")
+	logsFlags.StringSlice("data", nil, "Not indexed Arguments of Event, comma-separated string")
+	MarkAnnotationRequired(logsFlags, "event")
+
 	callCmd := &cobra.Command{
 		Use:   "call",
 		Short: "Call",
@@ -206,6 +313,9 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 			if len(dataM) > 0 {
 				param.Data = dataM
 			}
+			if height, _ := cmd.Flags().GetInt64("height"); height > 0 {
+				param.Height = jsonrpc.HexInt(intconv.FormatInt(height))
+			}
 			blk, err := rpcClient.Call(param)
 			if err != nil {
 				return err
@@ -225,6 +335,7 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 	callFlags.StringToString("param", nil,
 		"key=value, Function parameters, if '--raw' used, will overwrite")
 	callFlags.String("raw", "", "call with 'data' using raw json file or json-string")
+	callFlags.Int64("height", 0, "Height of the block for the state (0: last block)")
 	MarkAnnotationRequired(callFlags, "to")
 
 	rawCmd := &cobra.Command{
@@ -293,6 +404,50 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 				return JsonPrettyPrintln(os.Stdout, raw)
 			},
 		},
+		&cobra.Command{
+			Use:   "evidence [HEIGHT]",
+			Short: "GetEvidence",
+			Args:  ArgsWithDefaultErrorFunc(cobra.MaximumNArgs(1)),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				param := &v3.EvidenceParam{}
+				if len(args) > 0 {
+					height, err := intconv.ParseInt(args[0], 64)
+					if err != nil {
+						return err
+					}
+					param.Height = jsonrpc.HexInt(intconv.FormatInt(height))
+				}
+				raw, err := rpcClient.GetEvidence(param)
+				if err != nil {
+					return err
+				}
+				return JsonPrettyPrintln(os.Stdout, raw)
+			},
+		},
+		&cobra.Command{
+			Use:   "blockproof HEIGHT AT_HEIGHT",
+			Short: "GetBlockProof",
+			Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				height, err := intconv.ParseInt(args[0], 64)
+				if err != nil {
+					return err
+				}
+				atHeight, err := intconv.ParseInt(args[1], 64)
+				if err != nil {
+					return err
+				}
+				param := &v3.BlockProofParam{
+					Height:   jsonrpc.HexInt(intconv.FormatInt(height)),
+					AtHeight: jsonrpc.HexInt(intconv.FormatInt(atHeight)),
+				}
+				raw, err := rpcClient.GetBlockProof(param)
+				if err != nil {
+					return err
+				}
+				return JsonPrettyPrintln(os.Stdout, raw)
+			},
+		},
 		&cobra.Command{
 			Use:   "proofforresult HASH INDEX",
 			Short: "GetProofForResult",
@@ -773,6 +928,8 @@ func NewMonitorCmd(parentCmd *cobra.Command, parentVc *viper.Viper) *cobra.Comma
 				}
 				param.EventFilters = append(param.EventFilters, ef)
 			}
+			param.Header, _ = cmd.Flags().GetBool("header")
+			param.Votes, _ = cmd.Flags().GetBool("votes")
 			OnInterrupt(rpcClient.Cleanup)
 			err = rpcClient.MonitorBlock(param, func(v *server.BlockNotification) {
 				JsonPrettyPrintln(os.Stdout, v)
@@ -787,6 +944,8 @@ func NewMonitorCmd(parentCmd *cobra.Command, parentVc *viper.Viper) *cobra.Comma
 	monitorBlockFlags := monitorBlockCmd.Flags()
 	monitorBlockFlags.StringArray("filter", nil,
 		"EventFilter raw json file or json string")
+	monitorBlockFlags.Bool("header", false, "Include block header with decoded result")
+	monitorBlockFlags.Bool("votes", false, "Include commit votes and validators hash")
 
 	monitorEventCmd := &cobra.Command{
 		Use:   "event HEIGHT",
@@ -39,6 +39,7 @@ type GoChainConfig struct {
 	RPCAddr       string `json:"rpc_addr"`
 	RPCDump       bool   `json:"rpc_dump"`
 	RPCDebug      bool   `json:"rpc_debug"`
+	RPCBatchLimit int    `json:"rpc_batch_limit"`
 	EEInstances   int    `json:"ee_instances"`
 	Engines       string `json:"engines"`
 
@@ -112,10 +113,13 @@ func main() {
 	flag.StringVar(&cfg.RPCAddr, "rpc", ":9080", "Listen ip-port of JSON-RPC")
 	flag.BoolVar(&cfg.RPCDump, "rpc_dump", false, "JSON-RPC Request, Response Dump flag")
 	flag.BoolVar(&cfg.RPCDebug, "rpc_debug", false, "JSON-RPC Debug enable")
+	flag.IntVar(&cfg.RPCBatchLimit, "rpc_batch_limit", 0, "Max number of requests in a JSON-RPC batch (0: uses default)")
 	flag.StringVar(&cfg.SeedAddr, "seed", "", "Ip-port of Seed")
+	flag.StringVar(&cfg.StaticPeers, "static_peers", "", "Ip-port of peers always connected, Comma separated string")
+	flag.StringVar(&cfg.AllowedPeers, "allowed_peers", "", "ID or ip-port of peers allowed to connect, Comma separated string (empty: all)")
 	flag.StringVar(&genesisStorage, "genesis_storage", "", "Genesis storage path")
 	flag.StringVar(&genesisPath, "genesis", "", "Genesis template directory or file")
-	flag.StringVar(&cfg.DBType, "db_type", "goleveldb", "Name of database system (badgerdb, goleveldb, boltdb, mapdb)")
+	flag.StringVar(&cfg.DBType, "db_type", "goleveldb", "Name of database system (badgerdb, goleveldb, boltdb, mapdb, pebbledb)")
 	flag.UintVar(&cfg.Role, "role", 2, "[0:None, 1:Seed, 2:Validator, 3:Both]")
 	flag.StringVarP(&eeSocket, "ee_socket", "s", "", "Execution engine socket path (default: .chain/<address>/ee.sock)")
 	flag.StringVar(&keyStoreFile, "key_store", "", "KeyStore file for wallet")
@@ -130,6 +134,17 @@ func main() {
 	flag.IntVar(&cfg.PatchTxPoolSize, "patch_tx_pool", 0, "Patch transaction pool size")
 	flag.IntVar(&cfg.MaxBlockTxBytes, "max_block_tx_bytes", 0, "Maximum size of transactions in a block")
 	flag.StringVar(&cfg.NodeCache, "node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
+	flag.BoolVar(&cfg.AccountIndex, "account_index", false, "Enable account index for transactions by address")
+	flag.IntVar(&cfg.MaxLogsRange, "max_logs_range", 0, "Max number of blocks for icx_getLogs (0: uses default)")
+	flag.IntVar(&cfg.MaxLogsResult, "max_logs_result", 0, "Max number of results for icx_getLogs (0: uses default)")
+	flag.IntVar(&cfg.TxPoolMaxPerSender, "tx_pool_max_per_sender", 0, "Max number of pending transactions of a sender (0: unlimited)")
+	flag.StringVar(&cfg.TxPoolAllowList, "tx_pool_allow_list", "", "Senders allowed to add transactions to the pool, Comma separated string (empty: all)")
+	flag.StringVar(&cfg.TxPoolDenyList, "tx_pool_deny_list", "", "Senders denied to add transactions to the pool, Comma separated string")
+	flag.IntVar(&cfg.TxRateLimit, "tx_rate_limit", 0, "Max number of sendTransaction calls per second of a client IP (0: unlimited)")
+	flag.Int64Var(&cfg.StateRetention, "state_retention", 0, "Number of recent blocks to keep world states for (0: keep all)")
+	flag.Int64Var(&cfg.ReceiptRetention, "receipt_retention", 0, "Number of recent blocks to keep receipts for (0: keep all)")
+	flag.Int64Var(&cfg.BlockRetention, "block_retention", 0, "Number of recent blocks to keep (0: keep all)")
+	flag.Int64Var(&cfg.SnapshotInterval, "snapshot_interval", 0, "Interval of blocks to make state snapshots (0: disabled)")
 	flag.StringVar(&cfg.LogLevel, "log_level", "debug", "Main log level")
 	flag.StringVar(&cfg.ConsoleLevel, "console_level", "trace", "Console log level")
 	flag.StringToStringVar(&modLevels, "mod_level", nil, "Console log level for specific module (<mod>=<level>,...)")
@@ -457,6 +472,7 @@ func Execute(cmd *cobra.Command, args []string) {
 
 	// TODO : server-chain setting
 	srv := server.NewManager(cfg.RPCAddr, cfg.RPCDump, cfg.RPCDebug, "", wallet, logger)
+	srv.SetBatchLimit(cfg.RPCBatchLimit)
 	hex.EncodeToString(wallet.Address().ID())
 	c := chain.NewChain(wallet, nt, srv, pm, logger, &cfg.Config)
 	err = c.Init()
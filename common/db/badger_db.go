@@ -1,9 +1,11 @@
 package db
 
 import (
+	"io"
 	"path/filepath"
 
 	"github.com/dgraph-io/badger"
+	"github.com/pkg/errors"
 )
 
 func init() {
@@ -55,6 +57,72 @@ func (db *BadgerDB) Close() error {
 	return err
 }
 
+func (db *BadgerDB) NewBatch() Batch {
+	return &badgerBatch{db: db.db}
+}
+
+func (db *BadgerDB) Snapshot(dir, name string) error {
+	target, err := NewBadgerDB(name, dir)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	r, w := io.Pipe()
+	go func() {
+		_, err := db.db.Backup(w, 0)
+		w.CloseWithError(err)
+	}()
+	err = target.db.Load(r)
+	r.CloseWithError(err)
+	return err
+}
+
+func (db *BadgerDB) Dump(f func(key, value []byte) error) error {
+	return db.db.View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iter.Close()
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			item := iter.Item()
+			value, err := item.Value()
+			if err != nil {
+				return err
+			}
+			if err := f(item.Key(), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (db *BadgerDB) GetRaw(key []byte) ([]byte, error) {
+	var value []byte
+	err := db.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err == badger.ErrKeyNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	return value, err
+}
+
+func (db *BadgerDB) SetRaw(key, value []byte) error {
+	return db.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(key, value)
+	})
+}
+
+func (db *BadgerDB) DeleteRaw(key []byte) error {
+	return db.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	})
+}
+
 //----------------------------------------
 // Bucket
 
@@ -103,3 +171,114 @@ func (bucket *badgerBucket) Delete(key []byte) error {
 		return txn.Delete(ikey)
 	})
 }
+
+func (bucket *badgerBucket) NewIterator(start, limit []byte) Iterator {
+	istart, ilimit := internalRange(bucket.id, start, limit)
+	txn := bucket.db.NewTransaction(false)
+	return &badgerIterator{
+		id:    bucket.id,
+		txn:   txn,
+		iter:  txn.NewIterator(badger.DefaultIteratorOptions),
+		start: istart,
+		limit: ilimit,
+	}
+}
+
+var _ Iterator = (*badgerIterator)(nil)
+
+type badgerIterator struct {
+	id      BucketID
+	txn     *badger.Txn
+	iter    *badger.Iterator
+	start   []byte
+	limit   []byte
+	started bool
+	key     []byte
+	value   []byte
+	err     error
+}
+
+func (i *badgerIterator) Next() bool {
+	i.key, i.value = nil, nil
+	if i.err != nil {
+		return false
+	}
+	if !i.started {
+		i.started = true
+		i.iter.Seek(i.start)
+	} else {
+		i.iter.Next()
+	}
+	for ; i.iter.Valid(); i.iter.Next() {
+		item := i.iter.Item()
+		if !inRange(item.Key(), nil, i.limit) {
+			return false
+		}
+		key, ok := keyOfBucket(i.id, item.Key())
+		if !ok {
+			continue
+		}
+		value, err := item.Value()
+		if err != nil {
+			i.err = err
+			return false
+		}
+		i.key, i.value = key, value
+		return true
+	}
+	return false
+}
+
+func (i *badgerIterator) Key() []byte {
+	return i.key
+}
+
+func (i *badgerIterator) Value() []byte {
+	return i.value
+}
+
+func (i *badgerIterator) Error() error {
+	return i.err
+}
+
+func (i *badgerIterator) Release() {
+	i.iter.Close()
+	i.txn.Discard()
+}
+
+//----------------------------------------
+// Batch
+
+var _ Batch = (*badgerBatch)(nil)
+
+type badgerBatch struct {
+	batchOps
+	db *badger.DB
+}
+
+// Write applies the writes in a transaction. If they don't fit into a
+// transaction, it fails without writing any of them, because splitting them
+// breaks the atomicity of the batch.
+func (b *badgerBatch) Write() error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		for i := range b.batchOps {
+			err := applyBatchOp(txn, &b.batchOps[i])
+			if err == badger.ErrTxnTooBig {
+				return errors.Wrapf(err, "BatchTooBig(ops=%d,max=%d)",
+					len(b.batchOps), b.db.MaxBatchCount())
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func applyBatchOp(txn *badger.Txn, op *batchOp) error {
+	ikey := internalKey(op.id, op.key)
+	if op.delete {
+		return txn.Delete(ikey)
+	}
+	return txn.Set(ikey, op.value)
+}
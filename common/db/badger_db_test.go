@@ -1,10 +1,13 @@
 package db
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
 
+	"github.com/dgraph-io/badger"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -32,3 +35,34 @@ func TestBadgerDB_Database(t *testing.T) {
 	result, _ = bucket.Get(key)
 	assert.Nil(t, result, "empty")
 }
+
+func TestBadgerDB_LargeBatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "badgerdb")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testDB, err := NewBadgerDB("test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	// more entries than a transaction can have
+	cnt := int(testDB.db.MaxBatchCount()) + 100
+	batch := testDB.NewBatch()
+	for i := 0; i < cnt; i++ {
+		key := []byte(fmt.Sprintf("key%08d", i))
+		assert.NoError(t, batch.Set(BytesByHash, key, key))
+	}
+	err = batch.Write()
+	assert.True(t, errors.Is(err, badger.ErrTxnTooBig), "err=%+v", err)
+
+	// nothing is written
+	bk, _ := testDB.GetBucket(BytesByHash)
+	for _, i := range []int{0, cnt - 1} {
+		key := []byte(fmt.Sprintf("key%08d", i))
+		v, err := bk.Get(key)
+		assert.NoError(t, err)
+		assert.Nil(t, v)
+	}
+}
@@ -0,0 +1,69 @@
+package db
+
+type batchOp struct {
+	id     BucketID
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batchOps records writes of the batch for the backends applying them in
+// a transaction.
+type batchOps []batchOp
+
+func (ops *batchOps) Set(id BucketID, key []byte, value []byte) error {
+	*ops = append(*ops, batchOp{
+		id:    id,
+		key:   append([]byte(nil), key...),
+		value: append([]byte{}, value...),
+	})
+	return nil
+}
+
+func (ops *batchOps) Delete(id BucketID, key []byte) error {
+	*ops = append(*ops, batchOp{
+		id:     id,
+		key:    append([]byte(nil), key...),
+		delete: true,
+	})
+	return nil
+}
+
+// bucketBatch applies writes to the buckets of the database one by one.
+// It's used by the databases which can't apply them atomically, or which
+// need to handle each write through their buckets.
+type bucketBatch struct {
+	batchOps
+	database Database
+}
+
+func (b *bucketBatch) Write() error {
+	buckets := make(map[BucketID]Bucket)
+	for _, op := range b.batchOps {
+		bk, ok := buckets[op.id]
+		if !ok {
+			var err error
+			if bk, err = b.database.GetBucket(op.id); err != nil {
+				return err
+			}
+			buckets[op.id] = bk
+		}
+		var err error
+		if op.delete {
+			err = bk.Delete(op.key)
+		} else {
+			err = bk.Set(op.key, op.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	b.batchOps = nil
+	return nil
+}
+
+// NewBucketBatch returns a batch writing to the buckets of the database
+// on Write. The writes are not atomic.
+func NewBucketBatch(database Database) Batch {
+	return &bucketBatch{database: database}
+}
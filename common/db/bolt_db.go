@@ -49,6 +49,16 @@ func (db *BoltDB) Close() error {
 	return err
 }
 
+func (db *BoltDB) NewBatch() Batch {
+	return &boltBatch{db: db.db}
+}
+
+func (db *BoltDB) Snapshot(dir, name string) error {
+	return db.db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(filepath.Join(dir, name+".db"), 0644)
+	})
+}
+
 //----------------------------------------
 // Bucket
 
@@ -96,3 +106,91 @@ func (bucket *boltBucket) Delete(key []byte) error {
 	})
 	return err
 }
+
+func (bucket *boltBucket) NewIterator(start, limit []byte) Iterator {
+	tx, err := bucket.db.Begin(false)
+	if err != nil {
+		return NewErrorIterator(err)
+	}
+	return &boltIterator{
+		tx:     tx,
+		cursor: tx.Bucket(bucket.id).Cursor(),
+		start:  start,
+		limit:  limit,
+	}
+}
+
+var _ Iterator = (*boltIterator)(nil)
+
+type boltIterator struct {
+	tx      *bolt.Tx
+	cursor  *bolt.Cursor
+	start   []byte
+	limit   []byte
+	started bool
+	key     []byte
+	value   []byte
+}
+
+func (i *boltIterator) Next() bool {
+	if !i.started {
+		i.started = true
+		if i.start != nil {
+			i.key, i.value = i.cursor.Seek(i.start)
+		} else {
+			i.key, i.value = i.cursor.First()
+		}
+	} else if i.key != nil {
+		i.key, i.value = i.cursor.Next()
+	}
+	if i.key != nil && !inRange(i.key, nil, i.limit) {
+		i.key, i.value = nil, nil
+	}
+	return i.key != nil
+}
+
+func (i *boltIterator) Key() []byte {
+	return i.key
+}
+
+func (i *boltIterator) Value() []byte {
+	return i.value
+}
+
+func (i *boltIterator) Error() error {
+	return nil
+}
+
+func (i *boltIterator) Release() {
+	i.tx.Rollback()
+}
+
+//----------------------------------------
+// Batch
+
+var _ Batch = (*boltBatch)(nil)
+
+type boltBatch struct {
+	batchOps
+	db *bolt.DB
+}
+
+func (b *boltBatch) Write() error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		for _, op := range b.batchOps {
+			bucket, err := tx.CreateBucketIfNotExists([]byte("B" + op.id))
+			if err != nil {
+				return err
+			}
+			if op.delete {
+				err = bucket.Delete(op.key)
+			} else {
+				err = bucket.Put(op.key, op.value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
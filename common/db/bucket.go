@@ -6,6 +6,21 @@ type Bucket interface {
 	Has(key []byte) bool
 	Set(key []byte, value []byte) error
 	Delete(key []byte) error
+
+	// NewIterator returns an iterator for entries whose keys are in the
+	// range [start, limit) in the order of keys. nil start or limit means
+	// that the range isn't bounded on the side.
+	NewIterator(start, limit []byte) Iterator
+}
+
+// Iterator iterates entries of the bucket. Key and Value are valid until
+// Next is called. It should be released after use.
+type Iterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
 }
 
 type BucketID string
@@ -32,6 +47,18 @@ const (
 
 	// ChainProperty is general key value map for chain property.
 	ChainProperty BucketID = "C"
+
+	// TransactionHashByAddress maps transaction hashes from address and
+	// index. It also maps number of transactions from address.
+	TransactionHashByAddress BucketID = "A"
+
+	// EvidenceByHeight maps evidence of misbehavior of validators from
+	// height and hash of the evidence.
+	EvidenceByHeight BucketID = "E"
+
+	// BlockMTA maps states of the Merkle tree accumulator of block IDs from
+	// height, and nodes of the accumulator from their hashes.
+	BlockMTA BucketID = "M"
 )
 
 // internalKey returns key prefixed with the bucket's id.
@@ -6,14 +6,42 @@ import (
 
 type Database interface {
 	GetBucket(id BucketID) (Bucket, error)
+	NewBatch() Batch
 	Close() error
 }
 
+// Batch groups writes to buckets of the database. They are not visible
+// until Write is called, then they are applied atomically if the backend
+// supports it. BadgerDB fails to write a batch which doesn't fit into a
+// transaction. The batch can't be used after Write.
+type Batch interface {
+	Set(id BucketID, key []byte, value []byte) error
+	Delete(id BucketID, key []byte) error
+	Write() error
+}
+
 type LayerDB interface {
 	Database
 	Flush(write bool) error
 }
 
+// Snapshotter is implemented by databases which can write a consistent copy
+// of themselves while they are in use. The copy is written as a database of
+// the same backend with the name under the directory.
+type Snapshotter interface {
+	Snapshot(dir, name string) error
+}
+
+// Dumper is implemented by databases which can access entries with internal
+// keys, which are keys prefixed with bucket ids. It's used to transfer all
+// entries between databases of the same backend.
+type Dumper interface {
+	Dump(f func(key, value []byte) error) error
+	GetRaw(key []byte) ([]byte, error)
+	SetRaw(key, value []byte) error
+	DeleteRaw(key []byte) error
+}
+
 type BackendType string
 
 const (
@@ -21,6 +49,7 @@ const (
 	GoLevelDBBackend BackendType = "goleveldb"
 	BoltDBBackend    BackendType = "boltdb"
 	MapDBBackend     BackendType = "mapdb"
+	PebbleDBBackend  BackendType = "pebbledb"
 )
 
 type dbCreator func(name string, dir string) (Database, error)
@@ -0,0 +1,245 @@
+package db
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabase_Snapshot(t *testing.T) {
+	for _, backend := range []BackendType{
+		GoLevelDBBackend, BadgerDBBackend, BoltDBBackend, PebbleDBBackend,
+	} {
+		t.Run(string(backend), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", string(backend))
+			if err != nil {
+				panic(err)
+			}
+			defer os.RemoveAll(dir)
+
+			testDB, err := openDatabase(backend, "test", dir)
+			assert.NoError(t, err)
+			defer testDB.Close()
+
+			bk1, _ := testDB.GetBucket(BytesByHash)
+			bk2, _ := testDB.GetBucket(ChainProperty)
+			assert.NoError(t, bk1.Set([]byte("key1"), []byte("value1")))
+			assert.NoError(t, bk2.Set([]byte("key2"), []byte("value2")))
+
+			snapDir, err := ioutil.TempDir(dir, "snapshot")
+			assert.NoError(t, err)
+
+			s, ok := testDB.(Snapshotter)
+			assert.True(t, ok)
+			assert.NoError(t, s.Snapshot(snapDir, "test"))
+
+			// changes after the snapshot must not affect it
+			assert.NoError(t, bk1.Set([]byte("key3"), []byte("value3")))
+
+			snapDB, err := openDatabase(backend, "test", snapDir)
+			assert.NoError(t, err)
+			defer snapDB.Close()
+
+			sbk1, _ := snapDB.GetBucket(BytesByHash)
+			sbk2, _ := snapDB.GetBucket(ChainProperty)
+			v, err := sbk1.Get([]byte("key1"))
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value1"), v)
+			v, err = sbk2.Get([]byte("key2"))
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value2"), v)
+			assert.False(t, sbk1.Has([]byte("key3")))
+		})
+	}
+}
+
+func TestDatabase_Dump(t *testing.T) {
+	for _, backend := range []BackendType{
+		GoLevelDBBackend, BadgerDBBackend, PebbleDBBackend,
+	} {
+		t.Run(string(backend), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", string(backend))
+			if err != nil {
+				panic(err)
+			}
+			defer os.RemoveAll(dir)
+
+			testDB, err := openDatabase(backend, "test", dir)
+			assert.NoError(t, err)
+			defer testDB.Close()
+
+			bk, _ := testDB.GetBucket(ChainProperty)
+			assert.NoError(t, bk.Set([]byte("key1"), []byte("value1")))
+
+			d, ok := testDB.(Dumper)
+			assert.True(t, ok)
+
+			entries := make(map[string]string)
+			assert.NoError(t, d.Dump(func(key, value []byte) error {
+				entries[string(key)] = string(value)
+				return nil
+			}))
+			assert.Equal(t, map[string]string{"Ckey1": "value1"}, entries)
+
+			// raw keys are prefixed with bucket ids
+			assert.NoError(t, d.SetRaw([]byte("Ckey2"), []byte("value2")))
+			v, err := bk.Get([]byte("key2"))
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value2"), v)
+
+			v, err = d.GetRaw([]byte("Ckey1"))
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value1"), v)
+			v, err = d.GetRaw([]byte("Ckey3"))
+			assert.NoError(t, err)
+			assert.Nil(t, v)
+
+			assert.NoError(t, d.DeleteRaw([]byte("Ckey1")))
+			assert.False(t, bk.Has([]byte("key1")))
+		})
+	}
+}
+
+func TestDatabase_Batch(t *testing.T) {
+	for _, backend := range []BackendType{
+		GoLevelDBBackend, BadgerDBBackend, BoltDBBackend, PebbleDBBackend,
+		MapDBBackend,
+	} {
+		t.Run(string(backend), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", string(backend))
+			if err != nil {
+				panic(err)
+			}
+			defer os.RemoveAll(dir)
+
+			testDB, err := openDatabase(backend, "test", dir)
+			assert.NoError(t, err)
+			defer testDB.Close()
+
+			bk1, _ := testDB.GetBucket(BytesByHash)
+			bk2, _ := testDB.GetBucket(ChainProperty)
+			assert.NoError(t, bk2.Set([]byte("key3"), []byte("value3")))
+
+			batch := testDB.NewBatch()
+			assert.NoError(t, batch.Set(BytesByHash, []byte("key1"), []byte("value1")))
+			assert.NoError(t, batch.Set(ChainProperty, []byte("key2"), []byte("value2")))
+			assert.NoError(t, batch.Delete(ChainProperty, []byte("key3")))
+
+			// writes are not visible before Write
+			assert.False(t, bk1.Has([]byte("key1")))
+			assert.True(t, bk2.Has([]byte("key3")))
+
+			assert.NoError(t, batch.Write())
+			v, err := bk1.Get([]byte("key1"))
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value1"), v)
+			v, err = bk2.Get([]byte("key2"))
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value2"), v)
+			assert.False(t, bk2.Has([]byte("key3")))
+		})
+	}
+}
+
+type batchCounter struct {
+	Database
+	writes int
+}
+
+func (d *batchCounter) NewBatch() Batch {
+	d.writes += 1
+	return d.Database.NewBatch()
+}
+
+func TestLayerDB_FlushWithBatch(t *testing.T) {
+	real := &batchCounter{Database: NewMapDB()}
+	ldb := NewLayerDB(real)
+
+	bk1, _ := ldb.GetBucket(BytesByHash)
+	bk2, _ := ldb.GetBucket(ChainProperty)
+	assert.NoError(t, bk1.Set([]byte("key1"), []byte("value1")))
+	assert.NoError(t, bk2.Set([]byte("key2"), []byte("value2")))
+	assert.NoError(t, bk2.Delete([]byte("key2")))
+
+	rbk1, _ := real.GetBucket(BytesByHash)
+	assert.False(t, rbk1.Has([]byte("key1")))
+
+	assert.NoError(t, ldb.Flush(true))
+	assert.Equal(t, 1, real.writes)
+	v, err := rbk1.Get([]byte("key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value1"), v)
+	rbk2, _ := real.GetBucket(ChainProperty)
+	assert.False(t, rbk2.Has([]byte("key2")))
+}
+
+func collectEntries(t *testing.T, it Iterator) []string {
+	defer it.Release()
+	var entries []string
+	for it.Next() {
+		entries = append(entries, string(it.Key())+"="+string(it.Value()))
+	}
+	assert.NoError(t, it.Error())
+	return entries
+}
+
+func TestBucket_Iterator(t *testing.T) {
+	for _, backend := range []BackendType{
+		GoLevelDBBackend, BadgerDBBackend, BoltDBBackend, PebbleDBBackend,
+		MapDBBackend,
+	} {
+		t.Run(string(backend), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", string(backend))
+			if err != nil {
+				panic(err)
+			}
+			defer os.RemoveAll(dir)
+
+			testDB, err := openDatabase(backend, "test", dir)
+			assert.NoError(t, err)
+			defer testDB.Close()
+
+			bk, _ := testDB.GetBucket(ChainProperty)
+			for _, k := range []string{"b2", "a1", "b1", "c1", "b3"} {
+				assert.NoError(t, bk.Set([]byte(k), []byte("v"+k)))
+			}
+			other, _ := testDB.GetBucket(BytesByHash)
+			assert.NoError(t, other.Set([]byte("b4"), []byte("vb4")))
+			hash := []byte("0123456789abcdef0123456789abcdef")
+			mt, _ := testDB.GetBucket(MerkleTrie)
+			assert.NoError(t, mt.Set(hash, []byte("node")))
+
+			assert.Equal(t,
+				[]string{"a1=va1", "b1=vb1", "b2=vb2", "b3=vb3", "c1=vc1"},
+				collectEntries(t, bk.NewIterator(nil, nil)))
+			assert.Equal(t,
+				[]string{"b1=vb1", "b2=vb2", "b3=vb3"},
+				collectEntries(t, bk.NewIterator(BytesPrefix([]byte("b")))))
+			assert.Equal(t,
+				[]string{"b2=vb2", "b3=vb3"},
+				collectEntries(t, bk.NewIterator([]byte("b2"), []byte("c1"))))
+			assert.Equal(t,
+				[]string{string(hash) + "=node"},
+				collectEntries(t, mt.NewIterator(nil, nil)))
+
+			ldb := NewLayerDB(testDB)
+			lbk, _ := ldb.GetBucket(ChainProperty)
+			assert.NoError(t, lbk.Set([]byte("b0"), []byte("vb0")))
+			assert.NoError(t, lbk.Set([]byte("b2"), []byte("new")))
+			assert.NoError(t, lbk.Delete([]byte("b3")))
+			assert.Equal(t,
+				[]string{"b0=vb0", "b1=vb1", "b2=new"},
+				collectEntries(t, lbk.NewIterator(BytesPrefix([]byte("b")))))
+		})
+	}
+}
+
+func TestBytesPrefix(t *testing.T) {
+	start, limit := BytesPrefix([]byte{0x01, 0xff})
+	assert.Equal(t, []byte{0x01, 0xff}, start)
+	assert.Equal(t, []byte{0x02}, limit)
+	_, limit = BytesPrefix([]byte{0xff, 0xff})
+	assert.Nil(t, limit)
+}
@@ -4,7 +4,9 @@ import (
 	"path/filepath"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 func init() {
@@ -50,6 +52,71 @@ func (db *GoLevelDB) Close() error {
 	return db.db.Close()
 }
 
+func (db *GoLevelDB) NewBatch() Batch {
+	return &goLevelBatch{db: db.db}
+}
+
+const snapshotBatchSize = 1024
+
+func (db *GoLevelDB) Snapshot(dir, name string) error {
+	snap, err := db.db.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snap.Release()
+
+	target, err := leveldb.OpenFile(filepath.Join(dir, name), nil)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	iter := snap.NewIterator(nil, nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		batch.Put(iter.Key(), iter.Value())
+		if batch.Len() >= snapshotBatchSize {
+			if err := target.Write(batch, nil); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return target.Write(batch, nil)
+}
+
+func (db *GoLevelDB) Dump(f func(key, value []byte) error) error {
+	iter := db.db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		if err := f(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+func (db *GoLevelDB) GetRaw(key []byte) ([]byte, error) {
+	value, err := db.db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (db *GoLevelDB) SetRaw(key, value []byte) error {
+	return db.db.Put(key, value, nil)
+}
+
+func (db *GoLevelDB) DeleteRaw(key []byte) error {
+	return db.db.Delete(key, nil)
+}
+
 //----------------------------------------
 // GetBucket
 
@@ -84,3 +151,73 @@ func (bucket *goLevelBucket) Set(key []byte, value []byte) error {
 func (bucket *goLevelBucket) Delete(key []byte) error {
 	return bucket.db.Delete(internalKey(bucket.id, key), nil)
 }
+
+func (bucket *goLevelBucket) NewIterator(start, limit []byte) Iterator {
+	istart, ilimit := internalRange(bucket.id, start, limit)
+	return &goLevelIterator{
+		id:   bucket.id,
+		iter: bucket.db.NewIterator(&util.Range{Start: istart, Limit: ilimit}, nil),
+	}
+}
+
+var _ Iterator = (*goLevelIterator)(nil)
+
+type goLevelIterator struct {
+	id   BucketID
+	iter iterator.Iterator
+	key  []byte
+}
+
+func (i *goLevelIterator) Next() bool {
+	for i.iter.Next() {
+		if key, ok := keyOfBucket(i.id, i.iter.Key()); ok {
+			i.key = key
+			return true
+		}
+	}
+	i.key = nil
+	return false
+}
+
+func (i *goLevelIterator) Key() []byte {
+	return i.key
+}
+
+func (i *goLevelIterator) Value() []byte {
+	if i.key == nil {
+		return nil
+	}
+	return i.iter.Value()
+}
+
+func (i *goLevelIterator) Error() error {
+	return i.iter.Error()
+}
+
+func (i *goLevelIterator) Release() {
+	i.iter.Release()
+}
+
+//----------------------------------------
+// Batch
+
+var _ Batch = (*goLevelBatch)(nil)
+
+type goLevelBatch struct {
+	db    *leveldb.DB
+	batch leveldb.Batch
+}
+
+func (b *goLevelBatch) Set(id BucketID, key []byte, value []byte) error {
+	b.batch.Put(internalKey(id, key), value)
+	return nil
+}
+
+func (b *goLevelBatch) Delete(id BucketID, key []byte) error {
+	b.batch.Delete(internalKey(id, key))
+	return nil
+}
+
+func (b *goLevelBatch) Write() error {
+	return b.db.Write(&b.batch, nil)
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"bytes"
+	"sort"
+)
+
+// merkleTrieKeySize is the size of keys in MerkleTrie. Keys of the bucket
+// don't have the prefix, so they share the key space with the other
+// buckets on the backends using internal keys. Iterators for the bucket
+// skip keys which can't be hashes.
+const merkleTrieKeySize = 32
+
+// BytesPrefix returns the range of keys having the prefix.
+func BytesPrefix(prefix []byte) (start, limit []byte) {
+	return prefix, bytesLimit(prefix)
+}
+
+// bytesLimit returns the smallest key which is larger than all keys having
+// the prefix. It returns nil if there is no such key.
+func bytesLimit(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if c := prefix[i]; c < 0xff {
+			limit := make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i] = c + 1
+			return limit
+		}
+	}
+	return nil
+}
+
+// internalRange returns the range of internal keys for the range of keys
+// in the bucket.
+func internalRange(id BucketID, start, limit []byte) ([]byte, []byte) {
+	istart := internalKey(id, start)
+	var ilimit []byte
+	if limit != nil {
+		ilimit = internalKey(id, limit)
+	} else {
+		ilimit = bytesLimit([]byte(id))
+	}
+	return istart, ilimit
+}
+
+// keyOfBucket returns the key in the bucket for the internal key. It
+// returns false if the internal key doesn't belong to the bucket.
+func keyOfBucket(id BucketID, ikey []byte) ([]byte, bool) {
+	if id == MerkleTrie {
+		return ikey, len(ikey) == merkleTrieKeySize
+	}
+	if !bytes.HasPrefix(ikey, []byte(id)) {
+		return nil, false
+	}
+	return ikey[len(id):], true
+}
+
+func inRange(key, start, limit []byte) bool {
+	return (start == nil || bytes.Compare(key, start) >= 0) &&
+		(limit == nil || bytes.Compare(key, limit) < 0)
+}
+
+type errorIterator struct {
+	err error
+}
+
+func (i errorIterator) Next() bool {
+	return false
+}
+
+func (i errorIterator) Key() []byte {
+	return nil
+}
+
+func (i errorIterator) Value() []byte {
+	return nil
+}
+
+func (i errorIterator) Error() error {
+	return i.err
+}
+
+func (i errorIterator) Release() {
+}
+
+// NewErrorIterator returns an empty iterator returning the error. It's
+// used by the buckets which can't be iterated.
+func NewErrorIterator(err error) Iterator {
+	return errorIterator{err}
+}
+
+type entry struct {
+	key   []byte
+	value []byte
+}
+
+// sliceIterator iterates sorted entries.
+type sliceIterator struct {
+	entries []entry
+	index   int
+}
+
+func (i *sliceIterator) Next() bool {
+	if i.index < len(i.entries) {
+		i.index += 1
+	}
+	return i.index < len(i.entries)
+}
+
+func (i *sliceIterator) Key() []byte {
+	if i.index < 0 || i.index >= len(i.entries) {
+		return nil
+	}
+	return i.entries[i.index].key
+}
+
+func (i *sliceIterator) Value() []byte {
+	if i.index < 0 || i.index >= len(i.entries) {
+		return nil
+	}
+	return i.entries[i.index].value
+}
+
+func (i *sliceIterator) Error() error {
+	return nil
+}
+
+func (i *sliceIterator) Release() {
+	i.entries = nil
+	i.index = 0
+}
+
+func newSliceIterator(entries []entry) *sliceIterator {
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	return &sliceIterator{entries: entries, index: -1}
+}
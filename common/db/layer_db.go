@@ -1,12 +1,15 @@
 package db
 
 import (
-	"github.com/pkg/errors"
+	"bytes"
 	"sync"
+
+	"github.com/pkg/errors"
 )
 
 type layerBucket struct {
 	lock sync.Mutex
+	id   BucketID
 	data map[string][]byte
 	real Bucket
 }
@@ -65,18 +68,38 @@ func (bk *layerBucket) Delete(key []byte) error {
 	}
 }
 
-func (bk *layerBucket) Flush(write bool) error {
+func (bk *layerBucket) NewIterator(start, limit []byte) Iterator {
+	bk.lock.Lock()
+	defer bk.lock.Unlock()
+
+	if bk.data == nil {
+		return bk.real.NewIterator(start, limit)
+	}
+	var entries []entry
+	for k, v := range bk.data {
+		if inRange([]byte(k), start, limit) {
+			entries = append(entries, entry{[]byte(k), v})
+		}
+	}
+	return &layerIterator{
+		layer: newSliceIterator(entries),
+		real:  bk.real.NewIterator(start, limit),
+	}
+}
+
+// flushTo adds changes of the bucket to the batch, then clears them.
+func (bk *layerBucket) flushTo(batch Batch) error {
 	bk.lock.Lock()
 	defer bk.lock.Unlock()
 
-	if write && bk.data != nil {
+	if batch != nil && bk.data != nil {
 		for k, v := range bk.data {
 			if v == nil {
-				if err := bk.real.Delete([]byte(k)); err != nil {
+				if err := batch.Delete(bk.id, []byte(k)); err != nil {
 					return err
 				}
 			} else {
-				if err := bk.real.Set([]byte(k), v); err != nil {
+				if err := batch.Set(bk.id, []byte(k), v); err != nil {
 					return err
 				}
 			}
@@ -86,6 +109,73 @@ func (bk *layerBucket) Flush(write bool) error {
 	return nil
 }
 
+// layerIterator merges entries of the layer into ones of the real bucket.
+// Entries of the layer with nil value are deleted ones.
+type layerIterator struct {
+	layer, real         Iterator
+	lvalid, rvalid      bool
+	started             bool
+	nextLayer, nextReal bool
+	key, value          []byte
+}
+
+func (i *layerIterator) Next() bool {
+	if !i.started {
+		i.started = true
+		i.lvalid, i.rvalid = i.layer.Next(), i.real.Next()
+	}
+	if i.nextLayer {
+		i.lvalid, i.nextLayer = i.layer.Next(), false
+	}
+	if i.nextReal {
+		i.rvalid, i.nextReal = i.real.Next(), false
+	}
+	for i.lvalid || i.rvalid {
+		var c int
+		switch {
+		case !i.rvalid:
+			c = -1
+		case !i.lvalid:
+			c = 1
+		default:
+			c = bytes.Compare(i.layer.Key(), i.real.Key())
+		}
+		if c > 0 {
+			i.key, i.value = i.real.Key(), i.real.Value()
+			i.nextReal = true
+			return true
+		}
+		if c == 0 {
+			i.rvalid = i.real.Next()
+		}
+		if v := i.layer.Value(); v != nil {
+			i.key, i.value = i.layer.Key(), v
+			i.nextLayer = true
+			return true
+		}
+		i.lvalid = i.layer.Next()
+	}
+	i.key, i.value = nil, nil
+	return false
+}
+
+func (i *layerIterator) Key() []byte {
+	return i.key
+}
+
+func (i *layerIterator) Value() []byte {
+	return i.value
+}
+
+func (i *layerIterator) Error() error {
+	return i.real.Error()
+}
+
+func (i *layerIterator) Release() {
+	i.layer.Release()
+	i.real.Release()
+}
+
 type layerDB struct {
 	lock sync.Mutex
 
@@ -110,6 +200,7 @@ func (ldb *layerDB) GetBucket(id BucketID) (Bucket, error) {
 		return realbk, nil
 	}
 	bk := &layerBucket{
+		id:   id,
 		data: make(map[string][]byte),
 		real: realbk,
 	}
@@ -117,12 +208,24 @@ func (ldb *layerDB) GetBucket(id BucketID) (Bucket, error) {
 	return bk, nil
 }
 
+// Flush writes changes of all buckets to the real database in a batch if
+// write is true, then drops them. After that, the layer passes through
+// the real database.
 func (ldb *layerDB) Flush(write bool) error {
 	ldb.lock.Lock()
 	defer ldb.lock.Unlock()
 
+	var batch Batch
+	if write {
+		batch = ldb.real.NewBatch()
+	}
 	for _, bk := range ldb.buckets {
-		if err := bk.Flush(write); err != nil {
+		if err := bk.flushTo(batch); err != nil {
+			return err
+		}
+	}
+	if batch != nil {
+		if err := batch.Write(); err != nil {
 			return err
 		}
 	}
@@ -130,6 +233,16 @@ func (ldb *layerDB) Flush(write bool) error {
 	return nil
 }
 
+func (ldb *layerDB) NewBatch() Batch {
+	ldb.lock.Lock()
+	defer ldb.lock.Unlock()
+
+	if ldb.flushed {
+		return ldb.real.NewBatch()
+	}
+	return NewBucketBatch(ldb)
+}
+
 func (ldb *layerDB) Close() error {
 	return nil
 }
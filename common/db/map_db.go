@@ -60,6 +60,10 @@ func (t *mapDatabase) Close() error {
 	return nil
 }
 
+func (t *mapDatabase) NewBatch() Batch {
+	return NewBucketBatch(t)
+}
+
 //----------------------------------------
 // Bucket
 
@@ -120,3 +124,15 @@ func (t *mapBucket) Delete(k []byte) error {
 	delete(t.real, string(k))
 	return nil
 }
+
+func (t *mapBucket) NewIterator(start, limit []byte) Iterator {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var entries []entry
+	for k, v := range t.real {
+		if inRange([]byte(k), start, limit) {
+			entries = append(entries, entry{[]byte(k), []byte(v)})
+		}
+	}
+	return newSliceIterator(entries)
+}
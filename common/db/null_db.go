@@ -11,6 +11,10 @@ func (*nullDB) Close() error {
 	return nil
 }
 
+func (db *nullDB) NewBatch() Batch {
+	return NewBucketBatch(db)
+}
+
 type nullBucket struct {
 }
 
@@ -30,6 +34,10 @@ func (*nullBucket) Delete(key []byte) error {
 	panic("NullBucket.Delete() Unsupported")
 }
 
+func (*nullBucket) NewIterator(start, limit []byte) Iterator {
+	return newSliceIterator(nil)
+}
+
 func NewNullDB() *nullDB {
 	return &nullDB{}
 }
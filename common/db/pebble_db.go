@@ -0,0 +1,229 @@
+package db
+
+import (
+	"path/filepath"
+
+	"github.com/cockroachdb/pebble"
+)
+
+func init() {
+	dbCreator := func(name string, dir string) (Database, error) {
+		return NewPebbleDB(name, dir)
+	}
+	registerDBCreator(PebbleDBBackend, dbCreator, false)
+}
+
+func NewPebbleDB(name string, dir string) (*PebbleDB, error) {
+	return NewPebbleDBWithOpts(name, dir, nil)
+}
+
+func NewPebbleDBWithOpts(name string, dir string, o *pebble.Options) (*PebbleDB, error) {
+	dbPath := filepath.Join(dir, name)
+	db, err := pebble.Open(dbPath, o)
+	if err != nil {
+		return nil, err
+	}
+	database := &PebbleDB{
+		db: db,
+	}
+	return database, nil
+}
+
+//----------------------------------------
+// Database
+
+var _ Database = (*PebbleDB)(nil)
+
+type PebbleDB struct {
+	db *pebble.DB
+}
+
+func (db *PebbleDB) GetBucket(id BucketID) (Bucket, error) {
+	return &pebbleBucket{
+		id: id,
+		db: db.db,
+	}, nil
+}
+
+func (db *PebbleDB) Close() error {
+	return db.db.Close()
+}
+
+func (db *PebbleDB) NewBatch() Batch {
+	return &pebbleBatch{batch: db.db.NewBatch()}
+}
+
+func (db *PebbleDB) Snapshot(dir, name string) error {
+	snap := db.db.NewSnapshot()
+	defer snap.Close()
+
+	target, err := pebble.Open(filepath.Join(dir, name), nil)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	iter := snap.NewIter(nil)
+	defer iter.Close()
+
+	batch := target.NewBatch()
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := batch.Set(iter.Key(), iter.Value(), nil); err != nil {
+			return err
+		}
+		if batch.Count() >= snapshotBatchSize {
+			if err := batch.Commit(pebble.NoSync); err != nil {
+				return err
+			}
+			batch = target.NewBatch()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+func (db *PebbleDB) Dump(f func(key, value []byte) error) error {
+	iter := db.db.NewIter(nil)
+	defer iter.Close()
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := f(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+func (db *PebbleDB) GetRaw(key []byte) ([]byte, error) {
+	return pebbleGet(db.db, key)
+}
+
+func (db *PebbleDB) SetRaw(key, value []byte) error {
+	return db.db.Set(key, value, pebble.NoSync)
+}
+
+func (db *PebbleDB) DeleteRaw(key []byte) error {
+	return db.db.Delete(key, pebble.NoSync)
+}
+
+func pebbleGet(db *pebble.DB, key []byte) ([]byte, error) {
+	value, closer, err := db.Get(key)
+	if err == pebble.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return append([]byte{}, value...), nil
+}
+
+//----------------------------------------
+// Bucket
+
+var _ Bucket = (*pebbleBucket)(nil)
+
+type pebbleBucket struct {
+	id BucketID
+	db *pebble.DB
+}
+
+func (bucket *pebbleBucket) Get(key []byte) ([]byte, error) {
+	return pebbleGet(bucket.db, internalKey(bucket.id, key))
+}
+
+func (bucket *pebbleBucket) Has(key []byte) bool {
+	_, closer, err := bucket.db.Get(internalKey(bucket.id, key))
+	if err != nil {
+		return false
+	}
+	closer.Close()
+	return true
+}
+
+func (bucket *pebbleBucket) Set(key []byte, value []byte) error {
+	return bucket.db.Set(internalKey(bucket.id, key), value, pebble.NoSync)
+}
+
+func (bucket *pebbleBucket) Delete(key []byte) error {
+	return bucket.db.Delete(internalKey(bucket.id, key), pebble.NoSync)
+}
+
+func (bucket *pebbleBucket) NewIterator(start, limit []byte) Iterator {
+	istart, ilimit := internalRange(bucket.id, start, limit)
+	return &pebbleIterator{
+		id: bucket.id,
+		iter: bucket.db.NewIter(&pebble.IterOptions{
+			LowerBound: istart,
+			UpperBound: ilimit,
+		}),
+	}
+}
+
+var _ Iterator = (*pebbleIterator)(nil)
+
+type pebbleIterator struct {
+	id      BucketID
+	iter    *pebble.Iterator
+	started bool
+	key     []byte
+}
+
+func (i *pebbleIterator) Next() bool {
+	var valid bool
+	if !i.started {
+		i.started = true
+		valid = i.iter.First()
+	} else {
+		valid = i.iter.Next()
+	}
+	for ; valid; valid = i.iter.Next() {
+		if key, ok := keyOfBucket(i.id, i.iter.Key()); ok {
+			i.key = key
+			return true
+		}
+	}
+	i.key = nil
+	return false
+}
+
+func (i *pebbleIterator) Key() []byte {
+	return i.key
+}
+
+func (i *pebbleIterator) Value() []byte {
+	if i.key == nil {
+		return nil
+	}
+	return i.iter.Value()
+}
+
+func (i *pebbleIterator) Error() error {
+	return i.iter.Error()
+}
+
+func (i *pebbleIterator) Release() {
+	i.iter.Close()
+}
+
+//----------------------------------------
+// Batch
+
+var _ Batch = (*pebbleBatch)(nil)
+
+type pebbleBatch struct {
+	batch *pebble.Batch
+}
+
+func (b *pebbleBatch) Set(id BucketID, key []byte, value []byte) error {
+	return b.batch.Set(internalKey(id, key), value, nil)
+}
+
+func (b *pebbleBatch) Delete(id BucketID, key []byte) error {
+	return b.batch.Delete(internalKey(id, key), nil)
+}
+
+func (b *pebbleBatch) Write() error {
+	defer b.batch.Close()
+	return b.batch.Commit(pebble.NoSync)
+}
@@ -36,6 +36,13 @@ func (bk *proxyBucket) Delete(key []byte) error {
 	return errors.New("ProxyIsNotRealized")
 }
 
+func (bk *proxyBucket) NewIterator(start, limit []byte) Iterator {
+	if bk.real != nil {
+		return bk.real.NewIterator(start, limit)
+	}
+	return NewErrorIterator(errors.New("ProxyIsNotRealized"))
+}
+
 type proxyDB struct {
 	real    Database
 	buckets map[string]*proxyBucket
@@ -61,6 +68,13 @@ func (pdb *proxyDB) Close() error {
 	return nil
 }
 
+func (pdb *proxyDB) NewBatch() Batch {
+	if pdb.real != nil {
+		return pdb.real.NewBatch()
+	}
+	return NewBucketBatch(pdb)
+}
+
 func (pdb *proxyDB) SetReal(database Database) error {
 	pdb.real = database
 	for _, bk := range pdb.buckets {
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
 )
 
 const (
@@ -43,6 +44,15 @@ func (m *databaseWithCacheManager) getAccountNodeCache(id []byte) *NodeCache {
 	}
 }
 
+// Snapshot writes a copy of the underlying database. Node caches are not
+// included.
+func (m *databaseWithCacheManager) Snapshot(dir, name string) error {
+	if s, ok := m.Database.(db.Snapshotter); ok {
+		return s.Snapshot(dir, name)
+	}
+	return errors.ErrUnsupported
+}
+
 // WorldNodeCacheOf get node cache of the world if it has.
 // If node cache for world state is not enabled, it returns nil.
 func WorldNodeCacheOf(database db.Database) *NodeCache {
@@ -62,3 +62,33 @@ func TestMTAccumulator_Basic(t *testing.T) {
 
 	t.Logf("%s", a)
 }
+
+func TestMTAccumulator_EmptyRoots(t *testing.T) {
+	mdb := db.NewMapDB()
+	bk, _ := mdb.GetBucket("")
+
+	data := []string{"dog", "cat", "elephant", "bird", "monkey", "lion"}
+	for n := 1; n <= len(data); n++ {
+		a := &Accumulator{
+			KeyForState: []byte("a"),
+			Bucket:      bk,
+		}
+		for _, d := range data[:n] {
+			a.AddData([]byte(d))
+		}
+		assert.NoError(t, a.Flush())
+
+		a = &Accumulator{
+			KeyForState: []byte("a"),
+			Bucket:      bk,
+		}
+		assert.NoError(t, a.Recover())
+		assert.Equal(t, int64(n), a.Len())
+		for i, d := range data[:n] {
+			w, err := a.WitnessFor(int64(i))
+			assert.NoError(t, err)
+			w = HashesToWitness(WitnessesToHashes(w), int64(i))
+			assert.NoError(t, a.Verify(w, crypto.SHA3Sum256([]byte(d))))
+		}
+	}
+}
@@ -45,6 +45,9 @@ type (
 		root  node
 		mutex sync.Mutex
 		s     *mptStatics
+
+		// batch collects nodes written while it's flushing.
+		batch db.Batch
 	}
 )
 
@@ -170,7 +173,12 @@ func (m *mpt) Flush() error {
 		// Before flush node data to Database, We need to make sure that it
 		// builds required  data for dumping data.
 		m.root.getLink(true)
+		m.batch = m.db.NewBatch()
 		err := m.root.flush(m, make([]byte, 0, hashSize*2))
+		if err == nil {
+			err = m.batch.Write()
+		}
+		m.batch = nil
 		if logStatics {
 			if m.s.back == nil {
 				m.s = &mptStatics{
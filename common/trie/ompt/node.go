@@ -7,6 +7,7 @@ import (
 
 	"golang.org/x/crypto/sha3"
 
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/merkle"
 	"github.com/icon-project/goloop/common/trie"
@@ -109,7 +110,7 @@ func (n *nodeBase) flushBaseInLock(m *mpt, nibs []byte) error {
 		if logStatics {
 			atomic.AddInt32(&m.s.write, 1)
 		}
-		if err := m.bucket.Set(n.hashValue, n.serialized); err != nil {
+		if err := m.batch.Set(db.MerkleTrie, n.hashValue, n.serialized); err != nil {
 			return err
 		}
 		m.cache.Put(nibs, n.hashValue, n.serialized)
@@ -27,6 +27,7 @@ var csProtocols = []module.ProtocolInfo{
 	protoBlockPart,
 	protoVote,
 	protoVoteList,
+	protoEvidence,
 }
 
 const (
@@ -145,6 +146,7 @@ type consensus struct {
 	sentPatch          bool
 	lastVotes          *voteSet
 	hvs                heightVoteSet
+	evidences          *evidenceStore
 	nextProposeTime    time.Time
 	lockedRound        int32
 	lockedBlockParts   blockPartSet
@@ -234,6 +236,11 @@ func (cs *consensus) _resetForNewHeight(prevBlock module.Block, votes *voteSet)
 func (cs *consensus) resetForNewHeight(prevBlock module.Block, votes *voteSet) {
 	cs.endStep()
 	cs._resetForNewHeight(prevBlock, votes)
+	if cs.evidences != nil {
+		if err := cs.evidences.prune(cs.height - module.EvidenceExpiry); err != nil {
+			cs.logger.Warnf("fail to prune evidences. %+v\n", err)
+		}
+	}
 	cs._resetForNewRound(0)
 	cs.beginStep(stepNewHeight)
 }
@@ -307,11 +314,13 @@ func (cs *consensus) OnReceive(
 	msg, err := unmarshalMessage(sp.Uint16(), bs)
 	if err != nil {
 		cs.logger.Warnf("malformed consensus message: OnReceive(subprotocol:%v, from:%v): %+v\n", sp, common.HexPre(id.Bytes()), err)
+		cs.ph.Penalize(id, module.PenaltyMajor, "MalformedMessage")
 		return false, err
 	}
 	cs.logger.Debugf("OnReceive(msg:%v, from:%v)\n", msg, common.HexPre(id.Bytes()))
 	if err = msg.verify(); err != nil {
 		cs.logger.Warnf("consensus message verify failed: OnReceive(msg:%v, from:%v): %+v\n", msg, common.HexPre(id.Bytes()), err)
+		cs.ph.Penalize(id, module.PenaltyMajor, "InvalidMessage")
 		return false, err
 	}
 	switch m := msg.(type) {
@@ -323,6 +332,13 @@ func (cs *consensus) OnReceive(
 		_, err = cs.ReceiveVoteMessage(m, false)
 	case *voteListMessage:
 		err = cs.ReceiveVoteListMessage(m, false)
+	case *evidenceMessage:
+		// relay the evidence only if it's new to prevent flooding.
+		var added bool
+		added, err = cs.ReceiveEvidenceMessage(m)
+		if err == nil && !added {
+			return false, nil
+		}
 	default:
 		err = errors.Errorf("unexpected broadcast message %v", m)
 	}
@@ -430,6 +446,11 @@ func (cs *consensus) ReceiveVoteMessage(msg *voteMessage, unicast bool) (int, er
 	if index < 0 {
 		return -1, errors.Errorf("bad voter %v", msg.address())
 	}
+	if e := cs.hvs.getEvidence(cs.c.NID(), index, msg); e != nil {
+		cs.logger.Warnf("double sign detected. Signer:%v Vote1:%v Vote2:%v\n",
+			msg.address(), e.VoteList.Get(0), e.VoteList.Get(1))
+		cs.handleEvidence(e, true)
+	}
 	added, votes := cs.hvs.add(index, msg)
 	if !added {
 		return -1, nil
@@ -461,6 +482,46 @@ func (cs *consensus) ReceiveVoteListMessage(msg *voteListMessage, unicast bool)
 	return err
 }
 
+func (cs *consensus) ReceiveEvidenceMessage(msg *evidenceMessage) (bool, error) {
+	if msg.NID != cs.c.NID() {
+		cs.logger.Debugf("ignore evidence for other network %d\n", msg.NID)
+		return false, nil
+	}
+	signer := msg.Signer()
+	if cs.validators.IndexOf(signer) < 0 && cs.prevValidators.IndexOf(signer) < 0 {
+		cs.logger.Debugf("ignore evidence for non-validator %v\n", signer)
+		return false, nil
+	}
+	return cs.handleEvidence(&msg.doubleSignEvidence, false), nil
+}
+
+// handleEvidence stores the evidence, and sends it as a patch if it's new.
+// It broadcasts the evidence found by itself. Received evidences are relayed
+// by the network.
+func (cs *consensus) handleEvidence(e *doubleSignEvidence, broadcast bool) bool {
+	added, err := cs.evidences.add(e)
+	if err != nil {
+		cs.logger.Errorf("fail to store evidence. %+v\n", err)
+		return false
+	}
+	if !added {
+		return false
+	}
+	if broadcast {
+		msgBS, err := msgCodec.MarshalToBytes(&evidenceMessage{*e})
+		if err != nil {
+			cs.logger.Panicf("sendEvidence: %+v\n", err)
+		}
+		if err = cs.ph.Broadcast(protoEvidence, msgBS, module.BROADCAST_ALL); err != nil {
+			cs.logger.Warnf("sendEvidence: %+v\n", err)
+		}
+	}
+	if err := cs.c.ServiceManager().SendPatch(e); err != nil {
+		cs.logger.Warnf("fail to send evidence patch. %+v\n", err)
+	}
+	return true
+}
+
 func (cs *consensus) handlePrevoteMessage(msg *voteMessage, prevotes *voteSet) {
 	if cs.step >= stepCommit {
 		return
@@ -1527,6 +1588,25 @@ func (cs *consensus) Start() error {
 		validators = &emptyAddressIndexer{}
 	}
 
+	cs.evidences, err = newEvidenceStore(cs.c.Database())
+	if err != nil {
+		return err
+	}
+	// evidences are sent again, and the service manager drops ones
+	// already recorded. Expired ones can't be recorded.
+	if err := cs.evidences.prune(lastBlock.Height() + 1 - module.EvidenceExpiry); err != nil {
+		return err
+	}
+	if evidences, err := cs.evidences.get(-1); err != nil {
+		return err
+	} else {
+		for _, e := range evidences {
+			if err := cs.c.ServiceManager().SendPatch(e); err != nil {
+				cs.logger.Warnf("fail to send evidence patch. %+v\n", err)
+			}
+		}
+	}
+
 	cs.ph, err = cs.c.NetworkManager().RegisterReactor("consensus", module.ProtoConsensus, cs, csProtocols, configEnginePriority)
 	if err != nil {
 		return err
@@ -1653,6 +1733,24 @@ func (cs *consensus) GetVotesByHeight(height int64) (module.CommitVoteSet, error
 	return c.commitVotes, nil
 }
 
+func (cs *consensus) GetEvidences(height int64) ([]module.Evidence, error) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	if cs.evidences == nil {
+		return nil, errors.InvalidStateError.New("NotStarted")
+	}
+	evidences, err := cs.evidences.get(height)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]module.Evidence, len(evidences))
+	for i, e := range evidences {
+		res[i] = e
+	}
+	return res, nil
+}
+
 func (cs *consensus) getCommit(h int64) (*commit, error) {
 	if h > cs.height || (h == cs.height && cs.step < stepCommit) {
 		return nil, errors.ErrNotFound
@@ -0,0 +1,248 @@
+package consensus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// doubleSignEvidence proves that a validator signed two different votes for
+// the same height, round and vote type. Votes are sorted by their hashes, so
+// the evidence for the same votes has the same hash. NID is the network of
+// the node found it. Votes don't sign it, so it's not a proof of the network.
+type doubleSignEvidence struct {
+	NID      int
+	VoteList voteList
+}
+
+func (e *doubleSignEvidence) Type() string {
+	return module.PatchTypeDoubleSign
+}
+
+func (e *doubleSignEvidence) Data() []byte {
+	return codec.MustMarshalToBytes(e)
+}
+
+func (e *doubleSignEvidence) Hash() []byte {
+	return crypto.SHA3Sum256(e.Data())
+}
+
+func (e *doubleSignEvidence) Height() int64 {
+	if e.VoteList.Len() == 0 {
+		return -1
+	}
+	return e.VoteList.Get(0).Height
+}
+
+func (e *doubleSignEvidence) Signer() module.Address {
+	if e.VoteList.Len() == 0 {
+		return nil
+	}
+	if addr := e.VoteList.Get(0).address(); addr != nil {
+		return addr
+	}
+	return nil
+}
+
+func (e *doubleSignEvidence) Verify() error {
+	if l := e.VoteList.Len(); l != 2 {
+		return errors.Errorf("bad number of votes %d", l)
+	}
+	v1, v2 := e.VoteList.Get(0), e.VoteList.Get(1)
+	if err := v1.verify(); err != nil {
+		return err
+	}
+	if err := v2.verify(); err != nil {
+		return err
+	}
+	if !v1.address().Equal(v2.address()) {
+		return errors.Errorf("different signers %v %v", v1.address(), v2.address())
+	}
+	if v1.Height != v2.Height || v1.Round != v2.Round || v1.Type != v2.Type {
+		return errors.Errorf("different votes %v %v", v1.voteBase, v2.voteBase)
+	}
+	if v1.voteBase.Equal(&v2.voteBase) {
+		return errors.Errorf("same votes %v", v1.voteBase)
+	}
+	if bytes.Compare(v1.hash(), v2.hash()) >= 0 {
+		return errors.Errorf("votes are not sorted")
+	}
+	return nil
+}
+
+func (e *doubleSignEvidence) VerifyFor(vl module.ValidatorList, nid int) error {
+	if e.NID != nid {
+		return errors.Errorf("bad nid %d for network %d", e.NID, nid)
+	}
+	if err := e.Verify(); err != nil {
+		return err
+	}
+	if vl.IndexOf(e.Signer()) < 0 {
+		return errors.Errorf("bad signer %v", e.Signer())
+	}
+	return nil
+}
+
+func (e *doubleSignEvidence) ToJSON(version module.JSONVersion) (interface{}, error) {
+	votes := make([]interface{}, e.VoteList.Len())
+	for i := range votes {
+		msg := e.VoteList.Get(i)
+		vote := map[string]interface{}{
+			"blockID":   common.HexBytes(msg.BlockID),
+			"timestamp": &common.HexInt64{Value: msg.Timestamp},
+			"signature": msg.Signature,
+		}
+		if msg.BlockPartSetID != nil {
+			vote["blockPartSetID"] = map[string]interface{}{
+				"count": &common.HexUint16{Value: msg.BlockPartSetID.Count},
+				"hash":  common.HexBytes(msg.BlockPartSetID.Hash),
+			}
+		}
+		votes[i] = vote
+	}
+	res := map[string]interface{}{
+		"nid":   &common.HexInt32{Value: int32(e.NID)},
+		"type":  e.Type(),
+		"hash":  common.HexBytes(e.Hash()),
+		"votes": votes,
+		"data":  common.HexBytes(e.Data()),
+	}
+	if len(votes) > 0 {
+		msg := e.VoteList.Get(0)
+		res["signer"] = msg.address()
+		res["height"] = &common.HexInt64{Value: msg.Height}
+		res["round"] = &common.HexInt32{Value: msg.Round}
+		res["voteType"] = &common.HexUint16{Value: uint16(msg.Type)}
+	}
+	return res, nil
+}
+
+func newDoubleSignEvidence(nid int, v1, v2 *voteMessage) *doubleSignEvidence {
+	votes := []*voteMessage{v1, v2}
+	sort.Slice(votes, func(i, j int) bool {
+		return bytes.Compare(votes[i].hash(), votes[j].hash()) < 0
+	})
+	e := &doubleSignEvidence{NID: nid}
+	for _, v := range votes {
+		e.VoteList.AddVote(v)
+	}
+	return e
+}
+
+type evidenceMessage struct {
+	doubleSignEvidence
+}
+
+func newEvidenceMessage() *evidenceMessage {
+	return &evidenceMessage{}
+}
+
+func (msg *evidenceMessage) verify() error {
+	return msg.doubleSignEvidence.Verify()
+}
+
+func (msg *evidenceMessage) subprotocol() uint16 {
+	return uint16(protoEvidence)
+}
+
+func (msg *evidenceMessage) String() string {
+	return fmt.Sprintf("EvidenceMessage{H:%d,Signer:%v,Hash:%v}",
+		msg.Height(), msg.Signer(), common.HexPre(msg.Hash()))
+}
+
+// evidenceStore keeps evidences in the database. Keys are height of votes
+// in big endian followed by hash of the evidence. It keeps one evidence for
+// a signer at a height.
+type evidenceStore struct {
+	bucket db.Bucket
+}
+
+func evidenceKeyOf(height int64, hash []byte) []byte {
+	key := make([]byte, 8, 8+len(hash))
+	binary.BigEndian.PutUint64(key, uint64(height))
+	return append(key, hash...)
+}
+
+// add stores the evidence, and returns false if it's already stored or
+// there is another evidence for the signer at the height.
+func (s *evidenceStore) add(e *doubleSignEvidence) (bool, error) {
+	key := evidenceKeyOf(e.Height(), e.Hash())
+	if s.bucket.Has(key) {
+		return false, nil
+	}
+	evidences, err := s.get(e.Height())
+	if err != nil {
+		return false, err
+	}
+	for _, e2 := range evidences {
+		if e2.Signer().Equal(e.Signer()) {
+			return false, nil
+		}
+	}
+	if err := s.bucket.Set(key, e.Data()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// get returns evidences for the height, or all evidences if height is
+// negative.
+func (s *evidenceStore) get(height int64) ([]*doubleSignEvidence, error) {
+	var start, limit []byte
+	if height >= 0 {
+		start, limit = db.BytesPrefix(evidenceKeyOf(height, nil))
+	}
+	itr := s.bucket.NewIterator(start, limit)
+	defer itr.Release()
+	var evidences []*doubleSignEvidence
+	for itr.Next() {
+		e := new(doubleSignEvidence)
+		if _, err := codec.UnmarshalFromBytes(itr.Value(), e); err != nil {
+			return nil, errors.CriticalFormatError.Wrapf(err,
+				"InvalidEvidence(key=%#x)", itr.Key())
+		}
+		evidences = append(evidences, e)
+	}
+	if err := itr.Error(); err != nil {
+		return nil, err
+	}
+	return evidences, nil
+}
+
+// prune removes evidences for the heights lower than the height.
+func (s *evidenceStore) prune(height int64) error {
+	if height <= 0 {
+		return nil
+	}
+	itr := s.bucket.NewIterator(nil, evidenceKeyOf(height, nil))
+	var keys [][]byte
+	for itr.Next() {
+		keys = append(keys, append([]byte{}, itr.Key()...))
+	}
+	itr.Release()
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := s.bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newEvidenceStore(database db.Database) (*evidenceStore, error) {
+	bk, err := database.GetBucket(db.EvidenceByHeight)
+	if err != nil {
+		return nil, err
+	}
+	return &evidenceStore{bucket: bk}, nil
+}
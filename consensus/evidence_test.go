@@ -0,0 +1,163 @@
+package consensus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+const testNID = 1
+
+func newTestVote(t *testing.T, w module.Wallet, height int64, bid []byte) *voteMessage {
+	msg := newVoteMessage()
+	msg.Height = height
+	msg.Round = 1
+	msg.Type = voteTypePrevote
+	msg.BlockID = bid
+	msg.BlockPartSetID = &PartSetID{Count: 1, Hash: bid}
+	if err := msg.sign(w); err != nil {
+		t.Fatalf("fail to sign vote err=%+v", err)
+	}
+	return msg
+}
+
+func TestHeightVoteSet_GetEvidence(t *testing.T) {
+	w := wallet.New()
+	v1 := newTestVote(t, w, 10, []byte("block1"))
+	v2 := newTestVote(t, w, 10, []byte("block2"))
+
+	var hvs heightVoteSet
+	hvs.reset(4)
+	assert.Nil(t, hvs.getEvidence(testNID, 1, v1))
+	hvs.add(1, v1)
+	assert.Nil(t, hvs.getEvidence(testNID, 1, v1))
+
+	e := hvs.getEvidence(testNID, 1, v2)
+	if assert.NotNil(t, e) {
+		assert.NoError(t, e.Verify())
+		assert.Equal(t, int64(10), e.Height())
+		assert.True(t, e.Signer().Equal(w.Address()))
+		assert.Equal(t, e.Hash(), newDoubleSignEvidence(testNID, v2, v1).Hash())
+	}
+
+	// votes in the other round are not conflicting
+	v3 := newTestVote(t, w, 10, []byte("block2"))
+	v3.Round = 2
+	assert.Nil(t, hvs.getEvidence(testNID, 1, v3))
+}
+
+func TestDoubleSignEvidence_Verify(t *testing.T) {
+	w1, w2 := wallet.New(), wallet.New()
+	v1 := newTestVote(t, w1, 10, []byte("block1"))
+
+	cases := []struct {
+		name string
+		vote *voteMessage
+	}{
+		{"SameVote", newTestVote(t, w1, 10, []byte("block1"))},
+		{"OtherSigner", newTestVote(t, w2, 10, []byte("block2"))},
+		{"OtherHeight", newTestVote(t, w1, 11, []byte("block2"))},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := newDoubleSignEvidence(testNID, v1, c.vote)
+			assert.Error(t, e.Verify())
+		})
+	}
+
+	e := newDoubleSignEvidence(testNID, v1, newTestVote(t, w1, 10, []byte("block2")))
+	decoded := new(doubleSignEvidence)
+	_, err := codec.UnmarshalFromBytes(e.Data(), decoded)
+	assert.NoError(t, err)
+	assert.NoError(t, decoded.Verify())
+	assert.True(t, bytes.Equal(e.Hash(), decoded.Hash()))
+}
+
+type testValidatorList struct {
+	module.ValidatorList
+	addrs []module.Address
+}
+
+func (vl *testValidatorList) IndexOf(addr module.Address) int {
+	for i, a := range vl.addrs {
+		if a.Equal(addr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestDoubleSignEvidence_VerifyFor(t *testing.T) {
+	w1, w2 := wallet.New(), wallet.New()
+	e := newDoubleSignEvidence(testNID, newTestVote(t, w1, 10, []byte("block1")),
+		newTestVote(t, w1, 10, []byte("block2")))
+
+	vl := &testValidatorList{addrs: []module.Address{w2.Address(), w1.Address()}}
+	assert.NoError(t, e.VerifyFor(vl, testNID))
+	// NID isn't signed, so it only filters evidences found in other networks.
+	assert.Error(t, e.VerifyFor(vl, testNID+1))
+	assert.Error(t, e.VerifyFor(&testValidatorList{addrs: []module.Address{w2.Address()}}, testNID))
+
+	// the evidence of the same votes for the other network is different
+	e2 := newDoubleSignEvidence(testNID+1, e.VoteList.Get(0), e.VoteList.Get(1))
+	assert.NotEqual(t, e.Hash(), e2.Hash())
+}
+
+func TestEvidenceStore(t *testing.T) {
+	w := wallet.New()
+	s, err := newEvidenceStore(db.NewMapDB())
+	assert.NoError(t, err)
+
+	e1 := newDoubleSignEvidence(testNID, newTestVote(t, w, 10, []byte("block1")),
+		newTestVote(t, w, 10, []byte("block2")))
+	e2 := newDoubleSignEvidence(testNID, newTestVote(t, w, 11, []byte("block1")),
+		newTestVote(t, w, 11, []byte("block2")))
+	for _, e := range []*doubleSignEvidence{e1, e2} {
+		added, err := s.add(e)
+		assert.NoError(t, err)
+		assert.True(t, added)
+	}
+	added, err := s.add(e1)
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	evidences, err := s.get(11)
+	assert.NoError(t, err)
+	if assert.Len(t, evidences, 1) {
+		assert.Equal(t, e2.Hash(), evidences[0].Hash())
+	}
+	evidences, err = s.get(-1)
+	assert.NoError(t, err)
+	assert.Len(t, evidences, 2)
+
+	// one evidence for a signer at a height
+	v3 := newTestVote(t, w, 10, []byte("block1"))
+	v3.Round = 2
+	if err := v3.sign(w); err != nil {
+		t.Fatalf("fail to sign vote err=%+v", err)
+	}
+	e3 := newDoubleSignEvidence(testNID, v3, newTestVote(t, w, 10, []byte("block3")))
+	added, err = s.add(e3)
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	w2 := wallet.New()
+	e4 := newDoubleSignEvidence(testNID, newTestVote(t, w2, 10, []byte("block1")),
+		newTestVote(t, w2, 10, []byte("block2")))
+	added, err = s.add(e4)
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	assert.NoError(t, s.prune(11))
+	evidences, err = s.get(-1)
+	assert.NoError(t, err)
+	if assert.Len(t, evidences, 1) {
+		assert.Equal(t, e2.Hash(), evidences[0].Hash())
+	}
+}
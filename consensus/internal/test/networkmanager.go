@@ -151,6 +151,9 @@ func (ph *tProtocolHandler) Multicast(pi module.ProtocolInfo, b []byte, role mod
 	return nil
 }
 
+func (ph *tProtocolHandler) Penalize(id module.PeerID, penalty module.Penalty, reason string) {
+}
+
 func (ph *tProtocolHandler) Unicast(pi module.ProtocolInfo, b []byte, id module.PeerID) error {
 	ph.nm.Lock()
 	defer ph.nm.Unlock()
@@ -19,6 +19,7 @@ const (
 	protoVote
 	protoRoundState
 	protoVoteList
+	protoEvidence
 )
 
 type protocolConstructor struct {
@@ -32,6 +33,7 @@ var protocolConstructors = [...]protocolConstructor{
 	{protoVote, func() message { return newVoteMessage() }},
 	{protoRoundState, func() message { return newRoundStateMessage() }},
 	{protoVoteList, func() message { return newVoteListMessage() }},
+	{protoEvidence, func() message { return newEvidenceMessage() }},
 }
 
 func unmarshalMessage(sp uint16, bs []byte) (message, error) {
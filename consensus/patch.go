@@ -76,6 +76,9 @@ func DecodePatch(t string, bs []byte) (module.Patch, error) {
 	case module.PatchTypeSkipTransaction:
 		patch = &skipPatch{}
 		_, err = codec.UnmarshalFromBytes(bs, patch)
+	case module.PatchTypeDoubleSign:
+		patch = &doubleSignEvidence{}
+		_, err = codec.UnmarshalFromBytes(bs, patch)
 	default:
 		err = errors.ErrUnsupported
 	}
@@ -294,10 +294,12 @@ func (s *syncer) OnReceive(sp module.ProtocolInfo, bs []byte,
 	msg, err := unmarshalMessage(sp.Uint16(), bs)
 	if err != nil {
 		s.logger.Warnf("OnReceive: error=%+v\n", err)
+		s.ph.Penalize(id, module.PenaltyMajor, "MalformedMessage")
 		return false, err
 	}
 	s.logger.Debugf("OnReceive %v From:%v\n", msg, common.HexPre(id.Bytes()))
 	if err := msg.verify(); err != nil {
+		s.ph.Penalize(id, module.PenaltyMajor, "InvalidMessage")
 		return false, err
 	}
 	var idx int
@@ -61,6 +61,16 @@ func (vs *voteSet) add(index int, v *voteMessage) bool {
 	return true
 }
 
+// returns the vote of the validator conflicting with the vote. Votes for
+// different blocks in the same set are conflicting.
+func (vs *voteSet) getConflict(index int, v *voteMessage) *voteMessage {
+	omsg := vs.msgs[index]
+	if omsg != nil && !omsg.voteBase.Equal(&v.voteBase) {
+		return omsg
+	}
+	return nil
+}
+
 // returns true if has +2/3 votes
 func (vs *voteSet) hasOverTwoThirds() bool {
 	return vs.count > len(vs.msgs)*2/3
@@ -192,6 +202,19 @@ func (hvs *heightVoteSet) add(index int, v *voteMessage) (bool, *voteSet) {
 	return vs.add(index, v), vs
 }
 
+// returns the evidence if the vote conflicts with the vote of the validator
+// added before.
+func (hvs *heightVoteSet) getEvidence(nid int, index int, v *voteMessage) *doubleSignEvidence {
+	rvs, ok := hvs._votes[v.Round]
+	if !ok || rvs[v.Type] == nil {
+		return nil
+	}
+	if omsg := rvs[v.Type].getConflict(index, v); omsg != nil {
+		return newDoubleSignEvidence(nid, omsg, v)
+	}
+	return nil
+}
+
 func (hvs *heightVoteSet) votesFor(round int32, voteType voteType) *voteSet {
 	rvs := hvs._votes[round]
 	if rvs[voteType] == nil {
@@ -84,6 +84,23 @@ type BlockManager interface {
 	Finalize(BlockCandidate) error
 
 	GetTransactionInfo(id []byte) (TransactionInfo, error)
+
+	// GetTransactionCountByAddress returns number of transactions sent or
+	// received by the address. It returns UnsupportedError if account index
+	// is disabled.
+	GetTransactionCountByAddress(addr Address) (int64, error)
+
+	// GetTransactionsByAddress returns IDs of transactions sent or received
+	// by the address in order of finalization, starting from start-th one.
+	// It returns UnsupportedError if account index is disabled.
+	GetTransactionsByAddress(addr Address, start int64, limit int) ([][]byte, error)
+
+	// GetBlockProof returns the index of the finalized block at the height
+	// in the accumulator of block IDs at atHeight, and hashes of the witness
+	// for the block ID. The accumulator is local to the node, and it starts
+	// from the oldest block of the node.
+	GetBlockProof(height, atHeight int64) (int64, [][]byte, error)
+
 	Term()
 
 	// WaitTransaction waits for a transaction with timestamp between
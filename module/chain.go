@@ -25,6 +25,14 @@ type Chain interface {
 	NormalTxPoolSize() int
 	PatchTxPoolSize() int
 	MaxBlockTxBytes() int
+	AccountIndex() bool
+	MaxLogsRange() int
+	MaxLogsResult() int
+	PrunedStateHeight() (int64, error)
+	TxPoolMaxPerSender() int
+	TxPoolAllowList() []Address
+	TxPoolDenyList() []Address
+	TxRateLimit() int
 	DefaultWaitTimeout() time.Duration
 	MaxWaitTimeout() time.Duration
 	Genesis() []byte
@@ -42,8 +50,11 @@ type Chain interface {
 	Start() error
 	Stop() error
 	Import(src string, height int64) error
+	ImportArchive(file string) error
+	ImportSnapshot(file string) error
+	Export(file string, from, to int64, receipts bool) error
 	Prune(gs string, dbt string, height int64) error
-	Backup(file string, extra []string) error
+	Backup(file string, extra []string, online bool, base string) error
 	Term() error
 	State() (string, int64, error)
 	IsStarted() bool
@@ -51,6 +62,8 @@ type Chain interface {
 
 	Reset() error
 	Verify() error
+	Check(file string, repair bool) error
+	FindOrphans(limit int) (int64, [][]byte, error)
 
 	MetricContext() context.Context
 	Logger() log.Logger
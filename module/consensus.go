@@ -11,4 +11,8 @@ type Consensus interface {
 	Term()
 	GetStatus() *ConsensusStatus
 	GetVotesByHeight(height int64) (CommitVoteSet, error)
+
+	// GetEvidences returns evidences of misbehavior of validators found by
+	// the node. It returns all evidences if height is negative.
+	GetEvidences(height int64) ([]Evidence, error)
 }
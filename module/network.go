@@ -21,6 +21,8 @@ type NetworkManager interface {
 
 	SetTrustSeeds(seeds string)
 	SetInitialRoles(roles ...Role)
+	SetStaticPeers(peers string)
+	SetPeerAllowList(peers string)
 }
 
 type Reactor interface {
@@ -35,8 +37,26 @@ type ProtocolHandler interface {
 	Broadcast(pi ProtocolInfo, b []byte, bt BroadcastType) error
 	Multicast(pi ProtocolInfo, b []byte, role Role) error
 	Unicast(pi ProtocolInfo, b []byte, id PeerID) error
+
+	// Penalize decreases the score of the peer for its misbehavior. The peer
+	// is banned for a while if the score goes below the threshold.
+	Penalize(id PeerID, penalty Penalty, reason string)
 }
 
+// Penalty is the score taken from a peer for its misbehavior.
+type Penalty int
+
+const (
+	// PenaltyMinor is for messages which are useless or unexpected, but may
+	// be sent by an honest peer.
+	PenaltyMinor Penalty = 5
+	// PenaltyMajor is for messages which can't be decoded or verified.
+	PenaltyMajor Penalty = 20
+	// PenaltyCritical is for messages which can't be sent by an honest
+	// peer. The peer is banned immediately.
+	PenaltyCritical Penalty = 100
+)
+
 type BroadcastType byte
 type Role string
 
@@ -2,8 +2,13 @@ package module
 
 const (
 	PatchTypeSkipTransaction = "skip_txs"
+	PatchTypeDoubleSign      = "double_sign"
 )
 
+// EvidenceExpiry is the number of blocks after the height of the evidence
+// in which the evidence can be recorded.
+const EvidenceExpiry = 1000
+
 type Patch interface {
 	Type() string
 	Data() []byte
@@ -17,4 +22,23 @@ type SkipTransactionPatch interface {
 	Verify(vl ValidatorList, roundLimit int64, nid int) error
 }
 
+// Evidence is a verifiable proof of misbehavior of a validator. It's
+// submitted as a patch to record it in the world state.
+type Evidence interface {
+	Patch
+	Hash() []byte
+	Height() int64 // height of the votes
+	Signer() Address
+
+	// Verify check internal data is correct
+	Verify() error
+
+	// VerifyFor checks the signer is one of the validators. The network ID
+	// of the evidence is checked too, but it's not signed by the signer, so
+	// the evidence of the signer with the same key in other networks can't
+	// be distinguished from it.
+	VerifyFor(vl ValidatorList, nid int) error
+	ToJSON(version JSONVersion) (interface{}, error)
+}
+
 type PatchDecoder func(t string, bs []byte) (Patch, error)
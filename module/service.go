@@ -104,6 +104,8 @@ const (
 	Revision6
 	Revision7
 	Revision8
+	Revision9
+	Revision10
 	RevisionReserved
 )
 
@@ -373,6 +375,11 @@ type ServiceManager interface {
 	// Then it returns the expected result of the transaction.
 	// It ignores supplied step limit.
 	ExecuteTransaction(result []byte, vh []byte, js []byte, bi BlockInfo) (Receipt, error)
+
+	// ExecuteTransactionForTrace executes the transaction like
+	// ExecuteTransaction, and the execution is traced with the callback.
+	// Callback.OnEnd is called before it returns.
+	ExecuteTransactionForTrace(result []byte, vh []byte, js []byte, bi BlockInfo, cb TraceCallback) (Receipt, error)
 }
 
 type TraceInfo struct {
@@ -0,0 +1,244 @@
+package network
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	// lookupTimeout limits the time to resolve a host name, so a slow
+	// resolver doesn't block the refresh for long.
+	lookupTimeout = 5 * time.Second
+)
+
+// peerAllowList keeps IDs and network addresses of the peers allowed to
+// connect. Addresses are matched by the host, because incoming connections
+// come from arbitrary ports. Host names are resolved when the addresses are
+// set and on refresh, so connections are matched only with the hosts and
+// their resolved IPs. Static peers are always allowed, and all peers are
+// allowed if there is no ID or address in the list.
+type peerAllowList struct {
+	mtx     sync.RWMutex
+	ids     map[string]bool
+	addrs   map[NetAddress]bool
+	static  map[NetAddress]bool
+	hosts   map[string]bool
+	version int
+	resolve func(host string) []string
+
+	refreshing int32
+}
+
+func newPeerAllowList() *peerAllowList {
+	return &peerAllowList{
+		ids:     make(map[string]bool),
+		addrs:   make(map[NetAddress]bool),
+		static:  make(map[NetAddress]bool),
+		hosts:   make(map[string]bool),
+		resolve: lookupHost,
+	}
+}
+
+func (l *peerAllowList) set(ids []module.PeerID, addrs []NetAddress) {
+	l.mtx.Lock()
+	l.ids = make(map[string]bool)
+	for _, id := range ids {
+		l.ids[id.String()] = true
+	}
+	l.addrs = make(map[NetAddress]bool)
+	for _, na := range addrs {
+		l.addrs[na] = true
+	}
+	l._updated()
+	l.mtx.Unlock()
+
+	l.refresh()
+}
+
+func (l *peerAllowList) setStatic(addrs []NetAddress) {
+	l.mtx.Lock()
+	l.static = make(map[NetAddress]bool)
+	for _, na := range addrs {
+		l.static[na] = true
+	}
+	l._updated()
+	l.mtx.Unlock()
+
+	l.refresh()
+}
+
+// _updated makes hosts of the addresses available before they are resolved,
+// and makes the running refresh discard its result.
+func (l *peerAllowList) _updated() {
+	l.version += 1
+	hosts := make(map[string]bool)
+	for _, h := range l._hosts() {
+		hosts[h] = true
+	}
+	l.hosts = hosts
+}
+
+// refresh resolves host names of the addresses. It's called periodically
+// to follow changes of the names.
+func (l *peerAllowList) refresh() {
+	l.mtx.RLock()
+	version := l.version
+	names := l._hosts()
+	l.mtx.RUnlock()
+
+	hosts := make(map[string]bool)
+	for _, h := range names {
+		hosts[h] = true
+		for _, ip := range l.resolve(h) {
+			hosts[ip] = true
+		}
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.version == version {
+		l.hosts = hosts
+	}
+}
+
+// refreshAsync runs refresh in the background if it's not running, so the
+// caller isn't blocked by the resolver.
+func (l *peerAllowList) refreshAsync() {
+	if !atomic.CompareAndSwapInt32(&l.refreshing, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&l.refreshing, 0)
+		l.refresh()
+	}()
+}
+
+func (l *peerAllowList) _isEmpty() bool {
+	return len(l.ids) == 0 && len(l.addrs) == 0
+}
+
+func (l *peerAllowList) _hosts() []string {
+	hosts := make([]string, 0, len(l.addrs)+len(l.static))
+	for _, m := range []map[NetAddress]bool{l.addrs, l.static} {
+		for na := range m {
+			hosts = append(hosts, hostOf(string(na)))
+		}
+	}
+	return hosts
+}
+
+// allowsAddress returns whether it may dial to the address. Any address is
+// allowed if there are IDs in the list, because the ID of the peer is known
+// only after the connection is made. The ID is checked by allowsPeer then.
+func (l *peerAllowList) allowsAddress(na NetAddress) bool {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	return l._isEmpty() || len(l.ids) > 0 || l.static[na] || l.addrs[na] ||
+		l.hosts[hostOf(string(na))]
+}
+
+// allowsPeer returns whether the connected peer is allowed, by the ID or by
+// the host of the connection.
+func (l *peerAllowList) allowsPeer(p *Peer) bool {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	if l._isEmpty() || l.ids[p.id.String()] {
+		return true
+	}
+	if p.conn == nil {
+		return false
+	}
+	return l.hosts[hostOf(p.conn.RemoteAddr().String())]
+}
+
+func (l *peerAllowList) list() ([]string, []string) {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	allowed := make([]string, 0, len(l.ids)+len(l.addrs))
+	for id := range l.ids {
+		allowed = append(allowed, id)
+	}
+	for na := range l.addrs {
+		allowed = append(allowed, string(na))
+	}
+	static := make([]string, 0, len(l.static))
+	for na := range l.static {
+		static = append(static, string(na))
+	}
+	sort.Strings(allowed)
+	sort.Strings(static)
+	return static, allowed
+}
+
+func hostOf(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func lookupHost(host string) []string {
+	if net.ParseIP(host) != nil {
+		return []string{host}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil
+	}
+	return addrs
+}
+
+func splitPeerList(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// ParseNetAddressList parses comma separated network addresses(host:port).
+func ParseNetAddressList(s string) ([]NetAddress, error) {
+	var addrs []NetAddress
+	for _, v := range splitPeerList(s) {
+		if _, _, err := net.SplitHostPort(v); err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidNetAddress(%s)", v)
+		}
+		addrs = append(addrs, NetAddress(v))
+	}
+	return addrs, nil
+}
+
+// ParsePeerAllowList parses comma separated peer IDs and network
+// addresses(host:port).
+func ParsePeerAllowList(s string) ([]module.PeerID, []NetAddress, error) {
+	var ids []module.PeerID
+	var addrs []NetAddress
+	for _, v := range splitPeerList(s) {
+		if _, _, err := net.SplitHostPort(v); err == nil {
+			addrs = append(addrs, NetAddress(v))
+			continue
+		}
+		addr := new(common.Address)
+		if err := addr.SetString(v); err != nil || addr.IsContract() {
+			return nil, nil, errors.IllegalArgumentError.Errorf("InvalidPeer(%s)", v)
+		}
+		ids = append(ids, NewPeerIDFromAddress(addr))
+	}
+	return ids, addrs, nil
+}
@@ -0,0 +1,118 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func TestParsePeerAllowList(t *testing.T) {
+	id := generatePeerID()
+	ids, nas, err := ParsePeerAllowList(" 127.0.0.1:8080," + id.String() + ",,localhost:7100")
+	assert.NoError(t, err)
+	assert.Equal(t, []module.PeerID{id}, ids)
+	assert.Equal(t, []NetAddress{"127.0.0.1:8080", "localhost:7100"}, nas)
+
+	_, _, err = ParsePeerAllowList("127.0.0.1")
+	assert.Error(t, err)
+	_, _, err = ParsePeerAllowList("cx0000000000000000000000000000000000000001")
+	assert.Error(t, err)
+
+	nas, err = ParseNetAddressList("127.0.0.1:8080, 127.0.0.2:8080")
+	assert.NoError(t, err)
+	assert.Equal(t, []NetAddress{"127.0.0.1:8080", "127.0.0.2:8080"}, nas)
+	_, err = ParseNetAddressList(id.String())
+	assert.Error(t, err)
+}
+
+func TestPeerAllowList(t *testing.T) {
+	l := newPeerAllowList()
+	id1 := generatePeerID()
+	id2 := generatePeerID()
+
+	// empty list allows all
+	assert.True(t, l.allowsAddress("127.0.0.1:8080"))
+	assert.True(t, l.allowsPeer(&Peer{id: id1}))
+
+	l.set(nil, []NetAddress{"127.0.0.1:8080"})
+	assert.True(t, l.allowsAddress("127.0.0.1:8080"))
+	assert.True(t, l.allowsAddress("127.0.0.1:8081"))
+	assert.False(t, l.allowsAddress("127.0.0.2:8080"))
+	assert.False(t, l.allowsPeer(&Peer{id: id1}))
+
+	// static peers are allowed
+	l.setStatic([]NetAddress{"127.0.0.2:8080"})
+	assert.True(t, l.allowsAddress("127.0.0.2:8080"))
+	assert.False(t, l.allowsAddress("127.0.0.3:8080"))
+
+	// ID is checked after the connection
+	l.set([]module.PeerID{id1}, nil)
+	assert.True(t, l.allowsAddress("127.0.0.3:8080"))
+	assert.True(t, l.allowsPeer(&Peer{id: id1}))
+	assert.False(t, l.allowsPeer(&Peer{id: id2}))
+
+	static, allowed := l.list()
+	assert.Equal(t, []string{"127.0.0.2:8080"}, static)
+	assert.Equal(t, []string{id1.String()}, allowed)
+}
+
+func TestPeerAllowList_ResolveHosts(t *testing.T) {
+	l := newPeerAllowList()
+	resolved := map[string][]string{"node1": {"10.0.0.1"}}
+	l.resolve = func(host string) []string {
+		return resolved[host]
+	}
+
+	// host names are resolved when they are set
+	l.set(nil, []NetAddress{"node1:8080"})
+	assert.True(t, l.allowsAddress("node1:8081"))
+	assert.True(t, l.allowsAddress("10.0.0.1:8081"))
+	assert.False(t, l.allowsAddress("10.0.0.2:8081"))
+
+	// changes of names are applied on refresh
+	resolved["node1"] = []string{"10.0.0.2"}
+	assert.False(t, l.allowsAddress("10.0.0.2:8081"))
+	l.refresh()
+	assert.True(t, l.allowsAddress("10.0.0.2:8081"))
+	assert.False(t, l.allowsAddress("10.0.0.1:8081"))
+
+	l.setStatic([]NetAddress{"node2:8080"})
+	resolved["node2"] = []string{"10.0.0.3"}
+	assert.False(t, l.allowsAddress("10.0.0.3:8080"))
+	l.refresh()
+	assert.True(t, l.allowsAddress("10.0.0.3:8080"))
+}
+
+func TestDialer_NotAllowed(t *testing.T) {
+	d := newDialer("test", func(conn net.Conn, addr string, d *Dialer) {
+		assert.Fail(t, "connected to not allowed address", addr)
+	})
+	d.allowList.set(nil, []NetAddress{"127.0.0.1:8080"})
+	assert.Equal(t, ErrNotAllowedPeer, d.Dial("127.0.0.2:8080"))
+}
+
+func TestPeerAllowList_RefreshAsync(t *testing.T) {
+	l := newPeerAllowList()
+	l.resolve = func(host string) []string { return nil }
+	l.set(nil, []NetAddress{"node1:8080"})
+
+	release := make(chan struct{})
+	l.resolve = func(host string) []string {
+		<-release
+		return []string{"10.0.0.1"}
+	}
+
+	// it doesn't wait for the resolver, and runs one refresh at a time
+	l.refreshAsync()
+	l.refreshAsync()
+	assert.False(t, l.allowsAddress("10.0.0.1:8080"))
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return l.allowsAddress("10.0.0.1:8080")
+	}, time.Second, 10*time.Millisecond)
+}
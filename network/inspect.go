@@ -35,9 +35,20 @@ func inspectP2P(mgr *manager, informal bool) map[string]interface{} {
 	m["uncles"] = peerSetToMapArray(mgr.p2p.uncles, informal)
 	m["nephews"] = peerSetToMapArray(mgr.p2p.nephews, informal)
 	m["orphanages"] = peerSetToMapArray(mgr.p2p.orphanages, informal)
+	m["bans"] = mgr.p2p.reputation.banList()
+	m["staticPeers"], m["allowList"] = mgr.p2p.dialer.allowList.list()
 	if informal {
 		m["pre"] = peerSetToMapArray(mgr.p2p.pre, informal)
 		m["reject"] = peerSetToMapArray(mgr.p2p.reject, informal)
+		m["scores"] = inspectScores(mgr.p2p)
+	}
+	return m
+}
+
+func inspectScores(p2p *PeerToPeer) map[string]int {
+	m := make(map[string]int)
+	for _, p := range p2p.getPeers(false) {
+		m[p.id.String()] = p2p.reputation.scoreOf(p.id)
 	}
 	return m
 }
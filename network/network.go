@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
@@ -39,9 +40,13 @@ func NewManager(c module.Chain, nt module.NetworkTransport, trustSeeds string, r
 	mtr := metric.NewNetworkMetric(c.MetricContext())
 	networkLogger := c.Logger().WithFields(log.Fields{log.FieldKeyModule: "NM"})
 	networkLogger.Infof("NetworkManager use channel=%s for cid=%#x nid=%#x", channel, c.CID(), c.NID())
+	var bk db.Bucket
+	if database := c.Database(); database != nil {
+		bk, _ = database.GetBucket(db.ChainProperty)
+	}
 	m := &manager{
 		channel:          channel,
-		p2p:              newPeerToPeer(channel, self, t.GetDialer(channel), mtr, networkLogger),
+		p2p:              newPeerToPeer(channel, self, t.GetDialer(channel), bk, mtr, networkLogger),
 		roles:            make(map[module.Role]*PeerIDSet),
 		destByRole:       make(map[module.Role]byte),
 		roleByDest:       make(map[byte]module.Role),
@@ -62,6 +67,8 @@ func NewManager(c module.Chain, nt module.NetworkTransport, trustSeeds string, r
 
 	m.SetInitialRoles(roles...)
 	m.SetTrustSeeds(trustSeeds)
+	m.SetStaticPeers("")
+	m.SetPeerAllowList("")
 
 	m.logger.Debugln("NewManager", channel)
 	return m
@@ -310,6 +317,40 @@ func (m *manager) SetTrustSeeds(seeds string) {
 	m.p2p.trustSeeds.ClearAndAdd(nas...)
 }
 
+// SetStaticPeers sets the peers which are always dialed and kept connected.
+// Static peers are allowed to connect regardless of the allow-list.
+func (m *manager) SetStaticPeers(peers string) {
+	nas, err := ParseNetAddressList(peers)
+	if err != nil {
+		m.logger.Warnf("SetStaticPeers ignored err=%+v", err)
+		return
+	}
+	l := make([]NetAddress, 0, len(nas))
+	for _, na := range nas {
+		if na != m.p2p.getNetAddress() {
+			l = append(l, na)
+		}
+	}
+	m.p2p.staticPeers.ClearAndAdd(l...)
+	m.p2p.dialer.allowList.setStatic(l)
+}
+
+// SetPeerAllowList sets peer IDs and network addresses allowed to connect.
+// All peers are allowed if it's empty.
+func (m *manager) SetPeerAllowList(peers string) {
+	ids, nas, err := ParsePeerAllowList(peers)
+	if err != nil {
+		m.logger.Warnf("SetPeerAllowList ignored err=%+v", err)
+		return
+	}
+	m.p2p.dialer.allowList.set(ids, nas)
+	for _, p := range m.p2p.getPeers(false) {
+		if !m.p2p.dialer.allowList.allowsPeer(p) {
+			p.CloseByError(ErrNotAllowedPeer)
+		}
+	}
+}
+
 func (m *manager) SetInitialRoles(roles ...module.Role) {
 	role := PeerRoleFlag(p2pRoleNone)
 	for _, r := range roles {
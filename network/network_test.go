@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 )
@@ -273,6 +274,7 @@ func (c *dummyChain) CID() int                       { return c.nid }
 func (c *dummyChain) NetID() int                     { return c.nid }
 func (c *dummyChain) Logger() log.Logger             { return c.logger }
 func (c *dummyChain) MetricContext() context.Context { return c.metricCtx }
+func (c *dummyChain) Database() db.Database          { return nil }
 
 func generateNetwork(name string, port int, n int, t *testing.T, roles ...module.Role) ([]*testReactor, int) {
 	arr := make([]*testReactor, n)
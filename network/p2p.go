@@ -3,6 +3,7 @@ package network
 import (
 	"context"
 	"fmt"
+	"net"
 	"sort"
 	"strings"
 	"sync"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/metric"
@@ -39,14 +41,19 @@ type PeerToPeer struct {
 	reject     *PeerSet
 	parentMtx  sync.RWMutex
 
+	//Reputation of peers
+	reputation *reputation
+
 	//Discovery
 	discoveryTicker *time.Ticker
 	seedTicker      *time.Ticker
+	allowListTicker *time.Ticker
 
 	//Addresses
-	trustSeeds *NetAddressSet
-	seeds      *NetAddressSet
-	roots      *NetAddressSet //For seed, root
+	trustSeeds  *NetAddressSet
+	seeds       *NetAddressSet
+	roots       *NetAddressSet //For seed, root
+	staticPeers *NetAddressSet //Always dialed and kept connected
 	//[TBD] 2hop peers of current tree for status change
 	grandParent   NetAddress
 	grandChildren *NetAddressSet
@@ -78,7 +85,7 @@ const (
 	p2pEventNotAllowed = "not allowed"
 )
 
-func newPeerToPeer(channel string, self *Peer, d *Dialer, mtr *metric.NetworkMetric, l log.Logger) *PeerToPeer {
+func newPeerToPeer(channel string, self *Peer, d *Dialer, bk db.Bucket, mtr *metric.NetworkMetric, l log.Logger) *PeerToPeer {
 	p2pLogger := l.WithFields(log.Fields{LoggerFieldKeySubModule: "p2p"})
 	p2p := &PeerToPeer{
 		channel:          channel,
@@ -100,12 +107,15 @@ func newPeerToPeer(channel string, self *Peer, d *Dialer, mtr *metric.NetworkMet
 		orphanages:      NewPeerSet(),
 		pre:             NewPeerSet(),
 		reject:          NewPeerSet(),
+		reputation:      newReputation(bk, p2pLogger),
 		discoveryTicker: time.NewTicker(DefaultDiscoveryPeriod),
 		seedTicker:      time.NewTicker(DefaultSeedPeriod),
+		allowListTicker: time.NewTicker(DefaultAllowListPeriod),
 		//
 		trustSeeds:    NewNetAddressSet(),
 		seeds:         NewNetAddressSet(),
 		roots:         NewNetAddressSet(),
+		staticPeers:   NewNetAddressSet(),
 		grandChildren: NewNetAddressSet(),
 		//
 		allowedRoots: NewPeerIDSet(),
@@ -244,6 +254,11 @@ func (p2p *PeerToPeer) onPeer(p *Peer) {
 		p.CloseByError(fmt.Errorf("onPeer not allowed connection"))
 		return
 	}
+	if p2p.reputation.isBanned(p.id) {
+		p2p.onEvent(p2pEventNotAllowed, p)
+		p.CloseByError(ErrBannedPeer)
+		return
+	}
 	if dp := p2p.getPeer(p.id, false); dp != nil {
 		p2p.onEvent(p2pEventDuplicate, p)
 
@@ -261,16 +276,33 @@ func (p2p *PeerToPeer) onPeer(p *Peer) {
 		dp.CloseByError(ErrDuplicatedPeer)
 		p2p.logger.Infoln("Already exists connected Peer, close old", dp, diff)
 	}
+	// Static peers join without the connection type, so they exchange
+	// packets even if the discovery doesn't make them parent, uncle or
+	// friend. They should be static on both sides.
+	p.static = p2p.staticPeers.Contains(p.netAddress)
 	p2p.orphanages.Add(p)
+	if p.static {
+		p2p.onEvent(p2pEventJoin, p)
+	}
 	if !p.incomming {
 		p2p.sendQuery(p)
 	}
 }
 
+// isJoined returns whether the peer exchanges packets of the reactors.
+func isJoined(p *Peer) bool {
+	return p.connType != p2pConnTypeNone || p.static
+}
+
 //callback from Peer.sendRoutine or Peer.receiveRoutine
 func (p2p *PeerToPeer) onError(err error, p *Peer, pkt *Packet) {
 	p2p.logger.Infoln("onError", err, p, pkt)
 
+	//Invalid packet, not an error of the connection
+	if _, ok := err.(net.Error); !ok && !p.isCloseError(err) {
+		p2p.penalize(p, module.PenaltyMajor, "InvalidPacket: "+err.Error())
+	}
+
 	//Peer.receiveRoutine
 	//// bufio.Reader.Read error except {net.OpError, io.EOF, io.ErrUnexpectedEOF}
 	//Peer.sendRoutine
@@ -321,6 +353,8 @@ func (p2p *PeerToPeer) onFailure(err error, pkt *Packet, c *Counter) {
 	//if !p2p.IsStarted() {
 	//	return
 	//}
+	// Failures of sending are local (ex. the queue is full), so they don't
+	// penalize the peer. Peers are penalized only for what they send.
 	p2p.logger.Debugln("onFailure", err, pkt, c)
 	if cbFunc, ok := p2p.onFailureCbFuncs[pkt.protocol.Uint16()]; ok {
 		cbFunc(err, pkt, c)
@@ -338,7 +372,7 @@ func (p2p *PeerToPeer) removePeer(p *Peer) (isLeave bool) {
 		p2p.roots.Add(p.netAddress)
 	}
 
-	isLeave = !(p.connType == p2pConnTypeNone)
+	isLeave = isJoined(p)
 	switch p.connType {
 	case p2pConnTypeNone:
 		p2p.orphanages.Remove(p)
@@ -385,7 +419,7 @@ func (p2p *PeerToPeer) onPacket(pkt *Packet, p *Peer) {
 			}
 		}
 	} else {
-		if p.connType == p2pConnTypeNone {
+		if !isJoined(p) {
 			p2p.logger.Infoln("onPacket", "Drop, undetermined PeerConnectionType", pkt.protocol, pkt.subProtocol)
 			return
 		}
@@ -399,12 +433,14 @@ func (p2p *PeerToPeer) onPacket(pkt *Packet, p *Peer) {
 		isOneHop := pkt.ttl != 0 || pkt.dest == p2pDestPeer
 		if isOneHop && !isSourcePeer {
 			p2p.logger.Infoln("onPacket", "Drop, Invalid 1hop-src:", pkt.src, ",expected:", p.id, pkt.protocol, pkt.subProtocol)
+			p2p.penalize(p, module.PenaltyMinor, "Invalid1hopSource")
 			return
 		}
 
 		isBroadcast := pkt.dest == p2pDestAny && pkt.ttl == 0
 		if isBroadcast && isSourcePeer && !p.hasRole(p2pRoleRoot) {
 			p2p.logger.Infoln("onPacket", "Drop, Not authorized", p.id, pkt.protocol, pkt.subProtocol)
+			p2p.penalize(p, module.PenaltyMinor, "NotAuthorizedBroadcast")
 			return
 		}
 
@@ -420,6 +456,22 @@ func (p2p *PeerToPeer) onPacket(pkt *Packet, p *Peer) {
 	}
 }
 
+// penalize decreases the score of the peer, and closes the connection if
+// the peer is banned by the penalty.
+func (p2p *PeerToPeer) penalize(p *Peer, penalty module.Penalty, reason string) {
+	if p2p.reputation.penalize(p.id, penalty, reason) {
+		p.CloseByError(ErrBannedPeer)
+	}
+}
+
+func (p2p *PeerToPeer) penalizeByID(id module.PeerID, penalty module.Penalty, reason string) {
+	if p := p2p.getPeer(id, false); p != nil {
+		p2p.penalize(p, penalty, reason)
+	} else {
+		p2p.reputation.penalize(id, penalty, reason)
+	}
+}
+
 func (p2p *PeerToPeer) encodeMsgpack(v interface{}) []byte {
 	b := make([]byte, DefaultPacketBufferSize)
 	enc := codec.MP.NewEncoderBytes(&b)
@@ -581,6 +633,7 @@ func (p2p *PeerToPeer) handleQuery(pkt *Packet, p *Peer) {
 	err := p2p.decodeMsgpack(pkt.payload, qm)
 	if err != nil {
 		p2p.logger.Infoln("handleQuery", err, p)
+		p2p.penalize(p, module.PenaltyMajor, "handleQuery: "+err.Error())
 		return
 	}
 	p2p.logger.Traceln("handleQuery", qm, p)
@@ -634,6 +687,7 @@ func (p2p *PeerToPeer) handleQueryResult(pkt *Packet, p *Peer) {
 	err := p2p.decodeMsgpack(pkt.payload, qrm)
 	if err != nil {
 		p2p.logger.Infoln("handleQueryResult", err, p)
+		p2p.penalize(p, module.PenaltyMajor, "handleQueryResult: "+err.Error())
 		return
 	}
 	p2p.logger.Traceln("handleQueryResult", qrm, p)
@@ -677,6 +731,7 @@ func (p2p *PeerToPeer) handleRttRequest(pkt *Packet, p *Peer) {
 	err := p2p.decodeMsgpack(pkt.payload, rm)
 	if err != nil {
 		p2p.logger.Infoln("handleRttRequest", err, p)
+		p2p.penalize(p, module.PenaltyMajor, "handleRttRequest: "+err.Error())
 		return
 	}
 	p2p.logger.Traceln("handleRttRequest", rm, p)
@@ -704,6 +759,7 @@ func (p2p *PeerToPeer) handleRttResponse(pkt *Packet, p *Peer) {
 	err := p2p.decodeMsgpack(pkt.payload, rm)
 	if err != nil {
 		p2p.logger.Infoln("handleRttResponse", err, p)
+		p2p.penalize(p, module.PenaltyMajor, "handleRttResponse: "+err.Error())
 		return
 	}
 	p2p.logger.Traceln("handleRttResponse", rm, p)
@@ -724,6 +780,20 @@ func (p2p *PeerToPeer) sendToPeers(ctx context.Context, peers *PeerSet) {
 	}
 }
 
+// sendToStaticPeers sends the packet to the static peers which are joined
+// without the connection type.
+func (p2p *PeerToPeer) sendToStaticPeers(ctx context.Context) {
+	for _, p := range p2p.orphanages.Array() {
+		if !p.static {
+			continue
+		}
+		if err := p.send(ctx); err != nil && err != ErrDuplicatedPacket {
+			pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
+			p2p.logger.Infoln("sendToStaticPeers", err, pkt.protocol, pkt.subProtocol, p.id)
+		}
+	}
+}
+
 func (p2p *PeerToPeer) selectPeersFromFriends(pkt *Packet) ([]*Peer, []byte) {
 	src := pkt.src
 
@@ -857,6 +927,7 @@ Loop:
 						p2p.sendToPeers(ctx, p2p.uncles)
 						p2p.sendToPeers(ctx, p2p.children)
 						p2p.sendToPeers(ctx, p2p.nephews)
+						p2p.sendToStaticPeers(ctx)
 					} else if pkt.ttl == 2 {
 						if r.Has(p2pRoleRoot) {
 							p2p.sendToFriends(ctx)
@@ -1084,10 +1155,8 @@ func (p2p *PeerToPeer) getPeer(id module.PeerID, onlyJoin bool) *Peer {
 	} else if p := p2p.friends.GetByID(id); p != nil {
 		return p
 	}
-	if !onlyJoin {
-		if p := p2p.orphanages.GetByID(id); p != nil {
-			return p
-		}
+	if p := p2p.orphanages.GetByID(id); p != nil && (!onlyJoin || p.static) {
+		return p
 	}
 	return nil
 }
@@ -1102,8 +1171,10 @@ func (p2p *PeerToPeer) getPeers(onlyJoin bool) []*Peer {
 	arr = append(arr, p2p.nephews.Array()...)
 	arr = append(arr, p2p.friends.Array()...)
 
-	if !onlyJoin {
-		arr = append(arr, p2p.orphanages.Array()...)
+	for _, p := range p2p.orphanages.Array() {
+		if !onlyJoin || p.static {
+			arr = append(arr, p)
+		}
 	}
 	return arr
 }
@@ -1232,13 +1303,17 @@ Loop:
 				}
 			} else {
 				for _, p := range seeds {
-					if !p.hasRole(p2pRoleRoot) {
+					if !p.hasRole(p2pRoleRoot) && !p.static {
 						p2p.logger.Debugln("discoverRoutine", "seedTicker", "no need outgoing p2pRoleSeed connection")
 						p.Close("discoverRoutine no need outgoing p2pRoleSeed connection")
 					}
 				}
 			}
+		case <-p2p.allowListTicker.C:
+			p2p.dialer.allowList.refreshAsync()
 		case <-p2p.discoveryTicker.C:
+			p2p.dialStaticPeers()
+
 			r := p2p.getRole()
 			pr := PeerRoleFlag(p2pRoleSeed)
 			strRole := "p2pRoleSeed"
@@ -1289,6 +1364,16 @@ Loop:
 	}
 }
 
+// dialStaticPeers dials to the static peers which are not connected.
+func (p2p *PeerToPeer) dialStaticPeers() {
+	for _, na := range p2p.staticPeers.Array() {
+		if !p2p.hasNetAddresse(na) {
+			p2p.logger.Debugln("dialStaticPeers", "dial to", na)
+			_ = p2p.dial(na)
+		}
+	}
+}
+
 func (p2p *PeerToPeer) syncSeeds() (connectAndQuery bool) {
 	role := p2p.getRole()
 
@@ -1511,7 +1596,7 @@ func (p2p *PeerToPeer) updatePeerConnectionType(p *Peer, connType PeerConnection
 		}
 	}
 
-	if updated {
+	if updated && !p.static {
 		if pre == p2pConnTypeNone {
 			p2p.onEvent(p2pEventJoin, p)
 		}
@@ -1548,6 +1633,7 @@ func (p2p *PeerToPeer) handleP2PConnectionRequest(pkt *Packet, p *Peer) {
 	err := p2p.decodeMsgpack(pkt.payload, req)
 	if err != nil {
 		p2p.logger.Infoln("handleP2PConnectionRequest", err, p)
+		p2p.penalize(p, module.PenaltyMajor, "handleP2PConnectionRequest: "+err.Error())
 		return
 	}
 	p2p.logger.Traceln("handleP2PConnectionRequest", req, p)
@@ -1592,6 +1678,7 @@ func (p2p *PeerToPeer) handleP2PConnectionResponse(pkt *Packet, p *Peer) {
 	err := p2p.decodeMsgpack(pkt.payload, resp)
 	if err != nil {
 		p2p.logger.Infoln("handleP2PConnectionResponse", err, p)
+		p2p.penalize(p, module.PenaltyMajor, "handleP2PConnectionResponse: "+err.Error())
 		return
 	}
 	p2p.logger.Traceln("handleP2PConnectionResponse", resp, p)
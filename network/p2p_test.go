@@ -0,0 +1,69 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+func newTestP2P(t *testing.T) *PeerToPeer {
+	self := &Peer{id: generatePeerID(), netAddress: "127.0.0.1:8080"}
+	bk, err := db.NewMapDB().GetBucket(db.ChainProperty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := newDialer("test", func(conn net.Conn, addr string, d *Dialer) {})
+	mtr := metric.NewNetworkMetric(metric.DefaultMetricContext())
+	return newPeerToPeer("test", self, d, bk, mtr, log.GlobalLogger())
+}
+
+func newTestPeer(na NetAddress) *Peer {
+	conn, _ := net.Pipe()
+	p := newPeer(conn, nil, true, log.GlobalLogger())
+	p.id = generatePeerID()
+	p.netAddress = na
+	return p
+}
+
+func TestPeerToPeer_StaticPeer(t *testing.T) {
+	p2p := newTestP2P(t)
+	p2p.staticPeers.Add("127.0.0.1:8081")
+
+	var joined []*Peer
+	p2p.setEventCbFunc(p2pEventJoin, ProtoTestNetworkNeighbor.Uint16(), func(evt string, p *Peer) {
+		joined = append(joined, p)
+	})
+	var received []*Peer
+	p2p.setCbFunc(ProtoTestNetworkNeighbor, func(pkt *Packet, p *Peer) {
+		received = append(received, p)
+	}, nil, nil)
+
+	static := newTestPeer("127.0.0.1:8081")
+	other := newTestPeer("127.0.0.1:8082")
+	p2p.onPeer(static)
+	p2p.onPeer(other)
+
+	// static peers join without the connection type
+	assert.Equal(t, []*Peer{static}, joined)
+	assert.Equal(t, static, p2p.getPeer(static.id, true))
+	assert.Nil(t, p2p.getPeer(other.id, true))
+	assert.Equal(t, []*Peer{static}, p2p.getPeers(true))
+
+	for _, p := range []*Peer{static, other} {
+		pkt := NewPacket(ProtoTestNetworkNeighbor, ProtoTestNetworkNeighbor, []byte("test"))
+		pkt.dest = p2pDestPeer
+		pkt.ttl = 1
+		pkt.src = p.id
+		p2p.onPacket(pkt, p)
+	}
+	assert.Equal(t, []*Peer{static}, received)
+
+	// they leave on close
+	assert.True(t, p2p.removePeer(static))
+	assert.False(t, p2p.removePeer(other))
+}
@@ -44,6 +44,7 @@ type Peer struct {
 	channel   string
 	rtt       PeerRTT
 	connType  PeerConnectionType
+	static    bool // joined regardless of connType
 	role      PeerRoleFlag
 	roleMtx   sync.RWMutex
 	children  *NetAddressSet
@@ -415,6 +416,10 @@ func (p *Peer) receiveRoutine() {
 			r := p.isTemporaryError(err)
 			p.logger.Tracef("Peer.receiveRoutine Error isTemporary:{%v} error:{%+v} peer:%s", r, err, p.String())
 			if !r {
+				//notify invalid packet before closing
+				if cbFunc := p.getErrorCbFunc(); cbFunc != nil && !p.isCloseError(err) {
+					cbFunc(err, p, pkt)
+				}
 				p.CloseByError(err)
 				return
 			}
@@ -278,3 +278,11 @@ func (ph *protocolHandler) Broadcast(pi module.ProtocolInfo, b []byte, bt module
 	}
 	return nil
 }
+
+func (ph *protocolHandler) Penalize(id module.PeerID, penalty module.Penalty, reason string) {
+	if !ph.IsRun() {
+		return
+	}
+	ph.logger.Debugln("Penalize", id, penalty, reason)
+	ph.m.p2p.penalizeByID(id, penalty, ph.name+": "+reason)
+}
@@ -0,0 +1,176 @@
+package network
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	keyPeerBans = "network.bans"
+)
+
+type PeerBan struct {
+	ID     string    `json:"id"`
+	Until  time.Time `json:"until"`
+	Reason string    `json:"reason"`
+}
+
+type peerScore struct {
+	score   int
+	updated time.Time
+}
+
+// reputation keeps scores of peers. A peer loses its score for misbehavior,
+// and recovers a point for each DefaultPeerScoreRecovery. The peer is banned
+// for DefaultPeerBanDuration if the score goes down to zero. Bans are stored
+// in the bucket, so they are kept across restarts.
+type reputation struct {
+	mtx    sync.Mutex
+	scores map[string]*peerScore
+	bans   map[string]*PeerBan
+	bucket db.Bucket
+	now    func() time.Time
+	logger log.Logger
+}
+
+func newReputation(bk db.Bucket, l log.Logger) *reputation {
+	r := &reputation{
+		scores: make(map[string]*peerScore),
+		bans:   make(map[string]*PeerBan),
+		bucket: bk,
+		now:    time.Now,
+		logger: l,
+	}
+	r.load()
+	return r
+}
+
+func (r *reputation) load() {
+	if r.bucket == nil {
+		return
+	}
+	bs, err := r.bucket.Get([]byte(keyPeerBans))
+	if err != nil || len(bs) == 0 {
+		return
+	}
+	var bans []*PeerBan
+	if err := json.Unmarshal(bs, &bans); err != nil {
+		r.logger.Warnf("fail to load peer bans err=%+v", err)
+		return
+	}
+	now := r.now()
+	for _, b := range bans {
+		if b.Until.After(now) {
+			r.bans[b.ID] = b
+		}
+	}
+}
+
+func (r *reputation) _store() {
+	if r.bucket == nil {
+		return
+	}
+	bs, err := json.Marshal(r._banList())
+	if err == nil {
+		err = r.bucket.Set([]byte(keyPeerBans), bs)
+	}
+	if err != nil {
+		r.logger.Warnf("fail to store peer bans err=%+v", err)
+	}
+}
+
+func (r *reputation) _score(id string, now time.Time) *peerScore {
+	ps, ok := r.scores[id]
+	if !ok {
+		return &peerScore{score: DefaultPeerScore, updated: now}
+	}
+	if n := int(now.Sub(ps.updated) / DefaultPeerScoreRecovery); n > 0 {
+		ps.score += n
+		ps.updated = ps.updated.Add(time.Duration(n) * DefaultPeerScoreRecovery)
+	}
+	if ps.score >= DefaultPeerScore {
+		ps.score = DefaultPeerScore
+		ps.updated = now
+	}
+	return ps
+}
+
+// penalize decreases the score of the peer, and returns true if the peer is
+// banned by the penalty.
+func (r *reputation) penalize(id module.PeerID, penalty module.Penalty, reason string) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	key := id.String()
+	if _, ok := r.bans[key]; ok {
+		return false
+	}
+	now := r.now()
+	ps := r._score(key, now)
+	ps.score -= int(penalty)
+	if ps.score > 0 {
+		r.scores[key] = ps
+		r.logger.Debugln("penalize", key, penalty, ps.score, reason)
+		return false
+	}
+	delete(r.scores, key)
+	r.bans[key] = &PeerBan{
+		ID:     key,
+		Until:  now.Add(DefaultPeerBanDuration),
+		Reason: reason,
+	}
+	r.logger.Infoln("ban", key, "until", r.bans[key].Until, reason)
+	r._store()
+	return true
+}
+
+func (r *reputation) isBanned(id module.PeerID) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	key := id.String()
+	b, ok := r.bans[key]
+	if !ok {
+		return false
+	}
+	if b.Until.After(r.now()) {
+		return true
+	}
+	delete(r.bans, key)
+	r._store()
+	return false
+}
+
+func (r *reputation) scoreOf(id module.PeerID) int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r._score(id.String(), r.now()).score
+}
+
+func (r *reputation) _banList() []*PeerBan {
+	now := r.now()
+	bans := make([]*PeerBan, 0, len(r.bans))
+	for _, b := range r.bans {
+		if b.Until.After(now) {
+			bans = append(bans, b)
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].ID < bans[j].ID
+	})
+	return bans
+}
+
+func (r *reputation) banList() []*PeerBan {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r._banList()
+}
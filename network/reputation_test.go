@@ -0,0 +1,54 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestReputation_Ban(t *testing.T) {
+	bk, err := db.NewMapDB().GetBucket(db.ChainProperty)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	r := newReputation(bk, log.New())
+	r.now = func() time.Time { return now }
+
+	id1 := generatePeerID()
+	id2 := generatePeerID()
+	for i := 0; i < 4; i++ {
+		assert.False(t, r.penalize(id1, module.PenaltyMajor, "test"))
+	}
+	assert.Equal(t, DefaultPeerScore-4*int(module.PenaltyMajor), r.scoreOf(id1))
+
+	// recover score
+	now = now.Add(2 * DefaultPeerScoreRecovery)
+	assert.Equal(t, DefaultPeerScore-4*int(module.PenaltyMajor)+2, r.scoreOf(id1))
+	now = now.Add(DefaultPeerScore * DefaultPeerScoreRecovery)
+	assert.Equal(t, DefaultPeerScore, r.scoreOf(id1))
+
+	assert.True(t, r.penalize(id2, module.PenaltyCritical, "critical"))
+	assert.True(t, r.isBanned(id2))
+	assert.False(t, r.isBanned(id1))
+	assert.False(t, r.penalize(id2, module.PenaltyCritical, "critical"))
+
+	// bans are kept across restarts
+	r2 := newReputation(bk, log.New())
+	r2.now = r.now
+	assert.True(t, r2.isBanned(id2))
+	if bans := r2.banList(); assert.Len(t, bans, 1) {
+		assert.Equal(t, id2.String(), bans[0].ID)
+		assert.Equal(t, "critical", bans[0].Reason)
+	}
+
+	// bans are expired
+	now = now.Add(DefaultPeerBanDuration)
+	assert.False(t, r2.isBanned(id2))
+	assert.Len(t, r2.banList(), 0)
+	assert.Len(t, newReputation(bk, log.New()).banList(), 0)
+}
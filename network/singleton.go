@@ -24,6 +24,8 @@ const (
 	QueueOverflowError
 	DuplicatedPacketError
 	DuplicatedPeerError
+	BannedPeerError
+	NotAllowedPeerError
 )
 
 var (
@@ -41,6 +43,8 @@ var (
 	ErrQueueOverflow             = errors.NewBase(QueueOverflowError, "QueueOverflow")
 	ErrDuplicatedPacket          = errors.NewBase(DuplicatedPacketError, "DuplicatedPacket")
 	ErrDuplicatedPeer            = errors.NewBase(DuplicatedPeerError, "DuplicatedPeer")
+	ErrBannedPeer                = errors.NewBase(BannedPeerError, "BannedPeer")
+	ErrNotAllowedPeer            = errors.NewBase(NotAllowedPeerError, "NotAllowedPeer")
 	ErrIllegalArgument           = errors.ErrIllegalArgument
 )
 
@@ -58,6 +62,7 @@ const (
 	DefaultPacketPoolBucketLen  = 500
 	DefaultDiscoveryPeriod      = 2 * time.Second
 	DefaultSeedPeriod           = 3 * time.Second
+	DefaultAllowListPeriod      = 1 * time.Minute
 	DefaultMinSeed              = 1
 	DefaultAlternateSendPeriod  = 1 * time.Second
 	DefaultSendTimeout          = 5 * time.Second
@@ -78,6 +83,9 @@ const (
 	DefaultSimplePeerIDSize     = 4
 	UsingSelectiveFlooding      = true
 	DefaultDuplicatedPeerTime   = 1 * time.Second
+	DefaultPeerScore            = 100
+	DefaultPeerScoreRecovery    = 30 * time.Second
+	DefaultPeerBanDuration      = 1 * time.Hour
 )
 
 var (
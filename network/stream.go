@@ -84,6 +84,7 @@ func (r *reactor) OnReceive(pi module.ProtocolInfo, b []byte, id module.PeerID)
 		_, e := codec.UnmarshalFromBytes(b, sm)
 		if e != nil {
 			err = e
+			r.ph.Penalize(id, module.PenaltyMajor, "InvalidStreamMessage")
 			return true
 		}
 		if !s.receive(sm) {
@@ -148,6 +149,10 @@ func (r *reactor) Multicast(pi module.ProtocolInfo, b []byte, role module.Role)
 	return errors.Errorf("Multicast is not supported for stream")
 }
 
+func (r *reactor) Penalize(id module.PeerID, penalty module.Penalty, reason string) {
+	r.ph.Penalize(id, penalty, reason)
+}
+
 func (r *reactor) Unicast(pi module.ProtocolInfo, b []byte, id module.PeerID) error {
 	r.Lock()
 	defer r.Unlock()
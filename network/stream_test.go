@@ -100,6 +100,9 @@ func (ph *tProtocolHandler) Multicast(pi module.ProtocolInfo, b []byte, role mod
 	panic("not implemented")
 }
 
+func (ph *tProtocolHandler) Penalize(id module.PeerID, penalty module.Penalty, reason string) {
+}
+
 func (ph *tProtocolHandler) Unicast(pi module.ProtocolInfo, b []byte, id module.PeerID) error {
 	if ph.nm.drop {
 		return nil
@@ -222,6 +222,7 @@ type Dialer struct {
 	onConnect connectCbFunc
 	channel   string
 	dialing   *Set
+	allowList *peerAllowList
 }
 
 type connectCbFunc func(conn net.Conn, addr string, d *Dialer)
@@ -231,10 +232,14 @@ func newDialer(channel string, cbFunc connectCbFunc) *Dialer {
 		onConnect: cbFunc,
 		channel:   channel,
 		dialing:   NewSet(),
+		allowList: newPeerAllowList(),
 	}
 }
 
 func (d *Dialer) Dial(addr string) error {
+	if !d.allowList.allowsAddress(NetAddress(addr)) {
+		return ErrNotAllowedPeer
+	}
 	if !d.dialing.Add(addr) {
 		return ErrAlreadyDialing
 	}
@@ -425,6 +430,10 @@ func (pd *PeerDispatcher) dispatchPeer(p *Peer) {
 func (pd *PeerDispatcher) onPeer(p *Peer) {
 	pd.logger.Traceln("onPeer", p)
 	if p2p := pd.getPeerToPeer(p.channel); p2p != nil {
+		if !p2p.dialer.allowList.allowsPeer(p) {
+			p.CloseByError(ErrNotAllowedPeer)
+			return
+		}
 		p.setMetric(p2p.mtr)
 		p.setPacketCbFunc(p2p.onPacket)
 		p.setErrorCbFunc(p2p.onError)
@@ -0,0 +1,65 @@
+package node
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common/log"
+)
+
+// AuditRecord is a denied request of the admin API.
+type AuditRecord struct {
+	Timestamp string `json:"timestamp"`
+	Address   string `json:"address,omitempty"`
+	Method    string `json:"method"`
+	Route     string `json:"route"`
+	Outcome   string `json:"outcome"`
+}
+
+// AuditLog appends records to the file in JSON lines.
+type AuditLog struct {
+	filePath string
+	mtx      sync.Mutex
+}
+
+func (l *AuditLog) Record(ctx echo.Context, addr string, outcome string) {
+	r := &AuditRecord{
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+		Address:   addr,
+		Method:    ctx.Request().Method,
+		Route:     ctx.Request().URL.Path,
+		Outcome:   outcome,
+	}
+	log.Warnf("Denied request addr=%s method=%s route=%s outcome=%s",
+		r.Address, r.Method, r.Route, r.Outcome)
+	if l == nil || l.filePath == "" {
+		return
+	}
+	if err := l.write(r); err != nil {
+		log.Warnf("Fail to write audit log err=%+v", err)
+	}
+}
+
+func (l *AuditLog) write(r *AuditRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	f, err := os.OpenFile(l.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+func NewAuditLog(filePath string) *AuditLog {
+	return &AuditLog{filePath: filePath}
+}
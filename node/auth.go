@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,16 +23,90 @@ import (
 
 const (
 	AuthScheme = "goloop"
+	// ContextUser is the key of the authenticated user id in echo.Context.
+	ContextUser = "user"
 )
 
+// Role is the permission level of the user. A role includes permissions
+// of the lower roles.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleViewer
+	RoleOperator
+	RoleAdmin
+)
+
+var roleNames = []string{"none", "viewer", "operator", "admin"}
+
+func (r Role) String() string {
+	if r >= 0 && int(r) < len(roleNames) {
+		return roleNames[r]
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+func ParseRole(s string) (Role, error) {
+	for i, name := range roleNames {
+		if name == s {
+			return Role(i), nil
+		}
+	}
+	return RoleNone, errors.IllegalArgumentError.Errorf("InvalidRole(role=%s)", s)
+}
+
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Role) UnmarshalText(b []byte) error {
+	role, err := ParseRole(string(b))
+	if err != nil {
+		return err
+	}
+	*r = role
+	return nil
+}
+
+// User is a registered user of the admin API. Role is applied to all
+// chains and the node itself. Chains has roles for the specific chains,
+// which override Role.
+type User struct {
+	Id     string          `json:"id"`
+	Role   Role            `json:"role"`
+	Chains map[string]Role `json:"chains,omitempty"`
+}
+
+func chainKeyOf(cid int) string {
+	return fmt.Sprintf("%#x", cid)
+}
+
+// RoleOf returns the role of the user for the chain. Use -1 as cid for
+// the requests not related to any chain.
+func (u *User) RoleOf(cid int) Role {
+	if cid >= 0 {
+		if role, ok := u.Chains[chainKeyOf(cid)]; ok {
+			return role
+		}
+	}
+	return u.Role
+}
+
+type authUser struct {
+	*User
+	timestamp int64
+}
+
 type Auth struct {
-	skips map[string]map[string]bool
-	users map[string]int64
-	addrs map[string]string
-	filePath string
-	prefix string
+	skips            map[string]map[string]bool
+	users            map[string]*authUser
+	addrs            map[string]string
+	filePath         string
+	prefix           string
+	audit            *AuditLog
 	SkipIfEmptyUsers bool
-	mtx   sync.Mutex
+	mtx              sync.Mutex
 }
 
 func (a *Auth) MiddlewareFunc() echo.MiddlewareFunc {
@@ -49,19 +124,58 @@ func (a *Auth) MiddlewareFunc() echo.MiddlewareFunc {
 			}
 			key, err := a.extractor(ctx)
 			if err != nil {
+				a.audit.Record(ctx, "", "unauthorized("+err.Error()+")")
 				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
 			}
-			valid, err := a.validator(key, ctx)
+			addr, id, err := a.validator(key, ctx)
 			if err != nil {
+				a.audit.Record(ctx, addr, "unauthorized("+err.Error()+")")
 				return err
-			} else if valid {
+			} else if id != "" {
+				ctx.Set(ContextUser, id)
 				return next(ctx)
 			}
+			a.audit.Record(ctx, addr, "unauthorized")
 			return echo.ErrUnauthorized
 		}
 	}
 }
 
+// Authorizer returns the middleware allowing the request only if
+// the authenticated user has the role. It should be placed after
+// ChainInjector for the chain specific requests. Requests without
+// authentication are passed.
+func (a *Auth) Authorizer(role Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			id, ok := ctx.Get(ContextUser).(string)
+			if !ok {
+				return next(ctx)
+			}
+			cid := -1
+			if c, ok := ctx.Get("chain").(*Chain); ok {
+				cid = c.CID()
+			}
+			if ur := a.RoleOf(id, cid); ur < role {
+				a.audit.Record(ctx, id,
+					fmt.Sprintf("forbidden(role=%s,required=%s)", ur, role))
+				return echo.ErrForbidden
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func (a *Auth) RoleOf(id string, cid int) Role {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if u, ok := a.users[id]; ok {
+		return u.RoleOf(cid)
+	}
+	return RoleNone
+}
+
 func (a *Auth) SetSkip(r *echo.Route, skip bool) {
 	m, ok := a.skips[r.Method]
 	if !ok {
@@ -114,11 +228,13 @@ func parse(s string) map[string]string {
 	return m
 }
 
-func (a *Auth) validator(s string, ctx echo.Context) (b bool, err error) {
+// validator verifies the key, and returns the address of the signer and
+// the id of the user. The id is empty if the signer isn't a valid user.
+func (a *Auth) validator(s string, ctx echo.Context) (addr string, id string, err error) {
 	log.Traceln("validator:", s)
 	m := parse(s)
 	var timestamp int64
-	if timestamp, err = strconv.ParseInt(m["Timestamp"], 0, 64); err != nil{
+	if timestamp, err = strconv.ParseInt(m["Timestamp"], 0, 64); err != nil {
 		return
 	}
 	var signature []byte
@@ -129,7 +245,7 @@ func (a *Auth) validator(s string, ctx echo.Context) (b bool, err error) {
 	if sig, err = crypto.ParseSignature(signature); err != nil {
 		return
 	}
-	url := strings.Replace(ctx.Request().URL.EscapedPath(),a.prefix,"",1)
+	url := strings.Replace(ctx.Request().URL.EscapedPath(), a.prefix, "", 1)
 	serialized := fmt.Sprintf("Method=%s,Url=%s,Timestamp=%s",
 		ctx.Request().Method, url, m["Timestamp"])
 
@@ -137,28 +253,39 @@ func (a *Auth) validator(s string, ctx echo.Context) (b bool, err error) {
 	if pubKey, err = sig.RecoverPublicKey(crypto.SHA3Sum256([]byte(serialized))); err != nil {
 		return
 	}
-	addr := common.NewAccountAddressFromPublicKey(pubKey).String()
-	log.Traceln("addr:",addr,"serialized:", serialized)
+	addr = common.NewAccountAddressFromPublicKey(pubKey).String()
+	log.Traceln("addr:", addr, "serialized:", serialized)
 
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
-	if id, ok := a.addrs[addr]; ok {
-		if ts := a.users[id]; ts < timestamp {
-			a.users[id] = timestamp
-			log.Traceln("valid signature", ts, timestamp)
-			return true, nil
+	if uid, ok := a.addrs[addr]; ok {
+		if u := a.users[uid]; u.timestamp < timestamp {
+			log.Traceln("valid signature", u.timestamp, timestamp)
+			u.timestamp = timestamp
+			return addr, uid, nil
 		}
-		log.Traceln("old signature", a.users[id], timestamp)
-		return false, nil
+		log.Traceln("old signature", a.users[uid].timestamp, timestamp)
+		return addr, "", nil
 	}
 	log.Traceln("not found user", addr)
-	return false, nil
+	return addr, "", nil
 }
 
-func (a *Auth) AddUser(id string) error {
+func (a *Auth) AddUser(user *User) error {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
+	if err := a._addUser(user); err != nil {
+		return err
+	}
+	if err := a._export(); err != nil {
+		panic(err)
+	}
+	return nil
+}
+
+func (a *Auth) _addUser(user *User) error {
+	id := user.Id
 	if _, ok := a.users[id]; ok {
 		return errors.Wrapf(ErrAlreadyExists, "User(id=%s) already exists", id)
 	}
@@ -172,11 +299,19 @@ func (a *Auth) AddUser(id string) error {
 		return errors.Wrapf(ErrAlreadyExists, "User(addr=%s) already exists", addr.String())
 	}
 
-	a.users[id] = time.Now().Unix()
-	a.addrs[addr.String()] = id
-	if err := a._export(); err != nil {
-		panic(err)
+	u := &User{Id: id, Role: user.Role}
+	for key, role := range user.Chains {
+		cid, ok := cidOfSelector(key)
+		if !ok {
+			return errors.IllegalArgumentError.Errorf("InvalidChainID(cid=%s)", key)
+		}
+		if u.Chains == nil {
+			u.Chains = make(map[string]Role)
+		}
+		u.Chains[chainKeyOf(cid)] = role
 	}
+	a.users[id] = &authUser{User: u, timestamp: time.Now().Unix()}
+	a.addrs[addr.String()] = id
 	return nil
 }
 
@@ -203,11 +338,14 @@ func (a *Auth) RemoveUser(id string) error {
 	return nil
 }
 
-func (a *Auth) _users() []string {
-	users := make([]string, 0)
-	for user := range a.users {
-		users = append(users, user)
+func (a *Auth) _users() []*User {
+	users := make([]*User, 0, len(a.users))
+	for _, u := range a.users {
+		users = append(users, u.User)
 	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Id < users[j].Id
+	})
 	return users
 }
 
@@ -217,7 +355,7 @@ func (a *Auth) IsEmptyUsers() bool {
 	return len(a.users) == 0
 }
 
-func (a *Auth) GetUsers() []string {
+func (a *Auth) GetUsers() []*User {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
@@ -229,7 +367,7 @@ func (a *Auth) _export() error {
 		users := a._users()
 		if b, err := json.Marshal(users); err != nil {
 			return err
-		}else {
+		} else {
 			if err = ioutil.WriteFile(a.filePath, b, 0644); err != nil {
 				return err
 			}
@@ -238,13 +376,40 @@ func (a *Auth) _export() error {
 	return nil
 }
 
-func NewAuth(filePath, prefix string) *Auth {
+// _import loads users from the file. An address without the role is
+// loaded as an admin for the users registered before the roles.
+func (a *Auth) _import() error {
+	b, err := ioutil.ReadFile(a.filePath)
+	if err != nil {
+		return err
+	}
+	var entries []json.RawMessage
+	if err = json.Unmarshal(b, &entries); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		user := &User{Role: RoleAdmin}
+		if err = json.Unmarshal(entry, &user.Id); err != nil {
+			user = new(User)
+			if err = json.Unmarshal(entry, user); err != nil {
+				return err
+			}
+		}
+		if err = a._addUser(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewAuth(filePath, prefix, auditPath string) *Auth {
 	a := &Auth{
-		skips: make(map[string]map[string]bool),
-		users: make(map[string]int64),
-		addrs: make(map[string]string),
+		skips:    make(map[string]map[string]bool),
+		users:    make(map[string]*authUser),
+		addrs:    make(map[string]string),
 		filePath: filePath,
-		prefix: prefix,
+		prefix:   prefix,
+		audit:    NewAuditLog(auditPath),
 	}
 	if a.filePath != "" {
 		if _, err := os.Stat(filePath); err != nil {
@@ -254,18 +419,8 @@ func NewAuth(filePath, prefix string) *Auth {
 				}
 			}
 		}
-		if b, err := ioutil.ReadFile(filePath); err != nil {
+		if err := a._import(); err != nil {
 			panic(err)
-		} else {
-			var users []string
-			if err = json.Unmarshal(b, &users); err != nil {
-				panic(err)
-			}
-			for _, user := range users {
-				if err = a.AddUser(user); err != nil {
-					panic(err)
-				}
-			}
 		}
 	}
 	return a
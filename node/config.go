@@ -108,6 +108,7 @@ type RuntimeConfig struct {
 	EEInstances       int    `json:"eeInstances"`
 	RPCDefaultChannel string `json:"rpcDefaultChannel"`
 	RPCIncludeDebug   bool   `json:"rpcIncludeDebug"`
+	RPCBatchLimit     int    `json:"rpcBatchLimit"`
 
 	FilePath string `json:"-"` // absolute path
 }
@@ -0,0 +1,117 @@
+package node
+
+import (
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// DBBucket is a bucket of the chain database exposed for debugging.
+type DBBucket struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+var dbBuckets = []DBBucket{
+	{"merkle_trie", string(db.MerkleTrie)},
+	{"bytes_by_hash", string(db.BytesByHash)},
+	{"tx_locator", string(db.TransactionLocatorByHash)},
+	{"block_header_hash", string(db.BlockHeaderHashByHeight)},
+	{"block_v1", string(db.BlockV1ByHash)},
+	{"receipt_v1", string(db.ReceiptV1ByHash)},
+	{"chain_property", string(db.ChainProperty)},
+	{"tx_by_address", string(db.TransactionHashByAddress)},
+	{"evidence", string(db.EvidenceByHeight)},
+	{"block_mta", string(db.BlockMTA)},
+}
+
+func DBBuckets() []DBBucket {
+	return dbBuckets
+}
+
+func bucketIDOf(name string) (db.BucketID, error) {
+	for _, b := range dbBuckets {
+		if b.Name == name {
+			return db.BucketID(b.ID), nil
+		}
+	}
+	return "", errors.NotFoundError.Errorf("BucketNotFound(name=%s)", name)
+}
+
+type DBEntry struct {
+	Key   common.HexBytes `json:"key"`
+	Value common.HexBytes `json:"value"`
+}
+
+// _iterateDB calls f for the entries of the bucket in the range. The chain
+// should be stopped, so that nothing is written during the iteration.
+func (n *Node) _iterateDB(cid int, name string, start, limit []byte, f func(it db.Iterator) bool) error {
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	if !c.IsStopped() {
+		return errors.InvalidStateError.Errorf("ChainNotStopped(cid=%d)", cid)
+	}
+	id, err := bucketIDOf(name)
+	if err != nil {
+		return err
+	}
+	bk, err := c.Database().GetBucket(id)
+	if err != nil {
+		return err
+	}
+	it := bk.NewIterator(start, limit)
+	defer it.Release()
+	for it.Next() {
+		if !f(it) {
+			break
+		}
+	}
+	return it.Error()
+}
+
+func (n *Node) CountChainDB(cid int, name string, start, limit []byte) (int64, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	var count int64
+	err := n._iterateDB(cid, name, start, limit, func(it db.Iterator) bool {
+		count++
+		return true
+	})
+	return count, err
+}
+
+// DumpChainDB returns at most max entries of the bucket in the range.
+// If max isn't positive, then it returns all the entries.
+func (n *Node) DumpChainDB(cid int, name string, start, limit []byte, max int) ([]DBEntry, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	entries := make([]DBEntry, 0)
+	err := n._iterateDB(cid, name, start, limit, func(it db.Iterator) bool {
+		entries = append(entries, DBEntry{
+			Key:   append([]byte{}, it.Key()...),
+			Value: append([]byte{}, it.Value()...),
+		})
+		return max <= 0 || len(entries) < max
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindChainOrphans returns the number of orphaned trie nodes and at most
+// limit keys of them. It waits for the end of the scan without holding the
+// lock of the node.
+func (n *Node) FindChainOrphans(cid int, limit int) (int64, [][]byte, error) {
+	n.mtx.RLock()
+	c, err := n._get(cid)
+	n.mtx.RUnlock()
+	if err != nil {
+		return 0, nil, err
+	}
+	return c.FindOrphans(limit)
+}
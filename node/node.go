@@ -398,6 +398,18 @@ func (n *Node) JoinChain(
 	if err := n._canAdd(cid, nid, channel, false); err != nil {
 		return nil, err
 	}
+	if _, err := chain.ParseAddressList(p.TxPoolAllowList); err != nil {
+		return nil, err
+	}
+	if _, err := chain.ParseAddressList(p.TxPoolDenyList); err != nil {
+		return nil, err
+	}
+	if _, err := network.ParseNetAddressList(p.StaticPeers); err != nil {
+		return nil, err
+	}
+	if _, _, err := network.ParsePeerAllowList(p.AllowedPeers); err != nil {
+		return nil, err
+	}
 
 	chainDir, err := n._mkChainDir(cid)
 	if err != nil {
@@ -414,6 +426,8 @@ func (n *Node) JoinChain(
 		SecureSuites:     p.SecureSuites,
 		SecureAeads:      p.SecureAeads,
 		SeedAddr:         p.SeedAddr,
+		StaticPeers:      p.StaticPeers,
+		AllowedPeers:     p.AllowedPeers,
 		Role:             p.Role,
 		GenesisStorage:   genesisStorage,
 		ConcurrencyLevel: p.ConcurrencyLevel,
@@ -424,8 +438,20 @@ func (n *Node) JoinChain(
 		DefWaitTimeout:   p.DefWaitTimeout,
 		MaxWaitTimeout:   p.MaxWaitTimeout,
 		AutoStart:        p.AutoStart,
-		FilePath:         cfgFile,
-		NIDForP2P:        n.cfg.NIDForP2P,
+		AccountIndex:     p.AccountIndex,
+		MaxLogsRange:     p.MaxLogsRange,
+		MaxLogsResult:    p.MaxLogsResult,
+
+		TxPoolMaxPerSender: p.TxPoolMaxPerSender,
+		TxPoolAllowList:    p.TxPoolAllowList,
+		TxPoolDenyList:     p.TxPoolDenyList,
+		TxRateLimit:        p.TxRateLimit,
+		StateRetention:     p.StateRetention,
+		ReceiptRetention:   p.ReceiptRetention,
+		BlockRetention:     p.BlockRetention,
+		SnapshotInterval:   p.SnapshotInterval,
+		FilePath:           cfgFile,
+		NIDForP2P:          n.cfg.NIDForP2P,
 	}
 
 	if err := n.saveChainConfig(cfg, cfgFile); err != nil {
@@ -527,6 +553,50 @@ func (n *Node) ImportChain(cid int, s string, height int64) error {
 	return c.Import(s, height)
 }
 
+func (n *Node) ImportChainArchive(cid int, file string) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	return c.ImportArchive(file)
+}
+
+func (n *Node) ImportChainSnapshot(cid int, file string) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	return c.ImportSnapshot(file)
+}
+
+func (n *Node) ExportChain(cid int, file string, from, to int64, receipts bool) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	return c.Export(file, from, to, receipts)
+}
+
+func (n *Node) CheckChain(cid int, file string, repair bool) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	return c.Check(file, repair)
+}
+
 func (n *Node) PruneChain(cid int, dbt string, height int64) error {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
@@ -540,7 +610,7 @@ func (n *Node) PruneChain(cid int, dbt string, height int64) error {
 	return c.Prune(gs, dbt, height)
 }
 
-func (n *Node) BackupChain(cid int) (string, error) {
+func (n *Node) BackupChain(cid int, online bool, base string) (string, error) {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
 
@@ -554,11 +624,18 @@ func (n *Node) BackupChain(cid int) (string, error) {
 		return "", errors.InvalidStateError.Wrapf(err,
 			"Fail to make backup directory=%s", backupDir)
 	}
+	var baseFile string
+	if base != "" {
+		baseFile = path.Join(backupDir, path.Base(base))
+		if st, err := os.Stat(baseFile); err != nil || !st.Mode().IsRegular() {
+			return "", errors.NotFoundError.Errorf("BaseBackupNotFound(name=%s)", base)
+		}
+	}
 	now := time.Now()
 	name := fmt.Sprintf("%#x_%#x_%s_%s.zip", c.CID(), c.NID(), c.Channel(),
 		now.Format("20060102-150405"))
 	file := path.Join(backupDir, name)
-	return name, c.Backup(file, []string{ChainGenesisZipFileName, ChainConfigFileName})
+	return name, c.Backup(file, []string{ChainGenesisZipFileName, ChainConfigFileName}, online, baseFile)
 }
 
 type BackupInfo struct {
@@ -656,6 +733,18 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 		case "seedAddress":
 			c.cfg.SeedAddr = value
 			c.NetworkManager().SetTrustSeeds(c.cfg.SeedAddr)
+		case "staticPeers":
+			if _, err := network.ParseNetAddressList(value); err != nil {
+				return err
+			}
+			c.cfg.StaticPeers = value
+			c.NetworkManager().SetStaticPeers(c.cfg.StaticPeers)
+		case "allowedPeers":
+			if _, _, err := network.ParsePeerAllowList(value); err != nil {
+				return err
+			}
+			c.cfg.AllowedPeers = value
+			c.NetworkManager().SetPeerAllowList(c.cfg.AllowedPeers)
 		case "role":
 			if uintVal, err := strconv.ParseUint(value, 0, 32); err != nil {
 				return errors.Wrapf(err, "invalid value type")
@@ -738,6 +827,16 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			c.cfg.SecureAeads = value
 		case "seedAddress":
 			c.cfg.SeedAddr = value
+		case "staticPeers":
+			if _, err := network.ParseNetAddressList(value); err != nil {
+				return err
+			}
+			c.cfg.StaticPeers = value
+		case "allowedPeers":
+			if _, _, err := network.ParsePeerAllowList(value); err != nil {
+				return err
+			}
+			c.cfg.AllowedPeers = value
 		case "role":
 			if uintVal, err := strconv.ParseUint(value, 0, 32); err != nil {
 				return errors.Wrapf(err, "invalid value type")
@@ -750,6 +849,70 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.AutoStart = as
 			}
+		case "accountIndex":
+			if ai, err := strconv.ParseBool(value); err != nil {
+				return err
+			} else {
+				c.cfg.AccountIndex = ai
+			}
+		case "maxLogsRange":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.MaxLogsRange = intVal
+			}
+		case "maxLogsResult":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.MaxLogsResult = intVal
+			}
+		case "txPoolMaxPerSender":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.TxPoolMaxPerSender = intVal
+			}
+		case "txPoolAllowList":
+			if _, err := chain.ParseAddressList(value); err != nil {
+				return err
+			}
+			c.cfg.TxPoolAllowList = value
+		case "txPoolDenyList":
+			if _, err := chain.ParseAddressList(value); err != nil {
+				return err
+			}
+			c.cfg.TxPoolDenyList = value
+		case "txRateLimit":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.TxRateLimit = intVal
+			}
+		case "stateRetention":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.StateRetention = intVal
+			}
+		case "receiptRetention":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.ReceiptRetention = intVal
+			}
+		case "blockRetention":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.BlockRetention = intVal
+			}
+		case "snapshotInterval":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.SnapshotInterval = intVal
+			}
 		default:
 			return errors.Errorf("not found key %s", key)
 		}
@@ -773,6 +936,63 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 	}
 }
 
+func splitPeerList(s string) []string {
+	l := make([]string, 0)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+func editPeerList(s string, peers []string, remove bool) string {
+	l := splitPeerList(s)
+	for _, p := range peers {
+		p = strings.TrimSpace(p)
+		idx := -1
+		for i, v := range l {
+			if v == p {
+				idx = i
+				break
+			}
+		}
+		if remove && idx >= 0 {
+			l = append(l[:idx], l[idx+1:]...)
+		} else if !remove && idx < 0 && len(p) > 0 {
+			l = append(l, p)
+		}
+	}
+	return strings.Join(l, ",")
+}
+
+// EditChainPeers adds or removes static peers and allowed peers of the chain.
+// It's applied to the network immediately if the chain is started.
+func (n *Node) EditChainPeers(cid int, static, allowed []string, remove bool) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	staticPeers := editPeerList(c.cfg.StaticPeers, static, remove)
+	if _, err := network.ParseNetAddressList(staticPeers); err != nil {
+		return err
+	}
+	allowedPeers := editPeerList(c.cfg.AllowedPeers, allowed, remove)
+	if _, _, err := network.ParsePeerAllowList(allowedPeers); err != nil {
+		return err
+	}
+	c.cfg.StaticPeers = staticPeers
+	c.cfg.AllowedPeers = allowedPeers
+	if c.IsStarted() {
+		c.NetworkManager().SetStaticPeers(c.cfg.StaticPeers)
+		c.NetworkManager().SetPeerAllowList(c.cfg.AllowedPeers)
+	}
+	return n.saveChainConfig(c.cfg, c.cfg.FilePath)
+}
+
 func (n *Node) GetChains() []*Chain {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
@@ -844,6 +1064,13 @@ func (n *Node) Configure(key string, value string) error {
 			n.rcfg.RPCIncludeDebug = boolVal
 		}
 		n.srv.SetIncludeDebug(n.rcfg.RPCIncludeDebug)
+	case "rpcBatchLimit":
+		if intVal, err := strconv.Atoi(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.RPCBatchLimit = intVal
+		}
+		n.srv.SetBatchLimit(n.rcfg.RPCBatchLimit)
 	default:
 		return errors.Errorf("not found key")
 	}
@@ -876,6 +1103,7 @@ func NewNode(
 		_ = nt.SetListenAddress(cfg.P2PListenAddr)
 	}
 	srv := server.NewManager(cfg.RPCAddr, cfg.RPCDump, rcfg.RPCIncludeDebug, rcfg.RPCDefaultChannel, w, l)
+	srv.SetBatchLimit(rcfg.RPCBatchLimit)
 
 	ee, err := eeproxy.AllocEngines(l, strings.Split(cfg.Engines, ",")...)
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 
 	"github.com/icon-project/goloop/chain"
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
@@ -34,6 +35,7 @@ const (
 	UrlChainRes = "/:" + ParamCID
 	ParamID     = "id"
 	UrlUserRes  = "/:" + ParamID
+	ParamBucket = "bucket"
 )
 
 type Rest struct {
@@ -79,6 +81,8 @@ type ChainInspectView struct {
 type ChainConfig struct {
 	DBType           string `json:"dbType"`
 	SeedAddr         string `json:"seedAddress"`
+	StaticPeers      string `json:"staticPeers,omitempty"`
+	AllowedPeers     string `json:"allowedPeers,omitempty"`
 	Role             uint   `json:"role"`
 	ConcurrencyLevel int    `json:"concurrencyLevel,omitempty"`
 	NormalTxPoolSize int    `json:"normalTxPool,omitempty"`
@@ -91,11 +95,34 @@ type ChainConfig struct {
 	DefWaitTimeout   int64  `json:"defaultWaitTimeout"`
 	MaxWaitTimeout   int64  `json:"maxWaitTimeout"`
 	AutoStart        bool   `json:"autoStart"`
+	AccountIndex     bool   `json:"accountIndex,omitempty"`
+	MaxLogsRange     int    `json:"maxLogsRange,omitempty"`
+	MaxLogsResult    int    `json:"maxLogsResult,omitempty"`
+
+	TxPoolMaxPerSender int    `json:"txPoolMaxPerSender,omitempty"`
+	TxPoolAllowList    string `json:"txPoolAllowList,omitempty"`
+	TxPoolDenyList     string `json:"txPoolDenyList,omitempty"`
+	TxRateLimit        int    `json:"txRateLimit,omitempty"`
+
+	StateRetention   int64 `json:"stateRetention,omitempty"`
+	ReceiptRetention int64 `json:"receiptRetention,omitempty"`
+	BlockRetention   int64 `json:"blockRetention,omitempty"`
+	SnapshotInterval int64 `json:"snapshotInterval,omitempty"`
 }
 
 type ChainImportParam struct {
-	DBPath string `json:"dbPath"`
-	Height int64  `json:"height"`
+	DBPath        string `json:"dbPath"`
+	Height        int64  `json:"height"`
+	Archive       string `json:"archive,omitempty"`
+	Snapshot      string `json:"snapshot,omitempty"`
+	FetchSnapshot bool   `json:"fetchSnapshot,omitempty"`
+}
+
+type ChainExportParam struct {
+	File     string `json:"file"`
+	From     int64  `json:"from"`
+	To       int64  `json:"to"`
+	Receipts bool   `json:"receipts,omitempty"`
 }
 
 type ChainPruneParam struct {
@@ -103,6 +130,50 @@ type ChainPruneParam struct {
 	Height int64  `json:"height"`
 }
 
+type ChainCheckParam struct {
+	File   string `json:"file,omitempty"`
+	Repair bool   `json:"repair,omitempty"`
+}
+
+type ChainBackupParam struct {
+	Online bool   `json:"online,omitempty"`
+	Base   string `json:"base,omitempty"`
+}
+
+// UserParam is the parameter to add the user. Role is applied to the chains
+// in Chains, or all chains if it's empty. Role is admin if it's empty.
+type UserParam struct {
+	Id     string   `json:"id"`
+	Role   string   `json:"role,omitempty"`
+	Chains []string `json:"chains,omitempty"`
+}
+
+type ChainDBCountView struct {
+	Count int64 `json:"count"`
+}
+
+type ChainOrphansParam struct {
+	Limit int `json:"limit"`
+}
+
+type ChainOrphansView struct {
+	Count int64             `json:"count"`
+	Keys  []common.HexBytes `json:"keys"`
+}
+
+// ChainPeersParam is the parameter to add or remove peers. Static is the
+// list of network addresses of static peers, and Allowed is the list of
+// peer IDs or network addresses allowed to connect.
+type ChainPeersParam struct {
+	Static  []string `json:"static,omitempty"`
+	Allowed []string `json:"allowed,omitempty"`
+}
+
+type ChainPeersView struct {
+	Static  []string `json:"static"`
+	Allowed []string `json:"allowed"`
+}
+
 type ConfigureParam struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -147,6 +218,8 @@ func NewChainConfig(cfg *chain.Config) *ChainConfig {
 	v := &ChainConfig{
 		DBType:           cfg.DBType,
 		SeedAddr:         cfg.SeedAddr,
+		StaticPeers:      cfg.StaticPeers,
+		AllowedPeers:     cfg.AllowedPeers,
 		Role:             cfg.Role,
 		ConcurrencyLevel: cfg.ConcurrencyLevel,
 		NormalTxPoolSize: cfg.NormalTxPoolSize,
@@ -159,6 +232,19 @@ func NewChainConfig(cfg *chain.Config) *ChainConfig {
 		DefWaitTimeout:   cfg.DefWaitTimeout,
 		MaxWaitTimeout:   cfg.MaxWaitTimeout,
 		AutoStart:        cfg.AutoStart,
+		AccountIndex:     cfg.AccountIndex,
+		MaxLogsRange:     cfg.MaxLogsRange,
+		MaxLogsResult:    cfg.MaxLogsResult,
+
+		TxPoolMaxPerSender: cfg.TxPoolMaxPerSender,
+		TxPoolAllowList:    cfg.TxPoolAllowList,
+		TxPoolDenyList:     cfg.TxPoolDenyList,
+		TxRateLimit:        cfg.TxRateLimit,
+
+		StateRetention:   cfg.StateRetention,
+		ReceiptRetention: cfg.ReceiptRetention,
+		BlockRetention:   cfg.BlockRetention,
+		SnapshotInterval: cfg.SnapshotInterval,
 	}
 	return v
 }
@@ -174,7 +260,8 @@ func RegisterInspectFunc(name string, f InspectFunc) error {
 func RegisterRest(n *Node) {
 	r := Rest{
 		n: n,
-		a: NewAuth(path.Join(n.cfg.ResolveAbsolute(n.cfg.BaseDir), "auth.json"), server.UrlAdmin),
+		a: NewAuth(path.Join(n.cfg.ResolveAbsolute(n.cfg.BaseDir), "auth.json"), server.UrlAdmin,
+			path.Join(n.cfg.ResolveAbsolute(n.cfg.BaseDir), "audit.log")),
 	}
 	r.a.SkipIfEmptyUsers = n.cfg.AuthSkipIfEmptyUsers
 	ag := n.srv.AdminEchoGroup(r.a.MiddlewareFunc())
@@ -192,24 +279,37 @@ func RegisterRest(n *Node) {
 }
 
 func (r *Rest) RegisterChainHandlers(g *echo.Group) {
-	g.GET("", r.GetChains)
-	g.POST("", r.JoinChain)
-
-	g.GET(UrlChainRes, r.GetChain, r.ChainInjector)
-	g.DELETE(UrlChainRes, r.LeaveChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/start", r.StartChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/stop", r.StopChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/reset", r.ResetChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/verify", r.VerifyChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/import", r.ImportChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/prune", r.PruneChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/backup", r.BackupChain, r.ChainInjector)
-	route := g.GET(UrlChainRes+"/genesis", r.GetChainGenesis, r.ChainInjector)
+	viewer := r.a.Authorizer(RoleViewer)
+	operator := r.a.Authorizer(RoleOperator)
+	admin := r.a.Authorizer(RoleAdmin)
+
+	g.GET("", r.GetChains, viewer)
+	g.POST("", r.JoinChain, admin)
+
+	g.GET(UrlChainRes, r.GetChain, r.ChainInjector, viewer)
+	g.DELETE(UrlChainRes, r.LeaveChain, r.ChainInjector, admin)
+	g.POST(UrlChainRes+"/start", r.StartChain, r.ChainInjector, operator)
+	g.POST(UrlChainRes+"/stop", r.StopChain, r.ChainInjector, operator)
+	g.POST(UrlChainRes+"/reset", r.ResetChain, r.ChainInjector, admin)
+	g.POST(UrlChainRes+"/verify", r.VerifyChain, r.ChainInjector, operator)
+	g.POST(UrlChainRes+"/import", r.ImportChain, r.ChainInjector, admin)
+	g.POST(UrlChainRes+"/export", r.ExportChain, r.ChainInjector, operator)
+	g.POST(UrlChainRes+"/prune", r.PruneChain, r.ChainInjector, admin)
+	g.POST(UrlChainRes+"/backup", r.BackupChain, r.ChainInjector, operator)
+	g.POST(UrlChainRes+"/check", r.CheckChain, r.ChainInjector, operator)
+	g.POST(UrlChainRes+"/orphans", r.FindChainOrphans, r.ChainInjector, operator)
+	g.GET(UrlChainRes+"/db", r.GetChainDBBuckets, r.ChainInjector, viewer)
+	g.GET(UrlChainRes+"/db/:"+ParamBucket, r.DumpChainDB, r.ChainInjector, viewer)
+	g.GET(UrlChainRes+"/db/:"+ParamBucket+"/count", r.CountChainDB, r.ChainInjector, viewer)
+	route := g.GET(UrlChainRes+"/genesis", r.GetChainGenesis, r.ChainInjector, viewer)
 	if r.a != nil {
 		r.a.SetSkip(route, false)
 	}
-	g.GET(UrlChainRes+"/configure", r.GetChainConfig, r.ChainInjector)
-	g.POST(UrlChainRes+"/configure", r.ConfigureChain, r.ChainInjector)
+	g.GET(UrlChainRes+"/peers", r.GetChainPeers, r.ChainInjector, viewer)
+	g.POST(UrlChainRes+"/peers/add", r.AddChainPeers, r.ChainInjector, admin)
+	g.POST(UrlChainRes+"/peers/remove", r.RemoveChainPeers, r.ChainInjector, admin)
+	g.GET(UrlChainRes+"/configure", r.GetChainConfig, r.ChainInjector, viewer)
+	g.POST(UrlChainRes+"/configure", r.ConfigureChain, r.ChainInjector, admin)
 }
 
 func (r *Rest) ChainInjector(next echo.HandlerFunc) echo.HandlerFunc {
@@ -353,7 +453,27 @@ func (r *Rest) ImportChain(ctx echo.Context) error {
 	if err := ctx.Bind(param); err != nil {
 		return echo.ErrBadRequest
 	}
-	if err := r.n.ImportChain(c.CID(), param.DBPath, param.Height); err != nil {
+	if param.Snapshot != "" || param.FetchSnapshot {
+		if err := r.n.ImportChainSnapshot(c.CID(), param.Snapshot); err != nil {
+			return err
+		}
+	} else if param.Archive != "" {
+		if err := r.n.ImportChainArchive(c.CID(), param.Archive); err != nil {
+			return err
+		}
+	} else if err := r.n.ImportChain(c.CID(), param.DBPath, param.Height); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) ExportChain(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainExportParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if err := r.n.ExportChain(c.CID(), param.File, param.From, param.To, param.Receipts); err != nil {
 		return err
 	}
 	return ctx.String(http.StatusOK, "OK")
@@ -374,21 +494,158 @@ func (r *Rest) PruneChain(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) CheckChain(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainCheckParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if err := r.n.CheckChain(c.CID(), param.File, param.Repair); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) BackupChain(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
-	if name, err := r.n.BackupChain(c.CID()); err != nil {
+	param := &ChainBackupParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if name, err := r.n.BackupChain(c.CID(), param.Online, param.Base); err != nil {
 		return err
 	} else {
 		return ctx.String(http.StatusOK, name)
 	}
 }
 
+func (r *Rest) FindChainOrphans(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainOrphansParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	count, keys, err := r.n.FindChainOrphans(c.CID(), param.Limit)
+	if err != nil {
+		return err
+	}
+	v := &ChainOrphansView{
+		Count: count,
+		Keys:  make([]common.HexBytes, len(keys)),
+	}
+	for i, k := range keys {
+		v.Keys[i] = k
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
+func (r *Rest) GetChainDBBuckets(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, DBBuckets())
+}
+
+// queryHexBytes returns bytes of the hex encoded query parameter.
+func queryHexBytes(ctx echo.Context, name string) ([]byte, error) {
+	var bs common.HexBytes
+	if s := ctx.QueryParam(name); s != "" {
+		if err := bs.UnmarshalJSON([]byte(strconv.Quote(s))); err != nil {
+			return nil, echo.ErrBadRequest
+		}
+	}
+	return bs, nil
+}
+
+// queryChainDBRange returns the range of the keys for the query parameters.
+// The range for the prefix is used if it's specified.
+func queryChainDBRange(ctx echo.Context) ([]byte, []byte, error) {
+	prefix, err := queryHexBytes(ctx, "prefix")
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(prefix) > 0 {
+		start, limit := db.BytesPrefix(prefix)
+		return start, limit, nil
+	}
+	start, err := queryHexBytes(ctx, "start")
+	if err != nil {
+		return nil, nil, err
+	}
+	end, err := queryHexBytes(ctx, "end")
+	if err != nil {
+		return nil, nil, err
+	}
+	return start, end, nil
+}
+
+func (r *Rest) DumpChainDB(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	start, limit, err := queryChainDBRange(ctx)
+	if err != nil {
+		return err
+	}
+	var max int
+	if s := ctx.QueryParam("limit"); s != "" {
+		if max, err = strconv.Atoi(s); err != nil {
+			return echo.ErrBadRequest
+		}
+	}
+	entries, err := r.n.DumpChainDB(c.CID(), ctx.Param(ParamBucket), start, limit, max)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, entries)
+}
+
+func (r *Rest) CountChainDB(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	start, limit, err := queryChainDBRange(ctx)
+	if err != nil {
+		return err
+	}
+	count, err := r.n.CountChainDB(c.CID(), ctx.Param(ParamBucket), start, limit)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, &ChainDBCountView{Count: count})
+}
+
 func (r *Rest) GetChainGenesis(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	gsFile := path.Join(c.cfg.AbsBaseDir(), ChainGenesisZipFileName)
 	return ctx.Attachment(gsFile, fmt.Sprintf("%s_%s", c.Channel(), ChainGenesisZipFileName))
 }
 
+func (r *Rest) GetChainPeers(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	v := &ChainPeersView{
+		Static:  splitPeerList(c.cfg.StaticPeers),
+		Allowed: splitPeerList(c.cfg.AllowedPeers),
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
+func (r *Rest) AddChainPeers(ctx echo.Context) error {
+	return r.editChainPeers(ctx, false)
+}
+
+func (r *Rest) RemoveChainPeers(ctx echo.Context) error {
+	return r.editChainPeers(ctx, true)
+}
+
+func (r *Rest) editChainPeers(ctx echo.Context, remove bool) error {
+	c := ctx.Get("chain").(*Chain)
+	p := &ChainPeersParam{}
+	if err := ctx.Bind(p); err != nil {
+		return echo.ErrBadRequest
+	}
+	if err := r.n.EditChainPeers(c.CID(), p.Static, p.Allowed, remove); err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) GetChainConfig(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	return ctx.JSON(http.StatusOK, NewChainConfig(c.cfg))
@@ -407,9 +664,9 @@ func (r *Rest) ConfigureChain(ctx echo.Context) error {
 }
 
 func (r *Rest) RegisterSystemHandlers(g *echo.Group) {
-	g.GET("", r.GetSystem)
-	g.GET("/configure", r.GetSystemConfig)
-	g.POST("/configure", r.ConfigureSystem)
+	g.GET("", r.GetSystem, r.a.Authorizer(RoleViewer))
+	g.GET("/configure", r.GetSystemConfig, r.a.Authorizer(RoleViewer))
+	g.POST("/configure", r.ConfigureSystem, r.a.Authorizer(RoleAdmin))
 	r.RegistryBackupHandlers(g.Group("/backup"))
 	r.RegistryRestoreHandlers(g.Group("/restore"))
 }
@@ -450,7 +707,7 @@ func (r *Rest) ConfigureSystem(ctx echo.Context) error {
 }
 
 func (r *Rest) RegistryBackupHandlers(g *echo.Group) {
-	g.GET("", r.GetBackups)
+	g.GET("", r.GetBackups, r.a.Authorizer(RoleViewer))
 }
 
 func (r *Rest) GetBackups(ctx echo.Context) error {
@@ -462,9 +719,9 @@ func (r *Rest) GetBackups(ctx echo.Context) error {
 }
 
 func (r *Rest) RegistryRestoreHandlers(g *echo.Group) {
-	g.POST("", r.RestoreBackup)
-	g.GET("", r.GetRestore)
-	g.DELETE("", r.StopRestore)
+	g.POST("", r.RestoreBackup, r.a.Authorizer(RoleAdmin))
+	g.GET("", r.GetRestore, r.a.Authorizer(RoleViewer))
+	g.DELETE("", r.StopRestore, r.a.Authorizer(RoleAdmin))
 }
 
 func (r *Rest) GetRestore(ctx echo.Context) error {
@@ -501,13 +758,30 @@ func (r *Rest) Users(ctx echo.Context) error {
 }
 
 func (r *Rest) AddUser(ctx echo.Context) error {
-	param := struct {
-		Id string `json:"id"`
-	}{}
-	if err := ctx.Bind(&param); err != nil {
+	param := &UserParam{}
+	if err := ctx.Bind(param); err != nil {
 		return echo.ErrBadRequest
 	}
-	if err := r.a.AddUser(param.Id); err != nil {
+	role := RoleAdmin
+	if param.Role != "" {
+		var err error
+		if role, err = ParseRole(param.Role); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+	user := &User{Id: param.Id}
+	if len(param.Chains) == 0 {
+		user.Role = role
+	} else {
+		user.Chains = make(map[string]Role)
+		for _, cid := range param.Chains {
+			user.Chains[cid] = role
+		}
+	}
+	if err := r.a.AddUser(user); err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		if we, ok := err.(errors.Unwrapper); ok {
 			switch we.Unwrap() {
 			case ErrAlreadyExists:
@@ -19,7 +19,6 @@ package node
 import (
 	"archive/zip"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"os"
 	"path"
@@ -130,8 +129,34 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 		return err
 	}
 
+	// incremental backup is restored by replaying the base backups in
+	// the manifest followed by the backup itself.
+	var zrs []*zip.ReadCloser
+	defer func() {
+		if ret != nil {
+			for _, r := range zrs {
+				r.Close()
+			}
+		}
+	}()
+	if info.IsIncremental() {
+		dir := path.Dir(file)
+		if err := chain.VerifyBackupRefs(dir, info.Base); err != nil {
+			return err
+		}
+		for _, ref := range info.Base {
+			r, err := zip.OpenReader(path.Join(dir, ref.Name))
+			if err != nil {
+				return errors.IllegalArgumentError.Wrapf(err,
+					"ZipOpenFailure(backup=%s)", ref.Name)
+			}
+			zrs = append(zrs, r)
+		}
+	}
+	zrs = append(zrs, zr)
+
 	go func() {
-		if err := m._restore(node, zr, tmpDir, overwrite); err != nil {
+		if err := m._restore(node, zrs, tmpDir, overwrite); err != nil {
 			node.logger.Debugf("Restore failed err=%+v", err)
 			if errors.InterruptedError.Equals(err) {
 				m._setState(RestoreNone, nil)
@@ -147,7 +172,10 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 	m.overwrite = overwrite
 	m.state = RestoreStarted
 	m.current = 0
-	m.total = len(zr.File)
+	m.total = 0
+	for _, r := range zrs {
+		m.total += len(r.File)
+	}
 	return nil
 }
 
@@ -202,53 +230,39 @@ func (m *RestoreManager) _setStateInLock(s RestoreState, e error) {
 	}
 }
 
-func zipExtract(file *zip.File, tmpDir string) (ret error) {
-	rc, err := file.Open()
-	if err != nil {
-		return err
-	}
-	defer rc.Close()
-
-	target := path.Join(tmpDir, file.Name)
-	mode := file.Mode()
-	if mode.IsDir() {
-		return os.MkdirAll(target, mode.Perm())
-	}
-
-	if !file.Mode().IsRegular() {
-		return nil
-	}
-
-	if err := os.MkdirAll(path.Dir(target), 0755); err != nil {
-		return err
-	}
-
-	fd, err := os.OpenFile(target,
-		os.O_CREATE|os.O_EXCL|os.O_RDWR|os.O_TRUNC, mode.Perm())
-	if err != nil {
-		return err
-	}
-	defer fd.Close()
-
-	_, err = io.Copy(fd, rc)
-
-	return err
-}
-
-func (m *RestoreManager) _restore(node *Node, zr *zip.ReadCloser, tmpDir string, overwrite bool) (ret error) {
+func (m *RestoreManager) _restore(node *Node, zrs []*zip.ReadCloser, tmpDir string, overwrite bool) (ret error) {
 	defer func() {
 		if ret != nil {
 			os.RemoveAll(tmpDir)
 		}
 	}()
-	defer zr.Close()
+	defer func() {
+		for _, zr := range zrs {
+			zr.Close()
+		}
+	}()
 
-	for idx, file := range zr.File {
-		if err := zipExtract(file, tmpDir); err != nil {
-			return err
+	idx := 0
+	for i, zr := range zrs {
+		for _, file := range zr.File {
+			if file.Name != chain.BackupIncrementFile {
+				if err := chain.ExtractZipFile(file, tmpDir, i > 0); err != nil {
+					return err
+				}
+			}
+			if err := m._onRestored(idx); err != nil {
+				return err
+			}
+			idx += 1
 		}
-		if err := m._onRestored(idx); err != nil {
-			return err
+		if i > 0 {
+			cfg, err := node.loadChainConfig(tmpDir)
+			if err != nil {
+				return err
+			}
+			if err := chain.ApplyBackupIncrement(&zr.Reader, tmpDir, cfg); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -170,12 +170,14 @@ func ErrorHandler(re *Error, c echo.Context) {
 		}
 		status = http.StatusBadRequest
 	} else {
-		req := c.Get("request").(*Request)
 		res = &ErrorResponse{
-			ID:      req.ID,
 			Version: Version,
 			Error:   re,
 		}
+		// it's not available for failures of batch requests
+		if req, ok := c.Get("request").(*Request); ok {
+			res.ID = req.ID
+		}
 		switch re.Code {
 		case ErrorCodeInvalidRequest, ErrorCodeInvalidParams:
 			status = http.StatusBadRequest
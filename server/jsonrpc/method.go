@@ -1,10 +1,15 @@
 package jsonrpc
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"sync"
 
 	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
 )
 
 type Handler func(ctx *Context, params *Params) (result interface{}, err error)
@@ -47,17 +52,25 @@ func (mr *MethodRepository) TakeMethod(r *Request) (Handler, error) {
 
 func (mr *MethodRepository) InvokeMethod(c echo.Context, r *Request) (interface{}, error) {
 	h := c.Get("method").(Handler)
+	return mr.invoke(c, h, r)
+}
 
+func (mr *MethodRepository) invoke(c echo.Context, h Handler, r *Request) (interface{}, error) {
 	ctx := NewContext(c)
 	param := Params{
 		rawMessage: r.Params,
 		validator:  c.Echo().Validator,
 	}
 
-	return h(ctx, &param)
+	result, err := h(ctx, &param)
+	metric.OnJsonRpcCall(metricContextOf(c), r.Method, err)
+	return result, err
 }
 
 func (mr *MethodRepository) Handle(c echo.Context) (err error) {
+	if reqs, ok := c.Get("batch").([]json.RawMessage); ok {
+		return mr.handleBatch(c, reqs)
+	}
 	r := c.Get("request").(*Request)
 
 	result, err := mr.InvokeMethod(c, r)
@@ -73,3 +86,59 @@ func (mr *MethodRepository) Handle(c echo.Context) (err error) {
 
 	return c.JSON(http.StatusOK, res)
 }
+
+// handleBatch handles requests in the batch in order, and responds with
+// an array of responses. Failures are returned as error objects of
+// the elements.
+func (mr *MethodRepository) handleBatch(c echo.Context, reqs []json.RawMessage) error {
+	metric.OnJsonRpcBatch(metricContextOf(c), len(reqs))
+	res := make([]interface{}, len(reqs))
+	for i, raw := range reqs {
+		res[i] = mr.handleBatchElement(c, raw)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (mr *MethodRepository) handleBatchElement(c echo.Context, raw json.RawMessage) interface{} {
+	r := new(Request)
+	if err := json.Unmarshal(raw, r); err != nil {
+		return &ErrorResponse{
+			Version: Version,
+			Error:   ErrInvalidRequest(),
+		}
+	}
+	if err := c.Validate(r); err != nil {
+		return &ErrorResponse{
+			ID:      r.ID,
+			Version: Version,
+			Error:   ErrInvalidRequest(),
+		}
+	}
+	h, err := mr.TakeMethod(r)
+	if err == nil {
+		var result interface{}
+		if result, err = mr.invoke(c, h, r); err == nil {
+			return &Response{
+				ID:      r.ID,
+				Version: Version,
+				Result:  result,
+			}
+		}
+	}
+	je, ok := err.(*Error)
+	if !ok {
+		je = ErrInternal(err.Error())
+	}
+	return &ErrorResponse{
+		ID:      r.ID,
+		Version: Version,
+		Error:   je,
+	}
+}
+
+func metricContextOf(c echo.Context) context.Context {
+	if chain, ok := c.Get("chain").(module.Chain); ok && chain != nil {
+		return metric.GetMetricContextByCID(chain.CID())
+	}
+	return metric.DefaultMetricContext()
+}
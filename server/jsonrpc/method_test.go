@@ -3,8 +3,11 @@ package jsonrpc
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -54,3 +57,51 @@ func hello(ctx *Context, params *Params) (result interface{}, err error) {
 	}
 	return "hello, " + param.Name, nil
 }
+
+func TestMethodRepository_Batch(t *testing.T) {
+	mr := NewMethodRepository()
+	mr.RegisterMethod("hello", hello)
+
+	e := echo.New()
+	e.Validator = NewValidator()
+
+	message := []byte(`[
+		{"id":1,"jsonrpc":"2.0","method":"hello","params":{"name":"icon"}},
+		{"id":2,"jsonrpc":"2.0","method":"unknown"},
+		{"id":3,"jsonrpc":"2.0","method":"hello","params":{"invalid":"icon"}},
+		{"id":4,"method":"hello"},
+		1
+	]`)
+	var reqs []json.RawMessage
+	assert.NoError(t, json.Unmarshal(message, &reqs))
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+	c.Set("batch", reqs)
+	assert.NoError(t, mr.Handle(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var res []struct {
+		ID     interface{} `json:"id"`
+		Result interface{} `json:"result"`
+		Error  *Error      `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Len(t, res, len(reqs))
+
+	assert.EqualValues(t, 1, res[0].ID)
+	assert.Equal(t, "hello, icon", res[0].Result)
+	assert.Nil(t, res[0].Error)
+
+	assert.EqualValues(t, 2, res[1].ID)
+	assert.Equal(t, ErrorCodeMethodNotFound, res[1].Error.Code)
+
+	assert.EqualValues(t, 3, res[2].ID)
+	assert.Equal(t, ErrorCodeInvalidParams, res[2].Error.Code)
+
+	assert.EqualValues(t, 4, res[3].ID)
+	assert.Equal(t, ErrorCodeInvalidRequest, res[3].Error.Code)
+
+	assert.Nil(t, res[4].ID)
+	assert.Equal(t, ErrorCodeInvalidRequest, res[4].Error.Code)
+}
@@ -0,0 +1,39 @@
+package metric
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	msJsonRpcCall    = stats.Int64("jsonrpc_call", "JSON-RPC Method Call", stats.UnitDimensionless)
+	msJsonRpcFailure = stats.Int64("jsonrpc_failure", "JSON-RPC Method Failure", stats.UnitDimensionless)
+	msJsonRpcBatch   = stats.Int64("jsonrpc_batch", "JSON-RPC Batch Request", stats.UnitDimensionless)
+	mkJsonRpcMethod  = NewMetricKey("method")
+	jsonRpcMks       = []tag.Key{mkJsonRpcMethod}
+)
+
+func RegisterJsonRpc() {
+	RegisterMetricView(msJsonRpcCall, view.Count(), jsonRpcMks)
+	RegisterMetricView(msJsonRpcFailure, view.Count(), jsonRpcMks)
+	RegisterMetricView(msJsonRpcBatch, view.Count(), nil)
+	RegisterMetricView(msJsonRpcBatch, view.Sum(), nil)
+}
+
+// OnJsonRpcCall records a call of the method. It should be called only for
+// registered methods.
+func OnJsonRpcCall(ctx context.Context, method string, err error) {
+	mctx := GetMetricContext(ctx, &mkJsonRpcMethod, method)
+	stats.Record(mctx, msJsonRpcCall.M(1))
+	if err != nil {
+		stats.Record(mctx, msJsonRpcFailure.M(1))
+	}
+}
+
+// OnJsonRpcBatch records a batch request with the number of requests in it.
+func OnJsonRpcBatch(ctx context.Context, size int) {
+	stats.Record(ctx, msJsonRpcBatch.M(int64(size)))
+}
@@ -170,6 +170,7 @@ func PrometheusExporter() *prometheus.Exporter {
 	RegisterConsensus()
 	RegisterNetwork()
 	RegisterTransaction()
+	RegisterJsonRpc()
 	return pe
 }
 
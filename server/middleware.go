@@ -2,6 +2,7 @@ package server
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -20,6 +21,11 @@ func JsonRpc(mr *jsonrpc.MethodRepository) echo.MiddlewareFunc {
 			if strings.HasPrefix(ctype, echo.MIMETextPlain) {
 				c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			}
+			if isBatch, err := checkBatch(c); err != nil {
+				return err
+			} else if isBatch {
+				return next(c)
+			}
 			r := new(jsonrpc.Request)
 			if err := c.Bind(r); err != nil {
 				return jsonrpc.ErrParse()
@@ -38,6 +44,36 @@ func JsonRpc(mr *jsonrpc.MethodRepository) echo.MiddlewareFunc {
 	}
 }
 
+// checkBatch checks whether the request is a batch request. For a batch
+// request, it sets raw messages of the requests to "batch" of the context.
+func checkBatch(c echo.Context) (bool, error) {
+	r := c.Request()
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return false, jsonrpc.ErrParse()
+	}
+	r.ContentLength = int64(len(b))
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	b = bytes.TrimLeft(b, " \t\r\n")
+	if len(b) == 0 || b[0] != '[' {
+		return false, nil
+	}
+	var reqs []json.RawMessage
+	if err := json.Unmarshal(b, &reqs); err != nil {
+		return false, jsonrpc.ErrParse()
+	}
+	if len(reqs) == 0 {
+		return false, jsonrpc.ErrInvalidRequest()
+	}
+	if limit, _ := c.Get("batchLimit").(int); len(reqs) > limit {
+		return false, jsonrpc.ErrInvalidRequest(
+			fmt.Sprintf("TooManyRequests(size=%d,max=%d)", len(reqs), limit))
+	}
+	c.Set("batch", reqs)
+	return true, nil
+}
+
 func ChainInjector(srv *Manager) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
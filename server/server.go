@@ -21,6 +21,8 @@ const (
 	flagENABLE  int32 = 1
 	flagDISABLE int32 = 0
 	UrlAdmin          = "/admin"
+
+	DefaultJsonrpcBatchLimit = 10
 )
 
 type Manager struct {
@@ -33,6 +35,8 @@ type Manager struct {
 	jsonrpcDefaultChannel string
 	jsonrpcMessageDump    int32
 	jsonrpcIncludeDebug   int32
+	jsonrpcBatchLimit     int32
+	txRateLimiter         *v3.TxRateLimiter
 	logger                log.Logger
 }
 
@@ -63,10 +67,12 @@ func NewManager(addr string,
 		wssm:                  newWSSessionManager(logger),
 		mtx:                   sync.RWMutex{},
 		jsonrpcDefaultChannel: jsonrpcDefaultChannel,
+		txRateLimiter:         v3.NewTxRateLimiter(),
 		logger:                logger,
 	}
 	m.SetMessageDump(jsonrpcDump)
 	m.SetIncludeDebug(jsonrpcIncludeDebug)
+	m.SetBatchLimit(0)
 	return m
 }
 
@@ -147,6 +153,19 @@ func (srv *Manager) IncludeDebug() bool {
 	return atomicLoad(&srv.jsonrpcIncludeDebug)
 }
 
+// SetBatchLimit sets the max number of requests in a batch request.
+// DefaultJsonrpcBatchLimit is used if it's not positive.
+func (srv *Manager) SetBatchLimit(limit int) {
+	if limit <= 0 {
+		limit = DefaultJsonrpcBatchLimit
+	}
+	atomic.StoreInt32(&srv.jsonrpcBatchLimit, int32(limit))
+}
+
+func (srv *Manager) BatchLimit() int {
+	return int(atomic.LoadInt32(&srv.jsonrpcBatchLimit))
+}
+
 func (srv *Manager) Start() error {
 	srv.logger.Infoln("starting the server")
 	// middleware
@@ -171,6 +190,8 @@ func (srv *Manager) Start() error {
 	g.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
 			ctx.Set("includeDebug", srv.IncludeDebug())
+			ctx.Set("batchLimit", srv.BatchLimit())
+			ctx.Set("txRateLimiter", srv.txRateLimiter)
 			return next(ctx)
 		}
 	})
@@ -189,6 +210,7 @@ func (srv *Manager) Start() error {
 	// websocket
 	srv.e.GET("/api/v3/:channel/block", srv.wssm.RunBlockSession, ChainInjector(srv))
 	srv.e.GET("/api/v3/:channel/event", srv.wssm.RunEventSession, ChainInjector(srv))
+	srv.e.GET("/api/v3/:channel/subscription", srv.wssm.RunSubscriptionSession, ChainInjector(srv))
 
 	// metric
 	srv.e.GET("/metrics", echo.WrapHandler(metric.PrometheusExporter()))
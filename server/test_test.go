@@ -0,0 +1,137 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+	"github.com/icon-project/goloop/test"
+)
+
+type testBlock struct {
+	test.BlockBase
+	height int64
+	bloom  txresult.LogsBloom
+	votes  module.CommitVoteSet
+}
+
+func (b *testBlock) ID() []byte {
+	return []byte(fmt.Sprintf("block%d", b.height))
+}
+
+func (b *testBlock) Height() int64 {
+	return b.height
+}
+
+func (b *testBlock) Result() []byte {
+	return b.ID()
+}
+
+func (b *testBlock) LogsBloom() module.LogsBloom {
+	return &b.bloom
+}
+
+func (b *testBlock) PrevID() []byte {
+	return []byte(fmt.Sprintf("block%d", b.height-1))
+}
+
+func (b *testBlock) NextValidatorsHash() []byte {
+	return []byte(fmt.Sprintf("validators%d", b.height))
+}
+
+func (b *testBlock) Votes() module.CommitVoteSet {
+	return b.votes
+}
+
+type testBlockManager struct {
+	test.BlockManagerBase
+	blocks []*testBlock
+}
+
+// WaitForBlock returns the block if it exists. Otherwise, the channel never
+// delivers a block.
+func (bm *testBlockManager) WaitForBlock(height int64) (<-chan module.Block, error) {
+	ch := make(chan module.Block, 1)
+	if height < int64(len(bm.blocks)) {
+		ch <- bm.blocks[height]
+	}
+	return ch, nil
+}
+
+func (bm *testBlockManager) GetBlock(id []byte) (module.Block, error) {
+	for _, blk := range bm.blocks {
+		if bytes.Equal(blk.ID(), id) {
+			return blk, nil
+		}
+	}
+	return nil, errors.ErrNotFound
+}
+
+type testServiceManager struct {
+	test.ServiceManagerBase
+	receipts map[string][]txresult.Receipt
+}
+
+func (sm *testServiceManager) ReceiptListFromResult(result []byte, g module.TransactionGroup) (module.ReceiptList, error) {
+	var rs []txresult.Receipt
+	if g == module.TransactionGroupNormal {
+		rs = sm.receipts[string(result)]
+	}
+	return txresult.NewReceiptListFromSlice(db.NewMapDB(), rs), nil
+}
+
+type testGenesisStorage struct {
+	module.GenesisStorage
+}
+
+func (gs *testGenesisStorage) Height() int64 {
+	return 0
+}
+
+type testCommitVoteSet struct {
+	test.CommitVoteSetBase
+	bytes []byte
+}
+
+func (vs *testCommitVoteSet) Bytes() []byte {
+	return vs.bytes
+}
+
+// testConsensus has votes only for the heights in the map.
+type testConsensus struct {
+	module.Consensus
+	votes map[int64]module.CommitVoteSet
+}
+
+func (c *testConsensus) GetVotesByHeight(height int64) (module.CommitVoteSet, error) {
+	if vs, ok := c.votes[height]; ok {
+		return vs, nil
+	}
+	return nil, errors.ErrNotFound
+}
+
+type testChain struct {
+	test.ChainBase
+	bm *testBlockManager
+	sm *testServiceManager
+	cs *testConsensus
+}
+
+func (c *testChain) BlockManager() module.BlockManager {
+	return c.bm
+}
+
+func (c *testChain) ServiceManager() module.ServiceManager {
+	return c.sm
+}
+
+func (c *testChain) GenesisStorage() module.GenesisStorage {
+	return &testGenesisStorage{}
+}
+
+func (c *testChain) Consensus() module.Consensus {
+	return c.cs
+}
@@ -17,6 +17,8 @@ import (
 	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/service"
 	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/trace"
+	"github.com/icon-project/goloop/service/txresult"
 )
 
 const (
@@ -38,10 +40,15 @@ func MethodRepository() *jsonrpc.MethodRepository {
 	mr.RegisterMethod("icx_sendTransaction", sendTransaction)
 	mr.RegisterMethod("icx_sendTransactionAndWait", sendTransactionAndWait)
 	mr.RegisterMethod("icx_waitTransactionResult", waitTransactionResult)
+	mr.RegisterMethod("icx_getTransactionsByAddress", getTransactionsByAddress)
+	mr.RegisterMethod("icx_getTransactionCountByAddress", getTransactionCountByAddress)
+	mr.RegisterMethod("icx_getLogs", getLogs)
 
 	mr.RegisterMethod("icx_getDataByHash", getDataByHash)
 	mr.RegisterMethod("icx_getBlockHeaderByHeight", getBlockHeaderByHeight)
 	mr.RegisterMethod("icx_getVotesByHeight", getVotesByHeight)
+	mr.RegisterMethod("icx_getBlockProof", getBlockProof)
+	mr.RegisterMethod("icx_getEvidence", getEvidence)
 	mr.RegisterMethod("icx_getProofForResult", getProofForResult)
 	mr.RegisterMethod("icx_getProofForEvents", getProofForEvents)
 
@@ -193,7 +200,10 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
 
-	block, err := bm.GetLastBlock()
+	block, err := getBlockForState(chain, bm, param.Height, debug)
+	if err != nil {
+		return nil, err
+	}
 	result, err := sm.Call(block.Result(), block.NextValidators(), params.RawMessage(), block)
 	if err != nil {
 		if service.InvalidQueryError.Equals(err) {
@@ -227,9 +237,9 @@ func getBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, erro
 	}
 
 	var balance common.HexInt
-	block, err := bm.GetLastBlock()
+	block, err := getBlockForState(chain, bm, param.Height, debug)
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, err
 	}
 	b, err := sm.GetBalance(block.Result(), param.Address.Address())
 	if err != nil {
@@ -254,9 +264,9 @@ func getScoreApi(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, err
 	if bm == nil || sm == nil {
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
-	b, err := bm.GetLastBlock()
+	b, err := getBlockForState(chain, bm, param.Height, debug)
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, err
 	}
 	info, err := sm.GetAPIInfo(b.Result(), param.Address.Address())
 	if service.NoActiveContractError.Equals(err) {
@@ -272,8 +282,14 @@ func getScoreApi(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, err
 	}
 }
 
-func getTotalSupply(ctx *jsonrpc.Context, _ *jsonrpc.Params) (interface{}, error) {
+func getTotalSupply(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
+	var param TotalSupplyParam
+	if !params.IsEmpty() {
+		if err := params.Convert(&param); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+	}
 	chain, err := ctx.Chain()
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
@@ -284,9 +300,9 @@ func getTotalSupply(ctx *jsonrpc.Context, _ *jsonrpc.Params) (interface{}, error
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
 
-	b, err := bm.GetLastBlock()
+	b, err := getBlockForState(chain, bm, param.Height, debug)
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, err
 	}
 
 	var tsValue common.HexInt
@@ -299,6 +315,41 @@ func getTotalSupply(ctx *jsonrpc.Context, _ *jsonrpc.Params) (interface{}, error
 	return &tsValue, nil
 }
 
+// getBlockForState returns the block whose result has the world state for
+// the height. It returns the last block if the height is not specified.
+func getBlockForState(chain module.Chain, bm module.BlockManager, height jsonrpc.HexInt, debug bool) (module.Block, error) {
+	if height == "" {
+		block, err := bm.GetLastBlock()
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		return block, nil
+	}
+	h, err := height.ParseInt(64)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	block, err := bm.GetBlockByHeight(h)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	// Nodes of the state may be removed partially while the pruner removes
+	// it, so the root of the state is not enough to check it.
+	if pruned, err := chain.PrunedStateHeight(); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	} else if h < pruned {
+		return nil, jsonrpc.ErrorCodeNotFound.Errorf("StatePruned(height=%d,pruned=%d)", h, pruned)
+	}
+	if ok, err := service.HasWorldState(chain.Database(), block.Result()); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	} else if !ok {
+		return nil, jsonrpc.ErrorCodeNotFound.Errorf("StatePruned(height=%d)", h)
+	}
+	return block, nil
+}
+
 func getTransactionResult(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
@@ -389,6 +440,237 @@ func getTransactionByHash(ctx *jsonrpc.Context, params *jsonrpc.Params) (interfa
 	return result, nil
 }
 
+func getTransactionsByAddress(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param AccountTransactionsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	var start, limit int64
+	if param.Start != "" {
+		if v, err := param.Start.ParseInt(64); err != nil || v < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidStart(%s)", param.Start)
+		} else {
+			start = v
+		}
+	}
+	if param.Limit != "" {
+		if v, err := param.Limit.ParseInt(32); err != nil || v < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidLimit(%s)", param.Limit)
+		} else {
+			limit = v
+		}
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	if bm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	ids, err := bm.GetTransactionsByAddress(param.Address.Address(), start, int(limit))
+	if errors.UnsupportedError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	result := make([]interface{}, len(ids))
+	for i, id := range ids {
+		result[i] = "0x" + hex.EncodeToString(id)
+	}
+	return result, nil
+}
+
+func getTransactionCountByAddress(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param AddressParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	if param.Height != "" {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("HeightNotSupported")
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	if bm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	cnt, err := bm.GetTransactionCountByAddress(param.Address.Address())
+	if errors.UnsupportedError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return "0x" + strconv.FormatInt(cnt, 16), nil
+}
+
+// getLogs returns events matched with the filter in the range of blocks.
+// Like the event websocket, the height and the hash of the block including
+// the result of the transaction are used for the event.
+func getLogs(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param LogsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	if err := param.Compile(); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	from, err := param.FromHeight.ParseInt(64)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	to := last.Height()
+	if param.ToHeight != "" {
+		if v, err := param.ToHeight.ParseInt(64); err != nil || v > to {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidToHeight(%s)", param.ToHeight)
+		} else {
+			to = v
+		}
+	}
+	if gh := chain.GenesisStorage().Height(); from < gh || from > to {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"InvalidRange(from=%d,to=%d)", from, to)
+	}
+	if mr := chain.MaxLogsRange(); to-from+1 > int64(mr) {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"TooLargeRange(from=%d,to=%d,max=%d)", from, to, mr)
+	}
+	maxResult := chain.MaxLogsResult()
+
+	getBlock := func(height int64) (module.Block, error) {
+		blk, err := bm.GetBlockByHeight(height)
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		} else if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		return blk, nil
+	}
+	result := make([]interface{}, 0)
+	appendLogs := func(blk module.Block, txs module.TransactionList, rblk module.Block, g module.TransactionGroup) error {
+		if !rblk.LogsBloom().Contain(param.LogsBloom()) {
+			return nil
+		}
+		rl, err := sm.ReceiptListFromResult(rblk.Result(), g)
+		if err != nil {
+			return jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		group := "normal"
+		if g == module.TransactionGroupPatch {
+			group = "patch"
+		}
+		for it := txs.Iterator(); it.Has(); it.Next() {
+			tx, index, err := it.Get()
+			if err != nil {
+				return jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+			}
+			r, err := rl.Get(index)
+			if err != nil {
+				return jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+			}
+			if es, ok := param.Match(r); ok {
+				if len(result) >= maxResult {
+					return jsonrpc.ErrorLackOfResource.Errorf(
+						"TooManyResults(max=%d)", maxResult)
+				}
+				events, err := eventsOf(r, es)
+				if err != nil {
+					return jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+				}
+				result = append(result, map[string]interface{}{
+					"blockHeight": "0x" + strconv.FormatInt(blk.Height(), 16),
+					"blockHash":   "0x" + hex.EncodeToString(blk.ID()),
+					"txGroup":     group,
+					"txIndex":     "0x" + strconv.FormatInt(int64(index), 16),
+					"txHash":      "0x" + hex.EncodeToString(tx.ID()),
+					"events":      events,
+				})
+			}
+		}
+		return nil
+	}
+
+	// Receipts of patch transactions are in the result of the block
+	// including them, but receipts of normal transactions are in the result
+	// of the next block. So normal transactions of the last block are not
+	// available yet.
+	blk, err := getBlock(from)
+	if err != nil {
+		return nil, err
+	}
+	for h := from; h <= to; h++ {
+		var next module.Block
+		if h < last.Height() {
+			if next, err = getBlock(h + 1); err != nil {
+				return nil, err
+			}
+		}
+		if err := appendLogs(blk, blk.PatchTransactions(), blk, module.TransactionGroupPatch); err != nil {
+			return nil, err
+		}
+		if next != nil {
+			if err := appendLogs(blk, blk.NormalTransactions(), next, module.TransactionGroupNormal); err != nil {
+				return nil, err
+			}
+		}
+		blk = next
+	}
+	return result, nil
+}
+
+// eventsOf returns the events of the receipt at the indexes with the index
+// of each event.
+func eventsOf(r module.Receipt, indexes []common.HexInt32) ([]interface{}, error) {
+	events := make([]interface{}, 0, len(indexes))
+	for it, idx := r.EventLogIterator(), int32(0); it.Has() && len(events) < len(indexes); _, idx = it.Next(), idx+1 {
+		if indexes[len(events)].Value != idx {
+			continue
+		}
+		el, err := it.Get()
+		if err != nil {
+			return nil, err
+		}
+		event, err := txresult.EventLogToJSON(el, module.JSONVersion3)
+		if err != nil {
+			return nil, err
+		}
+		event["index"] = indexes[len(events)]
+		events = append(events, event)
+	}
+	return events, nil
+}
+
 func sendTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
@@ -402,11 +684,16 @@ func sendTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
 	}
 
+	if err := checkTxRateLimit(ctx, chain); err != nil {
+		return nil, err
+	}
+
 	sm := chain.ServiceManager()
 
 	hash, err := sm.SendTransaction(params.RawMessage())
 	if err != nil {
-		if service.TransactionPoolOverflowError.Equals(err) {
+		if service.TransactionPoolOverflowError.Equals(err) ||
+			service.TooManyTransactionsError.Equals(err) {
 			return nil, jsonrpc.ErrorCodeTxPoolOverflow.Wrap(err, debug)
 		}
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
@@ -514,6 +801,102 @@ func getVotesByHeight(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}
 	return votes.Bytes(), nil
 }
 
+func getEvidence(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param EvidenceParam
+	if !params.IsEmpty() {
+		if err := params.Convert(&param); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+	}
+	height := int64(-1)
+	if param.Height != "" {
+		if v, err := param.Height.ParseInt(64); err != nil || v < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidHeight(%s)", param.Height)
+		} else {
+			height = v
+		}
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	cs := chain.Consensus()
+	if cs == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	evidences, err := cs.GetEvidences(height)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	result := make([]interface{}, len(evidences))
+	for i, e := range evidences {
+		if result[i], err = e.ToJSON(module.JSONVersion3); err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+	}
+	return result, nil
+}
+
+func getBlockProof(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param BlockProofParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	height, err := param.Height.ParseInt(64)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	atHeight, err := param.AtHeight.ParseInt(64)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	if height < 0 || height > atHeight {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"InvalidHeight(height=%d,atHeight=%d)", height, atHeight)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	if bm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	block, err := bm.GetBlockByHeight(height)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := block.MarshalHeader(buf); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	idx, witness, err := bm.GetBlockProof(height, atHeight)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	return map[string]interface{}{
+		"header":  buf.Bytes(),
+		"index":   &common.HexInt64{Value: idx},
+		"witness": witness,
+	}, nil
+}
+
 func getProofForResult(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
@@ -686,9 +1069,14 @@ func sendTransactionAndWait(ctx *jsonrpc.Context, params *jsonrpc.Params) (inter
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
 
+	if err := checkTxRateLimit(ctx, chain); err != nil {
+		return nil, err
+	}
+
 	hash, fc, err := bm.SendTransactionAndWait(params.RawMessage())
 	if err != nil {
-		if service.TransactionPoolOverflowError.Equals(err) {
+		if service.TransactionPoolOverflowError.Equals(err) ||
+			service.TooManyTransactionsError.Equals(err) {
 			return nil, jsonrpc.ErrorCodeTxPoolOverflow.Wrap(err, debug)
 		}
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
@@ -804,10 +1192,40 @@ func DebugMethodRepository() *jsonrpc.MethodRepository {
 
 	mr.RegisterMethod("debug_getTrace", getTrace)
 	mr.RegisterMethod("debug_estimateStep", estimateStep)
+	mr.RegisterMethod("debug_traceCall", traceCall)
 
 	return mr
 }
 
+const (
+	TraceModeLogs     = "logs"
+	TraceModeCallTree = "callTree"
+)
+
+type resultTraceCallback interface {
+	module.TraceCallback
+	done() <-chan interface{}
+	result() interface{}
+}
+
+func newTraceCallback(mode string) (resultTraceCallback, error) {
+	switch mode {
+	case "", TraceModeLogs:
+		return &traceCallback{
+			logs:    make([]interface{}, 0, 100),
+			channel: make(chan interface{}, 10),
+		}, nil
+	case TraceModeCallTree:
+		return &callTreeCallback{
+			traceCallback: traceCallback{
+				channel: make(chan interface{}, 10),
+			},
+		}, nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("InvalidTraceMode(mode=%s)", mode)
+	}
+}
+
 type traceCallback struct {
 	lock    sync.Mutex
 	logs    []interface{}
@@ -837,6 +1255,10 @@ func (t *traceCallback) OnEnd(e error) {
 	close(t.channel)
 }
 
+func (t *traceCallback) done() <-chan interface{} {
+	return t.channel
+}
+
 func (t *traceCallback) result() interface{} {
 	t.lock.Lock()
 	defer t.lock.Unlock()
@@ -857,13 +1279,40 @@ func (t *traceCallback) result() interface{} {
 	return result
 }
 
+// callTreeCallback records frames of the transaction. Logs are recorded only
+// if logs of traceCallback is initialized.
+type callTreeCallback struct {
+	traceCallback
+	trace.CallTree
+}
+
+func (t *callTreeCallback) OnLog(level module.TraceLevel, msg string) {
+	// logs are kept only if it's requested
+	if t.logs != nil {
+		t.traceCallback.OnLog(level, msg)
+	}
+}
+
+func (t *callTreeCallback) result() interface{} {
+	result := t.traceCallback.result().(map[string]interface{})
+	if t.logs == nil {
+		delete(result, "logs")
+	}
+	result["calls"] = t.Calls()
+	return result
+}
+
 func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
-	var param TransactionHashParam
+	var param TraceParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
+	cb, err := newTraceCallback(param.Mode)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
 
 	chain, err := ctx.Chain()
 	if err != nil {
@@ -911,10 +1360,6 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 	}
 	tr2 = sm.PatchTransition(tr2, nblk.PatchTransactions(), nblk)
 
-	cb := &traceCallback{
-		logs:    make([]interface{}, 0, 100),
-		channel: make(chan interface{}, 10),
-	}
 	canceller, err := tr2.ExecuteForTrace(module.TraceInfo{
 		Group:    txInfo.Group(),
 		Index:    txInfo.Index(),
@@ -931,13 +1376,30 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 			canceller()
 			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
 				"Not enough time to get result of %x", param.Hash.Bytes())
-		case <-cb.channel:
+		case <-cb.done():
 			return cb.result(), nil
 		}
 	}
 	return nil, jsonrpc.ErrorCodeSystem.New("Unknown error on channel")
 }
 
+// lastBlockForEstimate returns the last block and the information of the
+// next block for executing a transaction on the state of the last block.
+func lastBlockForEstimate(bm module.BlockManager) (module.Block, module.BlockInfo, error) {
+	blk, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// new block information based on the last
+	oldTS := blk.Timestamp()
+	newTS := common.UnixMicroFromTime(time.Now())
+	if newTS <= oldTS {
+		newTS = oldTS + 1
+	}
+	return blk, common.NewBlockInfo(blk.Height()+1, newTS), nil
+}
+
 func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
@@ -957,20 +1419,11 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 		return nil, jsonrpc.ErrorCodeServer.New("ChannelStopped")
 	}
 
-	// get last block
-	blk, err := bm.GetLastBlock()
+	blk, bi, err := lastBlockForEstimate(bm)
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
 	}
 
-	// new block information based on the last
-	oldTS := blk.Timestamp()
-	newTS := common.UnixMicroFromTime(time.Now())
-	if newTS <= oldTS {
-		newTS = oldTS + 1
-	}
-	bi := common.NewBlockInfo(blk.Height()+1, newTS)
-
 	// execute transaction
 	rct, err := sm.ExecuteTransaction(
 		blk.Result(),
@@ -988,3 +1441,66 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 	steps.Set(rct.StepUsed())
 	return steps, nil
 }
+
+func traceCall(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	var param TransactionParamForEstimate
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("ChannelStopped")
+	}
+
+	blk, bi, err := lastBlockForEstimate(bm)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	cb := &callTreeCallback{
+		traceCallback: traceCallback{
+			logs:    make([]interface{}, 0, 100),
+			channel: make(chan interface{}, 1),
+		},
+	}
+	receipt, err := sm.ExecuteTransactionForTrace(
+		blk.Result(),
+		blk.NextValidators().Hash(),
+		params.RawMessage(),
+		bi,
+		cb,
+	)
+	if err != nil {
+		if scoreresult.InvalidParameterError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	res, err := receipt.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	result := res.(map[string]interface{})
+	calls := cb.Calls()
+	if receipt.Status() != module.StatusSuccess && len(calls) > 0 && calls[0].Failure != nil {
+		// use the message of the failure instead of the name of the status
+		result["failure"] = map[string]interface{}{
+			"code":    common.HexUint16{Value: uint16(receipt.Status())},
+			"message": calls[0].Failure.Message,
+		}
+	}
+	result["logs"] = cb.logs
+	result["calls"] = calls
+	result["accounts"] = cb.Accounts()
+	return result, nil
+}
@@ -0,0 +1,341 @@
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service/txresult"
+	"github.com/icon-project/goloop/test"
+)
+
+type testTransaction struct {
+	test.TransactionBase
+	id []byte
+}
+
+func (tx *testTransaction) ID() []byte {
+	return tx.id
+}
+
+type testTransactionList struct {
+	test.TransactionListBase
+	txs []module.Transaction
+}
+
+func (l *testTransactionList) Get(i int) (module.Transaction, error) {
+	if i < 0 || i >= len(l.txs) {
+		return nil, errors.ErrNotFound
+	}
+	return l.txs[i], nil
+}
+
+func (l *testTransactionList) Iterator() module.TransactionIterator {
+	return &testTransactionIterator{list: l}
+}
+
+type testTransactionIterator struct {
+	list *testTransactionList
+	idx  int
+}
+
+func (it *testTransactionIterator) Has() bool {
+	return it.idx < len(it.list.txs)
+}
+
+func (it *testTransactionIterator) Next() error {
+	it.idx++
+	return nil
+}
+
+func (it *testTransactionIterator) Get() (module.Transaction, int, error) {
+	return it.list.txs[it.idx], it.idx, nil
+}
+
+type testBlock struct {
+	test.BlockBase
+	height  int64
+	patches *testTransactionList
+	normals *testTransactionList
+	bloom   txresult.LogsBloom
+}
+
+func (b *testBlock) ID() []byte {
+	return []byte(fmt.Sprintf("block%d", b.height))
+}
+
+func (b *testBlock) Height() int64 {
+	return b.height
+}
+
+func (b *testBlock) Result() []byte {
+	return b.ID()
+}
+
+func (b *testBlock) LogsBloom() module.LogsBloom {
+	return &b.bloom
+}
+
+func (b *testBlock) PatchTransactions() module.TransactionList {
+	return b.patches
+}
+
+func (b *testBlock) NormalTransactions() module.TransactionList {
+	return b.normals
+}
+
+type testTransactionInfo struct {
+	blk   module.Block
+	index int
+	group module.TransactionGroup
+	tx    module.Transaction
+	rct   module.Receipt
+}
+
+func (ti *testTransactionInfo) Block() module.Block {
+	return ti.blk
+}
+
+func (ti *testTransactionInfo) Index() int {
+	return ti.index
+}
+
+func (ti *testTransactionInfo) Group() module.TransactionGroup {
+	return ti.group
+}
+
+func (ti *testTransactionInfo) Transaction() module.Transaction {
+	return ti.tx
+}
+
+func (ti *testTransactionInfo) GetReceipt() (module.Receipt, error) {
+	return ti.rct, nil
+}
+
+type receiptsKey struct {
+	result string
+	group  module.TransactionGroup
+}
+
+type testBlockManager struct {
+	test.BlockManagerBase
+	blocks   []*testBlock
+	receipts map[receiptsKey][]txresult.Receipt
+}
+
+func (bm *testBlockManager) GetLastBlock() (module.Block, error) {
+	return bm.blocks[len(bm.blocks)-1], nil
+}
+
+func (bm *testBlockManager) GetBlockByHeight(height int64) (module.Block, error) {
+	if height < 0 || height >= int64(len(bm.blocks)) {
+		return nil, errors.ErrNotFound
+	}
+	return bm.blocks[height], nil
+}
+
+func (bm *testBlockManager) GetTransactionInfo(id []byte) (module.TransactionInfo, error) {
+	for _, blk := range bm.blocks {
+		for _, g := range []module.TransactionGroup{module.TransactionGroupPatch, module.TransactionGroupNormal} {
+			txs, rblk := blk.patches, blk
+			if g == module.TransactionGroupNormal {
+				txs, rblk = blk.normals, bm.blocks[blk.height+1]
+			}
+			for i, tx := range txs.txs {
+				if string(tx.ID()) == string(id) {
+					rct := bm.receipts[receiptsKey{string(rblk.Result()), g}][i]
+					return &testTransactionInfo{blk, i, g, tx, rct}, nil
+				}
+			}
+		}
+	}
+	return nil, errors.ErrNotFound
+}
+
+type testServiceManager struct {
+	test.ServiceManagerBase
+	bm *testBlockManager
+}
+
+func (sm *testServiceManager) ReceiptListFromResult(result []byte, g module.TransactionGroup) (module.ReceiptList, error) {
+	return txresult.NewReceiptListFromSlice(db.NewMapDB(), sm.bm.receipts[receiptsKey{string(result), g}]), nil
+}
+
+type testGenesisStorage struct {
+	module.GenesisStorage
+}
+
+func (gs *testGenesisStorage) Height() int64 {
+	return 0
+}
+
+type testChain struct {
+	test.ChainBase
+	bm     *testBlockManager
+	sm     *testServiceManager
+	pruned int64
+}
+
+func (c *testChain) CID() int {
+	return 1
+}
+
+func (c *testChain) BlockManager() module.BlockManager {
+	return c.bm
+}
+
+func (c *testChain) ServiceManager() module.ServiceManager {
+	return c.sm
+}
+
+func (c *testChain) GenesisStorage() module.GenesisStorage {
+	return &testGenesisStorage{}
+}
+
+func (c *testChain) MaxLogsRange() int {
+	return 100
+}
+
+func (c *testChain) MaxLogsResult() int {
+	return 100
+}
+
+func (c *testChain) PrunedStateHeight() (int64, error) {
+	return c.pruned, nil
+}
+
+func invokeMethod(t *testing.T, c *testChain, method string, params interface{}) (interface{}, error) {
+	e := echo.New()
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+	e.Validator = validator
+
+	ps, err := json.Marshal(params)
+	assert.NoError(t, err)
+	req := &jsonrpc.Request{Version: jsonrpc.Version, Method: method, Params: ps, ID: 1}
+
+	mr := MethodRepository()
+	h, err := mr.TakeMethod(req)
+	assert.NoError(t, err)
+	ctx := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	ctx.Set("chain", module.Chain(c))
+	ctx.Set("includeDebug", true)
+	ctx.Set("method", h)
+	return mr.InvokeMethod(ctx, req)
+}
+
+func TestGetLogs_MatchesTransactionResult(t *testing.T) {
+	score := common.NewAddressFromString("cx059e19601bcb1424884f4ef19addc0a03de9e9cd")
+	sig := "Transfer(Address,Address,int)"
+	newReceipt := func(event bool) txresult.Receipt {
+		r := txresult.NewReceipt(db.NewMapDB(), 0, score)
+		if event {
+			r.AddLog(score, [][]byte{[]byte(sig), score.Bytes(), score.Bytes()}, [][]byte{{0x01}})
+		}
+		return r
+	}
+	newTxs := func(ids ...string) *testTransactionList {
+		l := &testTransactionList{}
+		for _, id := range ids {
+			l.txs = append(l.txs, &testTransaction{id: crypto.SHA3Sum256([]byte(id))})
+		}
+		return l
+	}
+
+	// normal transactions of block 1 and patch transaction of block 2 emit
+	// events, and all of their receipts are in the result of block 2.
+	bm := &testBlockManager{receipts: make(map[receiptsKey][]txresult.Receipt)}
+	for h := int64(0); h < 4; h++ {
+		bm.blocks = append(bm.blocks, &testBlock{
+			height:  h,
+			patches: newTxs(),
+			normals: newTxs(),
+		})
+	}
+	bm.blocks[1].normals = newTxs("tx1", "tx2", "tx3")
+	bm.blocks[2].patches = newTxs("patch1")
+	bm.blocks[3].normals = newTxs("tx4")
+	bm.receipts[receiptsKey{"block2", module.TransactionGroupNormal}] = []txresult.Receipt{
+		newReceipt(false), newReceipt(true), newReceipt(true),
+	}
+	bm.receipts[receiptsKey{"block2", module.TransactionGroupPatch}] = []txresult.Receipt{
+		newReceipt(true),
+	}
+	for _, rs := range bm.receipts {
+		for _, r := range rs {
+			bm.blocks[2].bloom.Merge(r.LogsBloom())
+		}
+	}
+	c := &testChain{bm: bm, sm: &testServiceManager{bm: bm}}
+
+	res, err := invokeMethod(t, c, "icx_getLogs", map[string]interface{}{
+		"fromHeight": "0x0",
+		"event":      sig,
+	})
+	assert.NoError(t, err)
+	logs := res.([]interface{})
+	assert.Len(t, logs, 3)
+
+	var hashes []string
+	groups := make(map[string]string)
+	for _, l := range logs {
+		log := l.(map[string]interface{})
+		hashes = append(hashes, log["txHash"].(string))
+		groups[log["txHash"].(string)] = log["txGroup"].(string)
+
+		events := log["events"].([]interface{})
+		assert.Len(t, events, 1)
+		bs, err := json.Marshal(events[0])
+		assert.NoError(t, err)
+		assert.JSONEq(t, fmt.Sprintf(`{
+			"index": "0x0",
+			"scoreAddress": "%[1]s",
+			"indexed": [ "%[2]s", "%[1]s", "%[1]s" ],
+			"data": [ "0x1" ]
+		}`, score, sig), string(bs))
+
+		res, err := invokeMethod(t, c, "icx_getTransactionResult", map[string]interface{}{
+			"txHash": log["txHash"],
+		})
+		assert.NoError(t, err)
+		tr := res.(map[string]interface{})
+		assert.Equal(t, tr["blockHeight"], log["blockHeight"])
+		assert.Equal(t, tr["blockHash"], log["blockHash"])
+		assert.Equal(t, tr["txIndex"], log["txIndex"])
+	}
+	var expected []string
+	for _, id := range []string{"tx2", "tx3", "patch1"} {
+		expected = append(expected, fmt.Sprintf("%#x", crypto.SHA3Sum256([]byte(id))))
+	}
+	assert.ElementsMatch(t, expected, hashes)
+	assert.Equal(t, "patch", groups[expected[2]])
+	assert.Equal(t, "normal", groups[expected[0]])
+}
+
+func TestGetBalance_PrunedState(t *testing.T) {
+	bm := &testBlockManager{}
+	for h := int64(0); h < 4; h++ {
+		bm.blocks = append(bm.blocks, &testBlock{height: h})
+	}
+	c := &testChain{bm: bm, sm: &testServiceManager{bm: bm}, pruned: 2}
+
+	_, err := invokeMethod(t, c, "icx_getBalance", map[string]interface{}{
+		"address": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+		"height":  "0x1",
+	})
+	if assert.IsType(t, &jsonrpc.Error{}, err) {
+		assert.Equal(t, jsonrpc.ErrorCodeNotFound, err.(*jsonrpc.Error).Code)
+		assert.Contains(t, err.(*jsonrpc.Error).Message, "StatePruned")
+	}
+}
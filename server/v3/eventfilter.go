@@ -0,0 +1,116 @@
+package v3
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+type EventFilter struct {
+	Addr       *common.Address `json:"addr,omitempty"`
+	Signature  string          `json:"event"`
+	Indexed    []*string       `json:"indexed,omitempty"`
+	Data       []*string       `json:"data,omitempty"`
+	indexedBSs [][]byte
+	dataBSs    [][]byte
+	numOfArgs  int
+	lb         module.LogsBloom
+	indexes    []int
+}
+
+// Compile parses arguments of the filter with types of the event signature.
+// It should be called before calling other methods.
+func (f *EventFilter) Compile() error {
+	lb := txresult.NewLogsBloom(nil)
+	if f.Addr != nil {
+		lb.AddAddressOfLog(f.Addr)
+	}
+	f.numOfArgs = len(f.Indexed) + len(f.Data)
+	name, pts := txresult.DecomposeEventSignature(f.Signature)
+	if len(name) == 0 || pts == nil || len(pts) < f.numOfArgs {
+		return errors.NewBase(errors.IllegalArgumentError, "bad event signature")
+	}
+	lb.AddIndexedOfLog(0, []byte(f.Signature))
+	idx := 0
+	f.indexedBSs = make([][]byte, len(f.Indexed))
+	for i, arg := range f.Indexed {
+		if arg != nil {
+			bs, err := txresult.EventDataStringToBytesByType(pts[idx], string(*arg))
+			if err != nil {
+				return errors.NewBase(errors.IllegalArgumentError, "bad event data")
+			}
+			lb.AddIndexedOfLog(i+1, bs)
+			f.indexedBSs[i] = bs
+		}
+		idx++
+	}
+	f.dataBSs = make([][]byte, len(f.Data))
+	for i, arg := range f.Data {
+		if arg != nil {
+			bs, err := txresult.EventDataStringToBytesByType(pts[idx], string(*arg))
+			if err != nil {
+				return errors.NewBase(errors.IllegalArgumentError, "bad event data")
+			}
+			f.dataBSs[i] = bs
+		}
+		idx++
+	}
+	f.lb = lb
+	return nil
+}
+
+// bytesEqual check equality of byte slice.
+// But it doesn't assume nil as empty bytes.
+func bytesEqual(b1 []byte, b2 []byte) bool {
+	if b1 == nil && b2 == nil {
+		return true
+	}
+	if b1 == nil || b2 == nil {
+		return false
+	}
+	return bytes.Equal(b1, b2)
+}
+
+// LogsBloom returns logs bloom for the filter. It's available after Compile.
+func (f *EventFilter) LogsBloom() module.LogsBloom {
+	return f.lb
+}
+
+// Match returns indexes of events in the receipt matched with the filter.
+func (f *EventFilter) Match(r module.Receipt) ([]common.HexInt32, bool) {
+	eventIndexes := make([]common.HexInt32, 0)
+	if r.LogsBloom().Contain(f.lb) {
+	loop:
+		for it, idx := r.EventLogIterator(), int32(0); it.Has(); _, idx = it.Next(), idx+1 {
+			if el, err := it.Get(); err == nil {
+				if bytes.Equal([]byte(f.Signature), el.Indexed()[0]) {
+					if f.Addr != nil && !el.Address().Equal(f.Addr) {
+						continue loop
+					}
+					if f.numOfArgs > 0 {
+						if (len(el.Indexed()) + len(el.Data())) <= f.numOfArgs {
+							continue loop
+						}
+
+						for i, arg := range f.indexedBSs {
+							if arg != nil && !bytesEqual(arg, el.Indexed()[i+1]) {
+								continue loop
+							}
+						}
+						for i, arg := range f.dataBSs {
+							if arg != nil && !bytesEqual(arg, el.Data()[i]) {
+								continue loop
+							}
+						}
+					}
+					eventIndexes = append(eventIndexes, common.HexInt32{Value: idx})
+				}
+			}
+		}
+		return eventIndexes, len(eventIndexes) > 0
+	}
+	return eventIndexes, false
+}
@@ -0,0 +1,71 @@
+package v3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+func TestLogsParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	var param LogsParam
+	bs := []byte(`{
+		"fromHeight": "0x10",
+		"addr": "cx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+		"event": "Transfer(Address,Address,int)",
+		"indexed": [ null, "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31" ]
+	}`)
+	assert.NoError(t, json.Unmarshal(bs, &param))
+	assert.NoError(t, validator.Validate(&param))
+	assert.NoError(t, param.Compile())
+
+	var param2 LogsParam
+	assert.NoError(t, json.Unmarshal([]byte(`{"event": "Transfer(Address,Address,int)"}`), &param2))
+	assert.Error(t, validator.Validate(&param2))
+}
+
+func TestEventFilter_Match(t *testing.T) {
+	score := common.NewAddressFromString("cx059e19601bcb1424884f4ef19addc0a03de9e9cd")
+	from := common.NewAddressFromString("hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31")
+	to := common.NewAddressFromString("hx4e436ed6adf72b6d2a80613cc15d5af5ddb6701e")
+	sig := "Transfer(Address,Address,int)"
+
+	r := txresult.NewReceipt(db.NewMapDB(), 0, score)
+	r.AddLog(score, [][]byte{[]byte(sig), from.Bytes(), to.Bytes()}, [][]byte{intconv.Int64ToBytes(1)})
+	r.AddLog(score, [][]byte{[]byte(sig), to.Bytes(), from.Bytes()}, [][]byte{intconv.Int64ToBytes(2)})
+
+	toStr := to.String()
+	f := &EventFilter{
+		Addr:      score,
+		Signature: sig,
+		Indexed:   []*string{nil, &toStr},
+	}
+	assert.NoError(t, f.Compile())
+	assert.True(t, r.LogsBloom().Contain(f.LogsBloom()))
+	idxs, ok := f.Match(r)
+	assert.True(t, ok)
+	assert.Equal(t, []common.HexInt32{{Value: 0}}, idxs)
+
+	f = &EventFilter{Signature: sig}
+	assert.NoError(t, f.Compile())
+	idxs, ok = f.Match(r)
+	assert.True(t, ok)
+	assert.Equal(t, []common.HexInt32{{Value: 0}, {Value: 1}}, idxs)
+
+	f = &EventFilter{Signature: "Approval(Address,Address,int)"}
+	assert.NoError(t, f.Compile())
+	_, ok = f.Match(r)
+	assert.False(t, ok)
+
+	f = &EventFilter{Signature: "Transfer"}
+	assert.Error(t, f.Compile())
+}
@@ -0,0 +1,73 @@
+package v3
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+// TxRateLimiter counts requests of clients in the current second.
+type TxRateLimiter struct {
+	lock   sync.Mutex
+	now    func() time.Time
+	window int64
+	counts map[string]int
+}
+
+func NewTxRateLimiter() *TxRateLimiter {
+	return &TxRateLimiter{
+		now:    time.Now,
+		counts: make(map[string]int),
+	}
+}
+
+// Allow returns whether the request of the client can be handled under
+// the limit of requests in a second.
+func (l *TxRateLimiter) Allow(key string, limit int) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if w := l.now().Unix(); w != l.window {
+		l.window = w
+		l.counts = make(map[string]int)
+	}
+	if l.counts[key] >= limit {
+		return false
+	}
+	l.counts[key] += 1
+	return true
+}
+
+func remoteIP(ctx *jsonrpc.Context) string {
+	addr := ctx.Request().RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// checkTxRateLimit checks rate limit of the chain for the client.
+// TxRateLimiter is injected to the context by the server with the key
+// "txRateLimiter".
+func checkTxRateLimit(ctx *jsonrpc.Context, chain module.Chain) error {
+	limit := chain.TxRateLimit()
+	if limit <= 0 {
+		return nil
+	}
+	l, ok := ctx.Get("txRateLimiter").(*TxRateLimiter)
+	if !ok {
+		return nil
+	}
+	// use the address of the connection, because headers like
+	// X-Forwarded-For can be forged by the client.
+	ip := remoteIP(ctx)
+	if !l.Allow(fmt.Sprintf("%d/%s", chain.CID(), ip), limit) {
+		return jsonrpc.ErrorLackOfResource.Errorf(
+			"TooManyRequests(ip=%s,limit=%d)", ip, limit)
+	}
+	return nil
+}
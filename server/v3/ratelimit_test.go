@@ -0,0 +1,37 @@
+package v3
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+func TestTxRateLimiter_Allow(t *testing.T) {
+	now := time.Unix(100, 0)
+	l := NewTxRateLimiter()
+	l.now = func() time.Time {
+		return now
+	}
+
+	assert.True(t, l.Allow("1/127.0.0.1", 2))
+	assert.True(t, l.Allow("1/127.0.0.1", 2))
+	assert.False(t, l.Allow("1/127.0.0.1", 2))
+	assert.True(t, l.Allow("1/127.0.0.2", 2))
+
+	now = now.Add(time.Second)
+	assert.True(t, l.Allow("1/127.0.0.1", 2))
+}
+
+func TestRemoteIP(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/v3", nil)
+	req.RemoteAddr = "127.0.0.1:12345"
+	req.Header.Set(echo.HeaderXForwardedFor, "127.0.0.2")
+	req.Header.Set(echo.HeaderXRealIP, "127.0.0.3")
+	ctx := jsonrpc.NewContext(echo.New().NewContext(req, httptest.NewRecorder()))
+	assert.Equal(t, "127.0.0.1", remoteIP(ctx))
+}
@@ -26,20 +26,32 @@ type CallParam struct {
 	ToAddress   jsonrpc.Address `json:"to" validate:"required,t_addr_score"`
 	DataType    string          `json:"dataType" validate:"required,call"`
 	Data        interface{}     `json:"data"`
+	Height      jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
 }
 
 type AddressParam struct {
 	Address jsonrpc.Address `json:"address" validate:"required,t_addr"`
+	Height  jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
 }
 
 type ScoreAddressParam struct {
 	Address jsonrpc.Address `json:"address" validate:"required,t_addr_score"`
+	Height  jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+}
+
+type TotalSupplyParam struct {
+	Height jsonrpc.HexInt `json:"height,omitempty" validate:"optional,t_int"`
 }
 
 type TransactionHashParam struct {
 	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
 }
 
+type TraceParam struct {
+	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
+	Mode string           `json:"mode,omitempty"`
+}
+
 type TransactionParamForEstimate struct {
 	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
 	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
@@ -66,6 +78,15 @@ type TransactionParam struct {
 	Data        interface{}     `json:"data,omitempty"`
 }
 
+type BlockProofParam struct {
+	Height   jsonrpc.HexInt `json:"height" validate:"required,t_int"`
+	AtHeight jsonrpc.HexInt `json:"atHeight" validate:"required,t_int"`
+}
+
+type EvidenceParam struct {
+	Height jsonrpc.HexInt `json:"height,omitempty" validate:"optional,t_int"`
+}
+
 type DataHashParam struct {
 	Hash jsonrpc.HexBytes `json:"hash" validate:"required,t_hash"`
 }
@@ -80,3 +101,15 @@ type ProofEventsParam struct {
 	Index     jsonrpc.HexInt   `json:"index" validate:"required,t_int"`
 	Events    []jsonrpc.HexInt `json:"events" validate:"gt=0,dive,t_int"`
 }
+
+type AccountTransactionsParam struct {
+	Address jsonrpc.Address `json:"address" validate:"required,t_addr"`
+	Start   jsonrpc.HexInt  `json:"start,omitempty" validate:"optional,t_int"`
+	Limit   jsonrpc.HexInt  `json:"limit,omitempty" validate:"optional,t_int"`
+}
+
+type LogsParam struct {
+	EventFilter
+	FromHeight jsonrpc.HexInt `json:"fromHeight" validate:"required,t_int"`
+	ToHeight   jsonrpc.HexInt `json:"toHeight,omitempty" validate:"optional,t_int"`
+}
@@ -11,7 +11,7 @@ import (
 
 var (
 	hexString          = regexp.MustCompile("^0x[0-9a-f]+$")
-	deployContentTypes = []string{"application/zip", "application/java"}
+	deployContentTypes = []string{"application/zip", "application/java", "application/x.score.go"}
 )
 
 func RegisterValidationRule(v *jsonrpc.Validator) {
@@ -133,6 +133,33 @@ func (wm *wsSessionManager) initSession(ctx echo.Context, reqPtr interface{}) (*
 	return wss, nil
 }
 
+// acceptSession upgrades the connection and registers a session for it
+// without reading any request.
+func (wm *wsSessionManager) acceptSession(ctx echo.Context) (*wsSession, error) {
+	chain, err := wm.chain(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u := Upgrader()
+	c, err := u.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	wss := wm.NewSession(c, chain)
+	if wss == nil {
+		wsResponse := WSResponse{
+			Code:    int(jsonrpc.ErrorLackOfResource),
+			Message: "too many monitor",
+		}
+		c.WriteJSON(&wsResponse)
+		c.Close()
+		return nil, errors.New("too many sessions")
+	}
+	return wss, nil
+}
+
 func (wm *wsSessionManager) chain(ctx echo.Context) (module.Chain, error) {
 	c, ok := ctx.Get("chain").(module.Chain)
 	if !ok {
@@ -1,26 +1,42 @@
 package server
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service"
 )
 
 type BlockRequest struct {
 	Height       common.HexInt64 `json:"height"`
 	EventFilters []*EventFilter  `json:"eventFilters,omitempty"`
+	Header       bool            `json:"header,omitempty"`
+	Votes        bool            `json:"votes,omitempty"`
 	bn           BlockNotification
 }
 
+type BlockResult struct {
+	StateHash         common.HexBytes `json:"stateHash"`
+	PatchReceiptHash  common.HexBytes `json:"patchReceiptHash"`
+	NormalReceiptHash common.HexBytes `json:"normalReceiptHash"`
+}
+
 type BlockNotification struct {
-	Hash    common.HexBytes       `json:"hash"`
-	Height  common.HexInt64       `json:"height"`
-	Indexes [][]common.HexInt32   `json:"indexes,omitempty"`
-	Events  [][][]common.HexInt32 `json:"events,omitempty"`
+	Hash               common.HexBytes       `json:"hash"`
+	Height             common.HexInt64       `json:"height"`
+	Header             common.HexBytes       `json:"header,omitempty"`
+	Result             *BlockResult          `json:"result,omitempty"`
+	NextValidatorsHash common.HexBytes       `json:"nextValidatorsHash,omitempty"`
+	Votes              common.HexBytes       `json:"votes,omitempty"`
+	ValidatorsHash     common.HexBytes       `json:"validatorsHash,omitempty"`
+	Indexes            [][]common.HexInt32   `json:"indexes,omitempty"`
+	Events             [][][]common.HexInt32 `json:"events,omitempty"`
 }
 
 func (wm *wsSessionManager) RunBlockSession(ctx echo.Context) error {
@@ -75,6 +91,16 @@ loop:
 		case blk := <-bch:
 			br.bn.Height = common.HexInt64{Value: h}
 			br.bn.Hash = blk.ID()
+			if br.Header {
+				if err = br.fillHeader(blk); err != nil {
+					break loop
+				}
+			}
+			if br.Votes {
+				if err = br.fillVotes(wss.chain, bm, blk, ech); err != nil {
+					break loop
+				}
+			}
 			if rl != nil {
 				rl = nil
 			}
@@ -90,7 +116,7 @@ loop:
 			}
 			lb := blk.LogsBloom()
 			for i, f := range br.EventFilters {
-				if lb.Contain(f.lb) {
+				if lb.Contain(f.LogsBloom()) {
 					if rl == nil {
 						rl, err = sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
 						if err != nil {
@@ -103,7 +129,7 @@ loop:
 						if err != nil {
 							break loop
 						}
-						if es, ok := f.match(r); ok {
+						if es, ok := f.Match(r); ok {
 							if len(br.bn.Indexes) < 1 {
 								br.bn.Indexes = indexes[:]
 								br.bn.Events = events[:]
@@ -126,9 +152,66 @@ loop:
 	return nil
 }
 
+// fillHeader sets the header of the block with its result and the hash of
+// next validators.
+func (r *BlockRequest) fillHeader(blk module.Block) error {
+	buf := bytes.NewBuffer(nil)
+	if err := blk.MarshalHeader(buf); err != nil {
+		return err
+	}
+	r.bn.Header = buf.Bytes()
+	r.bn.Result = nil
+	if sh, prh, nrh, err := service.ParseResult(blk.Result()); err != nil {
+		return err
+	} else if sh != nil {
+		r.bn.Result = &BlockResult{
+			StateHash:         sh,
+			PatchReceiptHash:  prh,
+			NormalReceiptHash: nrh,
+		}
+	}
+	r.bn.NextValidatorsHash = blk.NextValidatorsHash()
+	return nil
+}
+
+// fillVotes sets commit votes for the block and the hash of validators
+// who can vote for the block, which is next validators of the previous block.
+// If the consensus doesn't have votes for the block, for example the block
+// is synced from other nodes, it waits for the next block including them.
+// It returns the error from ech if it's received while waiting.
+func (r *BlockRequest) fillVotes(c module.Chain, bm module.BlockManager, blk module.Block, ech <-chan error) error {
+	r.bn.Votes = nil
+	r.bn.ValidatorsHash = nil
+	if blk.Height() <= c.GenesisStorage().Height() {
+		return nil
+	}
+	votes, err := c.Consensus().GetVotesByHeight(blk.Height())
+	if errors.NotFoundError.Equals(err) {
+		bch, err := bm.WaitForBlock(blk.Height() + 1)
+		if err != nil {
+			return err
+		}
+		select {
+		case err := <-ech:
+			return err
+		case next := <-bch:
+			votes = next.Votes()
+		}
+	} else if err != nil {
+		return err
+	}
+	prev, err := bm.GetBlock(blk.PrevID())
+	if err != nil {
+		return err
+	}
+	r.bn.Votes = votes.Bytes()
+	r.bn.ValidatorsHash = prev.NextValidatorsHash()
+	return nil
+}
+
 func (r *BlockRequest) compile() error {
 	for i, f := range r.EventFilters {
-		if err := f.compile(); err != nil {
+		if err := f.Compile(); err != nil {
 			return fmt.Errorf("fail to compile idx:%d, err:%v", i, err)
 		}
 	}
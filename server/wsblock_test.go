@@ -0,0 +1,65 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func newTestVotesChain() *testChain {
+	bm := &testBlockManager{}
+	for h := int64(0); h < 3; h++ {
+		bm.blocks = append(bm.blocks, &testBlock{height: h})
+	}
+	bm.blocks[2].votes = &testCommitVoteSet{bytes: []byte("votes1")}
+	return &testChain{
+		bm: bm,
+		cs: &testConsensus{votes: map[int64]module.CommitVoteSet{
+			2: &testCommitVoteSet{bytes: []byte("votes2")},
+		}},
+	}
+}
+
+func TestBlockRequest_FillVotes(t *testing.T) {
+	c := newTestVotesChain()
+	ech := make(chan error)
+
+	var br BlockRequest
+	assert.NoError(t, br.fillVotes(c, c.bm, c.bm.blocks[0], ech))
+	assert.Nil(t, br.bn.Votes)
+
+	assert.NoError(t, br.fillVotes(c, c.bm, c.bm.blocks[2], ech))
+	assert.Equal(t, []byte("votes2"), []byte(br.bn.Votes))
+	assert.Equal(t, []byte("validators1"), []byte(br.bn.ValidatorsHash))
+
+	// votes not in the consensus are taken from the next block
+	assert.NoError(t, br.fillVotes(c, c.bm, c.bm.blocks[1], ech))
+	assert.Equal(t, []byte("votes1"), []byte(br.bn.Votes))
+	assert.Equal(t, []byte("validators0"), []byte(br.bn.ValidatorsHash))
+}
+
+func TestBlockRequest_FillVotesWaitsForNextBlock(t *testing.T) {
+	c := newTestVotesChain()
+	c.bm.blocks = c.bm.blocks[:2]
+	ech := make(chan error)
+
+	// it waits for the next block until the session is closed
+	done := make(chan error)
+	var br BlockRequest
+	go func() {
+		done <- br.fillVotes(c, c.bm, c.bm.blocks[1], ech)
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("it should wait for the next block err=%+v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	closed := errors.New("closed")
+	ech <- closed
+	assert.Equal(t, closed, <-done)
+	assert.Nil(t, br.bn.Votes)
+}
@@ -1,16 +1,14 @@
 package server
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/icon-project/goloop/common"
-	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
-	"github.com/icon-project/goloop/service/txresult"
+	"github.com/icon-project/goloop/server/v3"
 )
 
 type EventRequest struct {
@@ -18,17 +16,8 @@ type EventRequest struct {
 	Height common.HexInt64 `json:"height"`
 }
 
-type EventFilter struct {
-	Addr       *common.Address `json:"addr,omitempty"`
-	Signature  string          `json:"event"`
-	Indexed    []*string       `json:"indexed,omitempty"`
-	Data       []*string       `json:"data,omitempty"`
-	indexedBSs [][]byte
-	dataBSs    [][]byte
-	numOfArgs  int
-	lb         module.LogsBloom
-	indexes    []int
-}
+// EventFilter is shared with icx_getLogs of JSON-RPC API v3.
+type EventFilter = v3.EventFilter
 
 type EventNotification struct {
 	Hash   common.HexBytes   `json:"hash"`
@@ -45,7 +34,7 @@ func (wm *wsSessionManager) RunEventSession(ctx echo.Context) error {
 	}
 	defer wm.StopSession(wss)
 
-	if err := er.compile(); err != nil {
+	if err := er.Compile(); err != nil {
 		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), "bad event request parameter")
 		return nil
 	}
@@ -81,7 +70,7 @@ loop:
 		case err = <-ech:
 			break loop
 		case blk := <-bch:
-			if !blk.LogsBloom().Contain(er.lb) {
+			if !blk.LogsBloom().Contain(er.LogsBloom()) {
 				h++
 				continue loop
 			}
@@ -95,7 +84,7 @@ loop:
 				if err != nil {
 					break loop
 				}
-				if es, ok := er.match(r); ok {
+				if es, ok := er.Match(r); ok {
 					var en EventNotification
 					en.Height.Value = h
 					en.Hash = blk.ID()
@@ -114,89 +103,3 @@ loop:
 	wm.logger.Warnf("%+v\n", err)
 	return nil
 }
-
-func (f *EventFilter) compile() error {
-	lb := txresult.NewLogsBloom(nil)
-	if f.Addr != nil {
-		lb.AddAddressOfLog(f.Addr)
-	}
-	f.numOfArgs = len(f.Indexed) + len(f.Data)
-	name, pts := txresult.DecomposeEventSignature(f.Signature)
-	if len(name) == 0 || pts == nil || len(pts) < f.numOfArgs {
-		return errors.NewBase(errors.IllegalArgumentError, "bad event signature")
-	}
-	lb.AddIndexedOfLog(0, []byte(f.Signature))
-	idx := 0
-	f.indexedBSs = make([][]byte, len(f.Indexed))
-	for i, arg := range f.Indexed {
-		if arg != nil {
-			bs, err := txresult.EventDataStringToBytesByType(pts[idx], string(*arg))
-			if err != nil {
-				return errors.NewBase(errors.IllegalArgumentError, "bad event data")
-			}
-			lb.AddIndexedOfLog(i+1, bs)
-			f.indexedBSs[i] = bs
-		}
-		idx++
-	}
-	f.dataBSs = make([][]byte, len(f.Data))
-	for i, arg := range f.Data {
-		if arg != nil {
-			bs, err := txresult.EventDataStringToBytesByType(pts[idx], string(*arg))
-			if err != nil {
-				return errors.NewBase(errors.IllegalArgumentError, "bad event data")
-			}
-			f.dataBSs[i] = bs
-		}
-		idx++
-	}
-	f.lb = lb
-	return nil
-}
-
-// bytesEqual check equality of byte slice.
-// But it doesn't assume nil as empty bytes.
-func bytesEqual(b1 []byte, b2 []byte) bool {
-	if b1 == nil && b2 == nil {
-		return true
-	}
-	if b1 == nil || b2 == nil {
-		return false
-	}
-	return bytes.Equal(b1, b2)
-}
-
-func (f *EventFilter) match(r module.Receipt) ([]common.HexInt32, bool) {
-	eventIndexes := make([]common.HexInt32, 0)
-	if r.LogsBloom().Contain(f.lb) {
-	loop:
-		for it, idx := r.EventLogIterator(), int32(0); it.Has(); _, idx = it.Next(), idx+1 {
-			if el, err := it.Get(); err == nil {
-				if bytes.Equal([]byte(f.Signature), el.Indexed()[0]) {
-					if f.Addr != nil && !el.Address().Equal(f.Addr) {
-						continue loop
-					}
-					if f.numOfArgs > 0 {
-						if (len(el.Indexed()) + len(el.Data())) <= f.numOfArgs {
-							continue loop
-						}
-
-						for i, arg := range f.indexedBSs {
-							if arg != nil && !bytesEqual(arg, el.Indexed()[i+1]) {
-								continue loop
-							}
-						}
-						for i, arg := range f.dataBSs {
-							if arg != nil && !bytesEqual(arg, el.Data()[i]) {
-								continue loop
-							}
-						}
-					}
-					eventIndexes = append(eventIndexes, common.HexInt32{Value: idx})
-				}
-			}
-		}
-		return eventIndexes, len(eventIndexes) > 0
-	}
-	return eventIndexes, false
-}
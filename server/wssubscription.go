@@ -0,0 +1,311 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	configMaxSubscriptions       = 32
+	configMaxSubscriptionFilters = 16
+)
+
+const (
+	SubscriptionMethodSubscribe   = "subscribe"
+	SubscriptionMethodUnsubscribe = "unsubscribe"
+)
+
+// EventCursor points an event in the chain. Height is the height of the block
+// including the result, and TxIndex is the index of the result in the block.
+// On resuming with the cursor, only events after it are notified.
+type EventCursor struct {
+	Height     common.HexInt64 `json:"height"`
+	TxIndex    common.HexInt32 `json:"txIndex"`
+	EventIndex common.HexInt32 `json:"eventIndex"`
+}
+
+// covers returns true if the event is at or before the cursor in the block
+// of the cursor.
+func (c *EventCursor) covers(height int64, txIndex, eventIndex int32) bool {
+	if c == nil || c.Height.Value != height {
+		return false
+	}
+	if txIndex != c.TxIndex.Value {
+		return txIndex < c.TxIndex.Value
+	}
+	return eventIndex <= c.EventIndex.Value
+}
+
+type SubscriptionRequest struct {
+	Method  string          `json:"method"`
+	ID      common.HexInt32 `json:"id,omitempty"`
+	Height  common.HexInt64 `json:"height,omitempty"`
+	Cursor  *EventCursor    `json:"cursor,omitempty"`
+	Filters []*EventFilter  `json:"filters,omitempty"`
+}
+
+type SubscriptionResponse struct {
+	Code    int              `json:"code"`
+	Message string           `json:"message,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	ID      *common.HexInt32 `json:"id,omitempty"`
+}
+
+type SubscriptionNotification struct {
+	ID      common.HexInt32   `json:"id"`
+	Hash    common.HexBytes   `json:"hash"`
+	Cursor  EventCursor       `json:"cursor"`
+	Filters []common.HexInt32 `json:"filters"`
+}
+
+type subscription struct {
+	id      int32
+	filters []*EventFilter
+	height  int64
+	cursor  *EventCursor
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+type subscriptionSession struct {
+	mtx    sync.Mutex
+	c      *websocket.Conn
+	chain  module.Chain
+	subs   map[int32]*subscription
+	lastID int32
+	wg     sync.WaitGroup
+}
+
+func (ss *subscriptionSession) WriteJSON(v interface{}) error {
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+	return ss.c.WriteJSON(v)
+}
+
+func (ss *subscriptionSession) response(method string, id *common.HexInt32, code int, msg string) error {
+	return ss.WriteJSON(&SubscriptionResponse{
+		Code:    code,
+		Message: msg,
+		Method:  method,
+		ID:      id,
+	})
+}
+
+func (ss *subscriptionSession) newSubscription(req *SubscriptionRequest) (*subscription, error) {
+	if len(req.Filters) < 1 || len(req.Filters) > configMaxSubscriptionFilters {
+		return nil, fmt.Errorf("invalid number of filters(%d)", len(req.Filters))
+	}
+	for i, f := range req.Filters {
+		if f == nil {
+			return nil, fmt.Errorf("null filter idx:%d", i)
+		}
+		if err := f.Compile(); err != nil {
+			return nil, fmt.Errorf("fail to compile idx:%d, err:%v", i, err)
+		}
+	}
+	h := req.Height.Value
+	if req.Cursor != nil {
+		h = req.Cursor.Height.Value
+	}
+	if gh := ss.chain.GenesisStorage().Height(); gh > h {
+		return nil, fmt.Errorf("given height(%d) is lower than genesis height(%d)", h, gh)
+	}
+	ss.lastID++
+	return &subscription{
+		id:      ss.lastID,
+		filters: req.Filters,
+		height:  h,
+		cursor:  req.Cursor,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// purge removes subscriptions finished by failures.
+func (ss *subscriptionSession) purge() {
+	for id, s := range ss.subs {
+		select {
+		case <-s.done:
+			delete(ss.subs, id)
+		default:
+		}
+	}
+}
+
+func (ss *subscriptionSession) stopAll() {
+	for id, s := range ss.subs {
+		close(s.stop)
+		delete(ss.subs, id)
+	}
+	ss.wg.Wait()
+}
+
+func (ss *subscriptionSession) handle(req *SubscriptionRequest, logger log.Logger) error {
+	switch req.Method {
+	case SubscriptionMethodSubscribe:
+		ss.purge()
+		if len(ss.subs) >= configMaxSubscriptions {
+			return ss.response(req.Method, nil, int(jsonrpc.ErrorLackOfResource),
+				"too many subscriptions")
+		}
+		bm := ss.chain.BlockManager()
+		sm := ss.chain.ServiceManager()
+		if bm == nil || sm == nil {
+			return ss.response(req.Method, nil, int(jsonrpc.ErrorCodeServer), "Stopped")
+		}
+		s, err := ss.newSubscription(req)
+		if err != nil {
+			return ss.response(req.Method, nil, int(jsonrpc.ErrorCodeInvalidParams), err.Error())
+		}
+		id := common.HexInt32{Value: s.id}
+		if err := ss.response(req.Method, &id, 0, ""); err != nil {
+			return err
+		}
+		ss.subs[s.id] = s
+		ss.wg.Add(1)
+		go func() {
+			defer ss.wg.Done()
+			defer close(s.done)
+			if err := ss.run(s, bm, sm); err != nil {
+				logger.Warnf("subscription id=%d finished err=%+v", s.id, err)
+				_ = ss.response("", &id, int(jsonrpc.ErrorCodeSystem), err.Error())
+			}
+		}()
+		return nil
+	case SubscriptionMethodUnsubscribe:
+		id := req.ID
+		s, ok := ss.subs[id.Value]
+		if !ok {
+			return ss.response(req.Method, &id, int(jsonrpc.ErrorCodeNotFound),
+				fmt.Sprintf("subscription(%d) not found", id.Value))
+		}
+		delete(ss.subs, id.Value)
+		close(s.stop)
+		<-s.done
+		return ss.response(req.Method, &id, 0, "")
+	default:
+		return ss.response(req.Method, nil, int(jsonrpc.ErrorCodeMethodNotFound),
+			fmt.Sprintf("unknown method(%s)", req.Method))
+	}
+}
+
+func (ss *subscriptionSession) run(s *subscription, bm module.BlockManager, sm module.ServiceManager) error {
+	var sn SubscriptionNotification
+	sn.ID.Value = s.id
+	for h := s.height; ; h++ {
+		bch, err := bm.WaitForBlock(h)
+		if err != nil {
+			return err
+		}
+		var blk module.Block
+		select {
+		case <-s.stop:
+			return nil
+		case blk = <-bch:
+		}
+		lb := blk.LogsBloom()
+		matched := false
+		for _, f := range s.filters {
+			if lb.Contain(f.LogsBloom()) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		rl, err := sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+		if err != nil {
+			return err
+		}
+		index := int32(0)
+		for rit := rl.Iterator(); rit.Has(); rit.Next() {
+			r, err := rit.Get()
+			if err != nil {
+				return err
+			}
+			events := make(map[int32][]common.HexInt32)
+			for i, f := range s.filters {
+				if es, ok := f.Match(r); ok {
+					for _, e := range es {
+						events[e.Value] = append(events[e.Value], common.HexInt32{Value: int32(i)})
+					}
+				}
+			}
+			eis := make([]int32, 0, len(events))
+			for ei := range events {
+				if !s.cursor.covers(h, index, ei) {
+					eis = append(eis, ei)
+				}
+			}
+			sort.Slice(eis, func(i, j int) bool {
+				return eis[i] < eis[j]
+			})
+			for _, ei := range eis {
+				sn.Hash = blk.ID()
+				sn.Cursor = EventCursor{
+					Height:     common.HexInt64{Value: h},
+					TxIndex:    common.HexInt32{Value: index},
+					EventIndex: common.HexInt32{Value: ei},
+				}
+				sn.Filters = events[ei]
+				if err := ss.WriteJSON(&sn); err != nil {
+					return err
+				}
+			}
+			index++
+		}
+	}
+}
+
+// RunSubscriptionSession handles subscriptions of events through a websocket.
+// Clients may subscribe and unsubscribe multiple times with a set of filters,
+// and notifications are tagged with the id of the subscription.
+func (wm *wsSessionManager) RunSubscriptionSession(ctx echo.Context) error {
+	wss, err := wm.acceptSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	ss := &subscriptionSession{
+		c:     wss.c,
+		chain: wss.chain,
+		subs:  make(map[int32]*subscription),
+	}
+	ss.serve(wm.logger)
+	return nil
+}
+
+// serve handles requests from the websocket until it's closed.
+func (ss *subscriptionSession) serve(logger log.Logger) {
+	defer ss.stopAll()
+
+	for {
+		_, msgBS, err := ss.c.ReadMessage()
+		if err != nil {
+			logger.Infof("subscription session finished err=%+v", err)
+			return
+		}
+		var req SubscriptionRequest
+		if err := json.Unmarshal(msgBS, &req); err != nil {
+			err = ss.response("", nil, int(jsonrpc.ErrorCodeJsonParse), "bad subscription request")
+		} else {
+			err = ss.handle(&req, logger)
+		}
+		if err != nil {
+			logger.Infof("fail to write json SubscriptionResponse err:%+v", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	testTransferSig = "Transfer(Address,Address,int)"
+	testApprovalSig = "Approval(Address,Address,int)"
+)
+
+var testScore = common.NewAddressFromString("cx059e19601bcb1424884f4ef19addc0a03de9e9cd")
+
+// newTestEventChain returns the chain with events below.
+//	height 1: tx0 => Transfer, Approval / tx1 => Approval
+//	height 2: tx0 => Transfer, Transfer
+//	height 3: no events
+func newTestEventChain() *testChain {
+	newReceipt := func(sigs ...string) txresult.Receipt {
+		r := txresult.NewReceipt(db.NewMapDB(), 0, testScore)
+		for i, sig := range sigs {
+			r.AddLog(testScore,
+				[][]byte{[]byte(sig), testScore.Bytes(), testScore.Bytes()},
+				[][]byte{{byte(i)}})
+		}
+		return r
+	}
+	bm := &testBlockManager{}
+	sm := &testServiceManager{receipts: make(map[string][]txresult.Receipt)}
+	for h := int64(0); h < 4; h++ {
+		bm.blocks = append(bm.blocks, &testBlock{height: h})
+	}
+	sm.receipts["block1"] = []txresult.Receipt{
+		newReceipt(testTransferSig, testApprovalSig),
+		newReceipt(testApprovalSig),
+	}
+	sm.receipts["block2"] = []txresult.Receipt{
+		newReceipt(testTransferSig, testTransferSig),
+	}
+	for _, blk := range bm.blocks {
+		for _, r := range sm.receipts[string(blk.ID())] {
+			blk.bloom.Merge(r.LogsBloom())
+		}
+	}
+	return &testChain{bm: bm, sm: sm}
+}
+
+// dialSubscriptionSession runs the subscription session for the chain and
+// returns the websocket connected to it.
+func dialSubscriptionSession(t *testing.T, chain module.Chain) (*websocket.Conn, func()) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		ss := &subscriptionSession{
+			c:     c,
+			chain: chain,
+			subs:  make(map[int32]*subscription),
+		}
+		ss.serve(log.New())
+	}))
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	c, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		srv.Close()
+		t.Fatalf("fail to dial err=%+v", err)
+	}
+	return c, func() {
+		c.Close()
+		srv.Close()
+	}
+}
+
+// readMessage reads a response or a notification. It returns nil for both
+// if there is no message until the timeout. The connection is not usable
+// after the timeout.
+func readMessage(t *testing.T, c *websocket.Conn, timeout time.Duration) (*SubscriptionResponse, *SubscriptionNotification) {
+	_ = c.SetReadDeadline(time.Now().Add(timeout))
+	_, bs, err := c.ReadMessage()
+	if err != nil {
+		if ne, ok := err.(interface{ Timeout() bool }); ok && ne.Timeout() {
+			return nil, nil
+		}
+		t.Fatalf("fail to read message err=%+v", err)
+	}
+	var fields map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(bs, &fields))
+	if _, ok := fields["cursor"]; ok {
+		sn := new(SubscriptionNotification)
+		assert.NoError(t, json.Unmarshal(bs, sn))
+		return nil, sn
+	}
+	sr := new(SubscriptionResponse)
+	assert.NoError(t, json.Unmarshal(bs, sr))
+	return sr, nil
+}
+
+func readResponse(t *testing.T, c *websocket.Conn) *SubscriptionResponse {
+	sr, sn := readMessage(t, c, time.Second)
+	if sr == nil {
+		t.Fatalf("response is expected notification=%+v", sn)
+	}
+	return sr
+}
+
+func readNotifications(t *testing.T, c *websocket.Conn, n int) []*SubscriptionNotification {
+	var sns []*SubscriptionNotification
+	for len(sns) < n {
+		sr, sn := readMessage(t, c, time.Second)
+		if sn == nil {
+			t.Fatalf("notification is expected response=%+v", sr)
+		}
+		sns = append(sns, sn)
+	}
+	return sns
+}
+
+func assertNoMessage(t *testing.T, c *websocket.Conn) {
+	sr, sn := readMessage(t, c, 200*time.Millisecond)
+	assert.Nil(t, sr)
+	assert.Nil(t, sn)
+}
+
+func cursorOf(h int64, tx, ev int32) EventCursor {
+	return EventCursor{
+		Height:     common.HexInt64{Value: h},
+		TxIndex:    common.HexInt32{Value: tx},
+		EventIndex: common.HexInt32{Value: ev},
+	}
+}
+
+func filtersOf(idxs ...int32) []common.HexInt32 {
+	fs := make([]common.HexInt32, len(idxs))
+	for i, idx := range idxs {
+		fs[i].Value = idx
+	}
+	return fs
+}
+
+func TestSubscriptionSession_MultipleFilters(t *testing.T) {
+	c, closer := dialSubscriptionSession(t, newTestEventChain())
+	defer closer()
+
+	// the first event matches with two filters.
+	assert.NoError(t, c.WriteJSON(&SubscriptionRequest{
+		Method: SubscriptionMethodSubscribe,
+		Filters: []*EventFilter{
+			{Signature: testTransferSig},
+			{Signature: testApprovalSig},
+			{Signature: testTransferSig, Addr: testScore},
+		},
+	}))
+	sr := readResponse(t, c)
+	assert.Equal(t, 0, sr.Code)
+	assert.Equal(t, SubscriptionMethodSubscribe, sr.Method)
+	assert.EqualValues(t, 1, sr.ID.Value)
+
+	sns := readNotifications(t, c, 5)
+	expected := []struct {
+		cursor  EventCursor
+		filters []common.HexInt32
+	}{
+		{cursorOf(1, 0, 0), filtersOf(0, 2)},
+		{cursorOf(1, 0, 1), filtersOf(1)},
+		{cursorOf(1, 1, 0), filtersOf(1)},
+		{cursorOf(2, 0, 0), filtersOf(0, 2)},
+		{cursorOf(2, 0, 1), filtersOf(0, 2)},
+	}
+	for i, sn := range sns {
+		assert.EqualValues(t, 1, sn.ID.Value)
+		assert.Equal(t, expected[i].cursor, sn.Cursor)
+		assert.Equal(t, expected[i].filters, sn.Filters)
+		assert.Equal(t, fmt.Sprintf("block%d", sn.Cursor.Height.Value), string(sn.Hash))
+	}
+
+	// notifications of the second subscription have its own id, and there
+	// is no more notification for the first one.
+	assert.NoError(t, c.WriteJSON(&SubscriptionRequest{
+		Method:  SubscriptionMethodSubscribe,
+		Height:  common.HexInt64{Value: 2},
+		Filters: []*EventFilter{{Signature: testTransferSig}},
+	}))
+	sr = readResponse(t, c)
+	assert.Equal(t, 0, sr.Code)
+	assert.EqualValues(t, 2, sr.ID.Value)
+	sns = readNotifications(t, c, 2)
+	for _, sn := range sns {
+		assert.EqualValues(t, 2, sn.ID.Value)
+		assert.EqualValues(t, 2, sn.Cursor.Height.Value)
+	}
+
+	assert.NoError(t, c.WriteJSON(&SubscriptionRequest{
+		Method: SubscriptionMethodUnsubscribe,
+		ID:     common.HexInt32{Value: 1},
+	}))
+	sr = readResponse(t, c)
+	assert.Equal(t, 0, sr.Code)
+	assert.Equal(t, SubscriptionMethodUnsubscribe, sr.Method)
+	assert.EqualValues(t, 1, sr.ID.Value)
+
+	assert.NoError(t, c.WriteJSON(&SubscriptionRequest{
+		Method: SubscriptionMethodUnsubscribe,
+		ID:     common.HexInt32{Value: 1},
+	}))
+	sr = readResponse(t, c)
+	assert.NotEqual(t, 0, sr.Code)
+
+	// invalid filters are rejected.
+	assert.NoError(t, c.WriteJSON(&SubscriptionRequest{
+		Method: SubscriptionMethodSubscribe,
+	}))
+	sr = readResponse(t, c)
+	assert.NotEqual(t, 0, sr.Code)
+	assert.NoError(t, c.WriteJSON(&SubscriptionRequest{
+		Method:  SubscriptionMethodSubscribe,
+		Filters: []*EventFilter{{Signature: "Transfer"}},
+	}))
+	sr = readResponse(t, c)
+	assert.NotEqual(t, 0, sr.Code)
+	assertNoMessage(t, c)
+}
+
+func TestSubscriptionSession_ResumeWithCursor(t *testing.T) {
+	chain := newTestEventChain()
+	filters := func() []*EventFilter {
+		return []*EventFilter{
+			{Signature: testTransferSig},
+			{Signature: testApprovalSig},
+		}
+	}
+
+	c, closer := dialSubscriptionSession(t, chain)
+	defer closer()
+	assert.NoError(t, c.WriteJSON(&SubscriptionRequest{
+		Method:  SubscriptionMethodSubscribe,
+		Filters: filters(),
+	}))
+	assert.Equal(t, 0, readResponse(t, c).Code)
+	all := readNotifications(t, c, 5)
+	assertNoMessage(t, c)
+
+	// resuming from each notification gives all the following ones
+	// without duplicates or gaps.
+	for i, sn := range all {
+		c, closer := dialSubscriptionSession(t, chain)
+		cursor := sn.Cursor
+		assert.NoError(t, c.WriteJSON(&SubscriptionRequest{
+			Method:  SubscriptionMethodSubscribe,
+			Cursor:  &cursor,
+			Filters: filters(),
+		}))
+		assert.Equal(t, 0, readResponse(t, c).Code)
+		rest := readNotifications(t, c, len(all)-i-1)
+		for j, r := range rest {
+			assert.Equal(t, all[i+j+1].Cursor, r.Cursor)
+			assert.Equal(t, all[i+j+1].Filters, r.Filters)
+		}
+		assertNoMessage(t, c)
+		closer()
+	}
+}
@@ -115,6 +115,7 @@ func (cc *callContext) pushFrame(handler ContractHandler, limit *big.Int) *callF
 		frame.snapshot = cc.GetSnapshot()
 	}
 	cc.frame = frame
+	traceFrameEnter(cc.log, handler, limit)
 	return frame
 }
 
@@ -244,23 +245,27 @@ func (cc *callContext) cleanUpFrames(target *callFrame, err error) {
 		if ach, ok := frame.handler.(AsyncContractHandler); ok {
 			achs = append(achs, ach)
 		}
+		cc.log.OnFrameExit(err, frame.getStepUsed(), nil)
 		if frame == target {
 			break
 		}
 	}
 	l.Unlock()
 
-	if !target.isQuery {
-		cc.Reset(target.snapshot)
-	}
 	for _, h := range achs {
 		h.Dispose()
 	}
 
+	// Contracts in the process (ex. Go contracts) may keep running until
+	// they are killed, so it resets the state after that.
 	if cc.executor != nil {
 		cc.executor.Kill()
 		cc.executor = nil
 	}
+
+	if !target.isQuery {
+		cc.Reset(target.snapshot)
+	}
 }
 
 func (cc *callContext) handleResult(target *callFrame, status error, result *codec.TypedObj, addr module.Address) bool {
@@ -274,6 +279,7 @@ func (cc *callContext) handleResult(target *callFrame, status error, result *cod
 	if current == nil {
 		return false
 	}
+	cc.log.OnFrameExit(status, current.getStepUsed(), addr)
 
 	if ach, ok := current.handler.(AsyncContractHandler); ok {
 		ach.Dispose()
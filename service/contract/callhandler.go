@@ -380,7 +380,11 @@ func (h *CallHandler) SetValue(key []byte, value []byte) ([]byte, error) {
 			"DeleteValueInQuery")
 	}
 	if h.as != nil {
-		return h.as.SetValue(key, value)
+		old, err := h.as.SetValue(key, value)
+		if err == nil {
+			h.log.OnStorageWrite(key, value, old)
+		}
+		return old, err
 	} else {
 		return nil, errors.CriticalUnknownError.Errorf(
 			"SetValue: No Account(%s) exists", h.to)
@@ -393,7 +397,11 @@ func (h *CallHandler) DeleteValue(key []byte) ([]byte, error) {
 			"DeleteValueInQuery")
 	}
 	if h.as != nil {
-		return h.as.DeleteValue(key)
+		old, err := h.as.DeleteValue(key)
+		if err == nil {
+			h.log.OnStorageWrite(key, nil, old)
+		}
+		return old, err
 	} else {
 		return nil, errors.CriticalUnknownError.Errorf(
 			"DeleteValue: No Account(%s) exists", h.to)
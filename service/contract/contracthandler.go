@@ -72,3 +72,25 @@ func (h *CommonHandler) Logger() log.Logger {
 func (h *CommonHandler) ResetLogger(logger log.Logger) {
 	h.log = trace.LoggerOf(logger)
 }
+
+// traceFrameEnter notifies a new frame for the handler to the call tracer.
+func traceFrameEnter(logger *trace.Logger, handler ContractHandler, limit *big.Int) {
+	switch h := handler.(type) {
+	case *TransferAndCallHandler:
+		logger.OnFrameEnter(trace.FrameTypeCall, h.from, h.to, h.value, h.name, limit)
+	case *CallHandler:
+		logger.OnFrameEnter(trace.FrameTypeCall, h.from, h.to, h.value, h.name, limit)
+	case *DeployHandler:
+		logger.OnFrameEnter(trace.FrameTypeDeploy, h.from, h.to, h.value, "", limit)
+	case *AcceptHandler:
+		logger.OnFrameEnter(trace.FrameTypeAccept, h.from, h.to, h.value, "", limit)
+	case *TransferHandler:
+		logger.OnFrameEnter(trace.FrameTypeTransfer, h.from, h.to, h.value, "", limit)
+	case *TransferAndMessageHandler:
+		logger.OnFrameEnter(trace.FrameTypeTransfer, h.from, h.to, h.value, "", limit)
+	case *patchHandler:
+		logger.OnFrameEnter(trace.FrameTypePatch, h.from, h.to, h.value, "", limit)
+	default:
+		logger.OnFrameEnter(trace.FrameTypeCall, nil, nil, nil, "", limit)
+	}
+}
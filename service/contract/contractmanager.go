@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/icon-project/goloop/service/eeproxy"
 	"github.com/icon-project/goloop/service/scoreapi"
 	"github.com/icon-project/goloop/service/scoreresult"
 
@@ -354,6 +355,20 @@ func storeJava(path string, code []byte, log log.Logger) error {
 	return nil
 }
 
+func storeGo(path string, code []byte, log log.Logger) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err = os.MkdirAll(path, 0755); err != nil {
+			return errors.WithCode(err, errors.CriticalIOError)
+		}
+	}
+	sPath := filepath.Join(path, eeproxy.GoCodeFile)
+	if err := ioutil.WriteFile(sPath, code, 0644); err != nil {
+		_ = os.RemoveAll(sPath)
+		return errors.WithCode(err, errors.CriticalIOError)
+	}
+	return nil
+}
+
 func storeByEEType(e state.EEType, path string, code []byte, log log.Logger) error {
 	var err error
 	switch e {
@@ -361,6 +376,8 @@ func storeByEEType(e state.EEType, path string, code []byte, log log.Logger) err
 		err = storePython(path, code, log)
 	case state.JavaEE:
 		err = storeJava(path, code, log)
+	case state.GoEE:
+		err = storeGo(path, code, log)
 	default:
 		err = scoreresult.Errorf(module.StatusInvalidParameter,
 			"UnexpectedEEType(%v)\n", e)
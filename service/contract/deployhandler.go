@@ -120,6 +120,11 @@ func (h *DeployHandler) ExecuteSync(cc CallContext) (error, *codec.TypedObj, mod
 
 	h.log.TSystemf("DEPLOY start to=%s", h.to)
 
+	if h.eeType == state.GoEE && cc.Revision() < module.Revision9 {
+		return scoreresult.InvalidParameterError.Errorf(
+			"UnsupportedContentType(%s)", h.contentType), nil, nil
+	}
+
 	update := false
 	info := cc.GetInfo()
 	if info == nil {
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/scoredb"
 	"github.com/icon-project/goloop/service/scoreresult"
 	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/txresult"
 )
 
 type Patch struct {
@@ -77,6 +79,59 @@ func (h *patchHandler) handleSkipTransaction(cc CallContext) error {
 	return nil
 }
 
+// handleDoubleSign records the evidence of double signing in the world
+// state, and emits the event for it. Evidences are recorded only once.
+// The signer is verified with the validators of the block, which are known
+// only for the heights after the last change of the validators. Evidences
+// older than module.EvidenceExpiry blocks are rejected.
+func (h *patchHandler) handleDoubleSign(cc CallContext) error {
+	decode := cc.PatchDecoder()
+	if decode == nil {
+		h.log.Warn("PatchHandler: patch decoder isn't set")
+		return scoreresult.InvalidParameterError.New("PatchDecoderIsNil")
+	}
+	pd, err := decode(h.patch.Type, h.patch.Data)
+	if err != nil {
+		h.log.Warnf("PatchHandler: decode fail err=%+v", err)
+		return scoreresult.InvalidParameterError.Wrap(err, "DecodeFail")
+	}
+	p := pd.(module.Evidence)
+	if p.Height() < 1 || p.Height() > cc.BlockHeight() {
+		return scoreresult.InvalidParameterError.Errorf("InvalidHeight(bh=%d,ph=%d)",
+			cc.BlockHeight(), p.Height())
+	}
+	if p.Height()+module.EvidenceExpiry < cc.BlockHeight() {
+		return scoreresult.InvalidParameterError.Errorf("ExpiredEvidence(bh=%d,ph=%d)",
+			cc.BlockHeight(), p.Height())
+	}
+	as := cc.GetAccountState(state.SystemID)
+	vh := scoredb.NewVarDB(as, state.VarValidatorsHeight).Int64()
+	if vh == 0 || p.Height() < vh {
+		return scoreresult.InvalidParameterError.Errorf(
+			"UnknownValidators(height=%d,validators=%d)", p.Height(), vh)
+	}
+	nid := scoredb.NewVarDB(as, state.VarNetwork).Int64()
+	if err := p.VerifyFor(cc.GetValidatorState().GetSnapshot(), int(nid)); err != nil {
+		h.log.Warnf("FailToVerifyEvidence(err=%v)", err)
+		return scoreresult.InvalidParameterError.Wrap(err, "VerifyEvidenceFail")
+	}
+	evidences := scoredb.NewDictDB(as, state.VarEvidences, 1)
+	if evidences.Get(p.Hash()) != nil {
+		return scoreresult.InvalidParameterError.Errorf(
+			"AlreadyRecorded(hash=%#x)", p.Hash())
+	}
+	if err := evidences.Set(p.Hash(), p.Height()); err != nil {
+		return err
+	}
+	cc.OnEvent(state.SystemAddress,
+		[][]byte{[]byte(txresult.EventLogDoubleSign), p.Signer().Bytes()},
+		[][]byte{intconv.Int64ToBytes(p.Height()), p.Hash()},
+	)
+	h.log.Warnf("PatchHandler: DOUBLE SIGN signer=%s height=%d hash=%#x",
+		p.Signer(), p.Height(), p.Hash())
+	return nil
+}
+
 func (h *patchHandler) ExecuteSync(cc CallContext) (error, *codec.TypedObj, module.Address) {
 	vs := cc.GetValidatorState()
 	if idx := vs.IndexOf(h.from); idx < 0 {
@@ -93,6 +148,13 @@ func (h *patchHandler) ExecuteSync(cc CallContext) (error, *codec.TypedObj, modu
 	case module.PatchTypeSkipTransaction:
 		s := h.handleSkipTransaction(cc)
 		return s, nil, nil
+	case module.PatchTypeDoubleSign:
+		if cc.Revision() < module.Revision10 {
+			return scoreresult.InvalidParameterError.Errorf(
+				"UnknownPatchType(%s)", h.patch.Type), nil, nil
+		}
+		s := h.handleDoubleSign(cc)
+		return s, nil, nil
 	default:
 		return scoreresult.InvalidParameterError.Errorf("InvalidDataType(%s)", h.patch.Type), nil, nil
 	}
@@ -117,7 +179,7 @@ func ParsePatchData(data []byte) (*Patch, error) {
 			"InvalidJSON(json=%s)", data)
 	}
 	switch p.Type {
-	case module.PatchTypeSkipTransaction:
+	case module.PatchTypeSkipTransaction, module.PatchTypeDoubleSign:
 		// do nothing
 	default:
 		return nil, scoreresult.InvalidParameterError.Errorf(
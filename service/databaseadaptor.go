@@ -35,6 +35,10 @@ func (da *databaseAdaptor) Close() error {
 	return nil
 }
 
+func (da *databaseAdaptor) NewBatch() db.Batch {
+	return db.NewBucketBatch(da)
+}
+
 func (da *databaseAdaptor) OnRead(size int) {
 	atomic.AddInt32(&da.size, int32(size))
 }
@@ -75,6 +79,10 @@ func (ba *bucketAdaptor) Delete(key []byte) error {
 	panic("Now allowed")
 }
 
+func (ba *bucketAdaptor) NewIterator(start, limit []byte) db.Iterator {
+	return ba.bucket.NewIterator(start, limit)
+}
+
 func newBucketAdaptor(da *databaseAdaptor, bk db.Bucket) *bucketAdaptor {
 	return &bucketAdaptor{
 		database: da,
@@ -5,9 +5,11 @@ import (
 	"github.com/icon-project/goloop/common/log"
 )
 
+// AllocEngines allocates the engines of the names. The Go engine runs in the
+// process, so it's always allocated for the revisions enabling Go contracts.
 func AllocEngines(l log.Logger, names ...string) ([]Engine, error) {
 	l.Infof("Allocate Engines:%s", names)
-	engines := make([]Engine, len(names))
+	engines := make([]Engine, len(names), len(names)+1)
 	for i, name := range names {
 		switch name {
 		case "python":
@@ -27,5 +29,10 @@ func AllocEngines(l log.Logger, names ...string) ([]Engine, error) {
 				"IllegalEngineName(name=%s)", name)
 		}
 	}
+	if engine, err := NewGoEE(l); err != nil {
+		return nil, err
+	} else {
+		engines = append(engines, engine)
+	}
 	return engines, nil
 }
@@ -0,0 +1,543 @@
+package eeproxy
+
+import (
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/trace"
+)
+
+const (
+	// GoCodeFile is the name of the file having the name of the Go
+	// contract in the contract directory.
+	GoCodeFile = "code.name"
+)
+
+// GoContract is a contract written in Go. It runs in the process with the
+// Go engine. Like Java contracts, its API should have the install method
+// "<init>".
+type GoContract interface {
+	GetAPI() *scoreapi.Info
+	Invoke(ctx GoContext, method string, params []interface{}) (interface{}, error)
+}
+
+// GoContext is the context of an invocation of the Go contract. Its store
+// is for scoredb. Once the invocation is canceled (ex. by the execution
+// timeout), Done is closed and all other methods fail, so the contract
+// should return on them.
+type GoContext interface {
+	scoredb.StateStore
+	Address() module.Address
+	From() module.Address
+	Value() *big.Int
+	IsQuery() bool
+	Info() map[string]interface{}
+	GetBalance(addr module.Address) *big.Int
+	OnEvent(indexed, data [][]byte) error
+	Call(to module.Address, value *big.Int, method string, params ...interface{}) (interface{}, error)
+	Logger() log.Logger
+	Done() <-chan struct{}
+}
+
+var goContracts = struct {
+	sync.RWMutex
+	factories map[string]func() GoContract
+}{
+	factories: make(map[string]func() GoContract),
+}
+
+// RegisterGoContract registers the factory of the Go contract with the
+// name. The name is used as the content of the deploy transaction. It's
+// expected to be called in init() of the package implementing it.
+func RegisterGoContract(name string, factory func() GoContract) {
+	goContracts.Lock()
+	defer goContracts.Unlock()
+	if _, ok := goContracts.factories[name]; ok {
+		log.Panicf("DuplicateGoContract(name=%s)", name)
+	}
+	goContracts.factories[name] = factory
+}
+
+func newGoContract(name string) (GoContract, error) {
+	goContracts.RLock()
+	defer goContracts.RUnlock()
+	if factory, ok := goContracts.factories[name]; ok {
+		return factory(), nil
+	}
+	return nil, scoreresult.ContractNotFoundError.Errorf(
+		"GoContractNotFound(name=%s)", name)
+}
+
+type goExecutionEngine struct {
+	lock   sync.Mutex
+	names  map[string]string
+	logger log.Logger
+}
+
+func (e *goExecutionEngine) Type() string {
+	return string(state.GoEE)
+}
+
+func (e *goExecutionEngine) Init(net, addr string) error {
+	return nil
+}
+
+func (e *goExecutionEngine) SetInstances(n int) error {
+	return nil
+}
+
+func (e *goExecutionEngine) OnAttach(uid string) bool {
+	return false
+}
+
+func (e *goExecutionEngine) OnEnd(uid string) bool {
+	return false
+}
+
+func (e *goExecutionEngine) Kill(uid string) (bool, error) {
+	return false, nil
+}
+
+func (e *goExecutionEngine) OnConnect(conn ipc.Connection, version uint16) error {
+	return errors.UnsupportedError.New("GoEngineDoesNotConnect")
+}
+
+func (e *goExecutionEngine) OnClose(conn ipc.Connection) bool {
+	return false
+}
+
+func (e *goExecutionEngine) newProxy() Proxy {
+	return &goProxy{engine: e}
+}
+
+// contractOf returns the contract stored in the directory.
+func (e *goExecutionEngine) contractOf(code string) (GoContract, error) {
+	e.lock.Lock()
+	name, ok := e.names[code]
+	e.lock.Unlock()
+	if !ok {
+		bs, err := ioutil.ReadFile(filepath.Join(code, GoCodeFile))
+		if err != nil {
+			return nil, errors.CriticalIOError.Wrapf(err,
+				"FailToReadGoCode(path=%s)", code)
+		}
+		name = string(bs)
+		e.lock.Lock()
+		e.names[code] = name
+		e.lock.Unlock()
+	}
+	return newGoContract(name)
+}
+
+func NewGoEE(l log.Logger) (Engine, error) {
+	return &goExecutionEngine{
+		names:  make(map[string]string),
+		logger: l,
+	}, nil
+}
+
+// localEngine is an engine running contracts in the process. Instead of
+// connections from the executors, it makes a proxy for each executor.
+type localEngine interface {
+	Engine
+	newProxy() Proxy
+}
+
+type goCallResult struct {
+	status error
+	steps  *big.Int
+	result *codec.TypedObj
+}
+
+type goFrame struct {
+	lock    sync.RWMutex
+	killed  bool
+	done    chan struct{}
+	ctx     CallContext
+	log     *trace.Logger
+	isQuery bool
+	from    module.Address
+	to      module.Address
+	value   *big.Int
+	limit   *big.Int
+	used    *big.Int
+	costs   map[string]*big.Int
+	info    map[string]interface{}
+	result  chan *goCallResult
+}
+
+// enter checks the frame is alive, and keeps it alive until leave, so
+// the context isn't changed after the frame is killed.
+func (f *goFrame) enter() error {
+	f.lock.RLock()
+	if f.killed {
+		f.lock.RUnlock()
+		return errors.ExecutionFailError.New("ProxyIsKilled")
+	}
+	return nil
+}
+
+func (f *goFrame) leave() {
+	f.lock.RUnlock()
+}
+
+func (f *goFrame) kill() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if !f.killed {
+		f.killed = true
+		close(f.done)
+		close(f.result)
+	}
+}
+
+func (f *goFrame) isKilled() bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.killed
+}
+
+func (f *goFrame) cost(t string, n int) *big.Int {
+	v := new(big.Int)
+	if c, ok := f.costs[t]; ok {
+		v.Mul(c, big.NewInt(int64(n)))
+	}
+	return v
+}
+
+// apply applies steps of the operation with the base cost and the cost
+// for each byte.
+func (f *goFrame) apply(base, perByte string, n int) error {
+	steps := f.cost(base, 1)
+	steps.Add(steps, f.cost(perByte, n))
+	return f.applySteps(steps)
+}
+
+func (f *goFrame) applySteps(steps *big.Int) error {
+	f.used.Add(f.used, steps)
+	if f.used.Cmp(f.limit) > 0 {
+		f.used.Set(f.limit)
+		return scoreresult.ErrOutOfStep
+	}
+	return nil
+}
+
+func (f *goFrame) GetValue(key []byte) ([]byte, error) {
+	if err := f.enter(); err != nil {
+		return nil, err
+	}
+	defer f.leave()
+	value, err := f.ctx.GetValue(key)
+	if err != nil {
+		return nil, err
+	}
+	f.log.TSystemf("GETVALUE key=<%x> value=<%x>", key, value)
+	if err := f.apply(state.StepTypeDefaultGet, state.StepTypeGet, len(value)); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (f *goFrame) SetValue(key []byte, value []byte) ([]byte, error) {
+	if err := f.enter(); err != nil {
+		return nil, err
+	}
+	defer f.leave()
+	if err := f.apply(state.StepTypeDefaultSet, state.StepTypeSet, len(value)); err != nil {
+		return nil, err
+	}
+	old, err := f.ctx.SetValue(key, value)
+	f.log.TSystemf("SETVALUE key=<%x> value=<%x> old=<%x>", key, value, old)
+	return old, err
+}
+
+func (f *goFrame) DeleteValue(key []byte) ([]byte, error) {
+	if err := f.enter(); err != nil {
+		return nil, err
+	}
+	defer f.leave()
+	old, err := f.ctx.DeleteValue(key)
+	if err != nil {
+		return nil, err
+	}
+	f.log.TSystemf("DELETE key=<%x> old=<%x>", key, old)
+	if err := f.apply(state.StepTypeDefaultDelete, state.StepTypeDelete, len(old)); err != nil {
+		return nil, err
+	}
+	return old, nil
+}
+
+func (f *goFrame) Address() module.Address {
+	return f.to
+}
+
+func (f *goFrame) From() module.Address {
+	return f.from
+}
+
+func (f *goFrame) Value() *big.Int {
+	return f.value
+}
+
+func (f *goFrame) IsQuery() bool {
+	return f.isQuery
+}
+
+func (f *goFrame) Info() map[string]interface{} {
+	return f.info
+}
+
+func (f *goFrame) GetBalance(addr module.Address) *big.Int {
+	if err := f.enter(); err != nil {
+		return new(big.Int)
+	}
+	defer f.leave()
+	return f.ctx.GetBalance(addr)
+}
+
+func (f *goFrame) OnEvent(indexed, data [][]byte) error {
+	size := 0
+	for _, l := range [][][]byte{indexed, data} {
+		for _, v := range l {
+			size += len(v)
+		}
+	}
+	if err := f.enter(); err != nil {
+		return err
+	}
+	defer f.leave()
+	if err := f.apply(state.StepTypeEventLogBase, state.StepTypeEventLog, size); err != nil {
+		return err
+	}
+	f.ctx.OnEvent(f.to, indexed, data)
+	return nil
+}
+
+func (f *goFrame) Call(to module.Address, value *big.Int, method string, params ...interface{}) (interface{}, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	if params == nil {
+		params = []interface{}{}
+	}
+	po, err := common.EncodeAny(params)
+	if err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidCallParams")
+	}
+	// The context drops the call if the frame is killed.
+	if err := f.enter(); err != nil {
+		return nil, err
+	}
+	f.leave()
+	limit := new(big.Int).Sub(f.limit, f.used)
+	f.log.Tracef("GoProxy.OnCall from=%v to=%v value=%v steplimit=%v method=%s",
+		f.to, to, value, limit, method)
+	f.ctx.OnCall(f.to, to, value, limit, method, po)
+	r, ok := <-f.result
+	if !ok {
+		return nil, errors.ExecutionFailError.New("ProxyIsKilled")
+	}
+	if err := f.applySteps(r.steps); err != nil {
+		return nil, err
+	}
+	if r.status != nil {
+		return nil, r.status
+	}
+	return common.DecodeAny(r.result)
+}
+
+func (f *goFrame) Logger() log.Logger {
+	return f.log
+}
+
+func (f *goFrame) Done() <-chan struct{} {
+	return f.done
+}
+
+func (f *goFrame) invoke(c GoContract, method string, params *codec.TypedObj) (status error, result *codec.TypedObj) {
+	defer func() {
+		if obj := recover(); obj != nil {
+			f.log.Warnf("GoProxy.Invoke method=%s panic=%+v", method, obj)
+			status = scoreresult.UnknownFailureError.Errorf("Recover obj=%+v", obj)
+			result = nil
+		}
+	}()
+
+	var ps []interface{}
+	if params != nil {
+		obj, err := common.DecodeAny(params)
+		if err != nil {
+			return scoreresult.InvalidParameterError.Wrap(err, "InvalidParams"), nil
+		}
+		if obj != nil {
+			var ok bool
+			if ps, ok = obj.([]interface{}); !ok {
+				return scoreresult.InvalidParameterError.Errorf(
+					"InvalidParams(type=%T)", obj), nil
+			}
+		}
+	}
+	size := 0
+	if params != nil {
+		size = len(codec.BC.MustMarshalToBytes(params))
+	}
+	if err := f.apply(state.StepTypeDefault, state.StepTypeInput, size); err != nil {
+		return err, nil
+	}
+	ret, err := c.Invoke(f, method, ps)
+	if err != nil {
+		return scoreresult.Validate(err), nil
+	}
+	if ret == nil {
+		return nil, codec.Nil
+	}
+	obj, err := common.EncodeAny(ret)
+	if err != nil {
+		return scoreresult.UnknownFailureError.Wrap(err, "InvalidResult"), nil
+	}
+	return nil, obj
+}
+
+// goProxy runs Go contracts for an executor. Each invocation runs in its
+// own goroutine, and results of its calls are delivered with SendResult as
+// the external engines do.
+type goProxy struct {
+	engine *goExecutionEngine
+
+	lock   sync.Mutex
+	frames []*goFrame
+	killed bool
+}
+
+func (p *goProxy) pushFrame(f *goFrame) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.killed {
+		return errors.ExecutionFailError.New("ProxyIsKilled")
+	}
+	p.frames = append(p.frames, f)
+	return nil
+}
+
+func (p *goProxy) popFrame() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if n := len(p.frames); n > 0 {
+		p.frames = p.frames[:n-1]
+	}
+}
+
+func (p *goProxy) Invoke(
+	ctx CallContext, code string, isQuery bool,
+	from, to module.Address, value, limit *big.Int, method string, params *codec.TypedObj,
+	eid int, cs *CodeState,
+) error {
+	logger := trace.LoggerOf(ctx.Logger())
+	logger.Tracef("GoProxy[%p].Invoke code=%s query=%v from=%v to=%v value=%v limit=%v method=%s eid=%d",
+		p, code, isQuery, from, to, value, limit, method, eid)
+
+	c, err := p.engine.contractOf(code)
+	if err != nil {
+		return err
+	}
+	info, _ := common.DecodeAny(ctx.GetInfo())
+	f := &goFrame{
+		ctx:     ctx,
+		log:     logger,
+		isQuery: isQuery,
+		from:    from,
+		to:      to,
+		value:   value,
+		limit:   new(big.Int).Set(limit),
+		used:    new(big.Int),
+		costs:   make(map[string]*big.Int),
+		done:    make(chan struct{}),
+		result:  make(chan *goCallResult, 1),
+	}
+	if m, ok := info.(map[string]interface{}); ok {
+		f.info = m
+		if costs, ok := m[state.InfoStepCosts].(map[string]interface{}); ok {
+			for k, v := range costs {
+				if i, ok := v.(*common.HexInt); ok {
+					f.costs[k] = &i.Int
+				}
+			}
+		}
+	}
+	if err := p.pushFrame(f); err != nil {
+		return err
+	}
+	go func() {
+		status, result := f.invoke(c, method, params)
+		p.popFrame()
+		if f.isKilled() {
+			// the result of the killed frame is discarded.
+			return
+		}
+		logger.Tracef("GoProxy[%p].OnResult status=%v steps=%v", p, status, f.used)
+		ctx.OnResult(status, f.used, result)
+	}()
+	return nil
+}
+
+func (p *goProxy) SendResult(ctx CallContext, status error, steps *big.Int, result *codec.TypedObj, eid int, last int) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.killed {
+		return errors.ExecutionFailError.New("ProxyIsKilled")
+	}
+	n := len(p.frames)
+	if n == 0 {
+		return errors.InvalidStateError.New("Empty frame")
+	}
+	if result == nil {
+		result = codec.Nil
+	}
+	f := p.frames[n-1]
+	if err := f.enter(); err != nil {
+		return err
+	}
+	defer f.leave()
+	f.result <- &goCallResult{status, steps, result}
+	return nil
+}
+
+func (p *goProxy) GetAPI(ctx CallContext, code string) error {
+	c, err := p.engine.contractOf(code)
+	go func() {
+		if err != nil {
+			ctx.OnAPI(err, nil)
+			return
+		}
+		ctx.OnAPI(nil, c.GetAPI())
+	}()
+	return nil
+}
+
+func (p *goProxy) Release() {
+	// do nothing
+}
+
+func (p *goProxy) Kill() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !p.killed {
+		p.killed = true
+		for _, f := range p.frames {
+			f.kill()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package eeproxy
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+type testCounter struct{}
+
+func (c *testCounter) GetAPI() *scoreapi.Info {
+	return scoreapi.NewInfo([]*scoreapi.Method{
+		{
+			Type:  scoreapi.Function,
+			Name:  "<init>",
+			Flags: scoreapi.FlagExternal,
+		},
+	})
+}
+
+func (c *testCounter) Invoke(ctx GoContext, method string, params []interface{}) (interface{}, error) {
+	count := scoredb.NewVarDB(ctx, "count")
+	switch method {
+	case "increase":
+		if err := count.Set(count.Int64() + 1); err != nil {
+			return nil, err
+		}
+		return count.Int64(), nil
+	case "relay":
+		to := params[0].(module.Address)
+		return ctx.Call(to, nil, "increase")
+	case "spin":
+		for {
+			if err := count.Set(count.Int64() + 1); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, scoreresult.MethodNotFoundError.Errorf("UnknownMethod(%s)", method)
+	}
+}
+
+func init() {
+	RegisterGoContract("test.counter", func() GoContract {
+		return &testCounter{}
+	})
+}
+
+type testCallResult struct {
+	status error
+	steps  *big.Int
+	result *codec.TypedObj
+}
+
+type testCall struct {
+	to     module.Address
+	limit  *big.Int
+	method string
+}
+
+type testCallContext struct {
+	store  map[string][]byte
+	result chan *testCallResult
+	call   chan *testCall
+}
+
+func (t *testCallContext) GetValue(key []byte) ([]byte, error) {
+	return t.store[string(key)], nil
+}
+
+func (t *testCallContext) SetValue(key []byte, value []byte) ([]byte, error) {
+	old := t.store[string(key)]
+	t.store[string(key)] = value
+	return old, nil
+}
+
+func (t *testCallContext) DeleteValue(key []byte) ([]byte, error) {
+	old := t.store[string(key)]
+	delete(t.store, string(key))
+	return old, nil
+}
+
+func (t *testCallContext) GetInfo() *codec.TypedObj {
+	return common.MustEncodeAny(map[string]interface{}{
+		"StepCosts": map[string]interface{}{
+			"default": 100,
+			"set":     10,
+			"input":   1,
+		},
+	})
+}
+
+func (t *testCallContext) GetBalance(addr module.Address) *big.Int {
+	return new(big.Int)
+}
+
+func (t *testCallContext) OnEvent(addr module.Address, indexed, data [][]byte) {
+}
+
+func (t *testCallContext) OnResult(status error, steps *big.Int, result *codec.TypedObj) {
+	t.result <- &testCallResult{status, steps, result}
+}
+
+func (t *testCallContext) OnCall(from, to module.Address, value, limit *big.Int, method string, params *codec.TypedObj) {
+	t.call <- &testCall{to, limit, method}
+}
+
+func (t *testCallContext) OnAPI(status error, info *scoreapi.Info) {
+}
+
+func (t *testCallContext) SetCode(code []byte) error {
+	return nil
+}
+
+func (t *testCallContext) GetObjGraph(bool) (int, []byte, []byte, error) {
+	return 0, nil, nil, nil
+}
+
+func (t *testCallContext) SetObjGraph(flags bool, nextHash int, objGraph []byte) error {
+	return nil
+}
+
+func (t *testCallContext) Logger() log.Logger {
+	return log.GlobalLogger()
+}
+
+func newTestCallContext() *testCallContext {
+	return &testCallContext{
+		store:  make(map[string][]byte),
+		result: make(chan *testCallResult, 1),
+		call:   make(chan *testCall, 1),
+	}
+}
+
+func newTestGoCode(t *testing.T, name string) string {
+	dir, err := ioutil.TempDir("", "goee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, GoCodeFile), []byte(name), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestGoEE_Invoke(t *testing.T) {
+	code := newTestGoCode(t, "test.counter")
+	defer os.RemoveAll(code)
+
+	engine, _ := NewGoEE(log.GlobalLogger())
+	p := engine.(localEngine).newProxy()
+	ctx := newTestCallContext()
+	addr := common.NewAddressFromString("cx0000000000000000000000000000000000000100")
+
+	for i := int64(1); i <= 2; i++ {
+		err := p.Invoke(ctx, code, false, nil, addr, new(big.Int), big.NewInt(1000),
+			"increase", common.MustEncodeAny([]interface{}{}), 0, nil)
+		if err != nil {
+			t.Fatalf("fail to invoke err=%+v", err)
+		}
+		r := <-ctx.result
+		if r.status != nil {
+			t.Fatalf("unexpected status=%+v", r.status)
+		}
+		if v := common.MustDecodeAny(r.result).(*common.HexInt); v.Int64() != i {
+			t.Errorf("unexpected result=%s exp=%d", v, i)
+		}
+		// default + set * 1 byte + input * 3 bytes of the empty list
+		if r.steps.Int64() != 113 {
+			t.Errorf("unexpected steps=%s", r.steps)
+		}
+	}
+
+	err := p.Invoke(ctx, code, false, nil, addr, new(big.Int), big.NewInt(50),
+		"increase", nil, 0, nil)
+	if err != nil {
+		t.Fatalf("fail to invoke err=%+v", err)
+	}
+	if r := <-ctx.result; !scoreresult.OutOfStepError.Equals(r.status) {
+		t.Errorf("unexpected status=%+v", r.status)
+	}
+}
+
+func TestGoEE_CallAndSendResult(t *testing.T) {
+	code := newTestGoCode(t, "test.counter")
+	defer os.RemoveAll(code)
+
+	engine, _ := NewGoEE(log.GlobalLogger())
+	p := engine.(localEngine).newProxy()
+	ctx := newTestCallContext()
+	addr := common.NewAddressFromString("cx0000000000000000000000000000000000000100")
+	other := common.NewAddressFromString("cx0000000000000000000000000000000000000200")
+
+	err := p.Invoke(ctx, code, false, nil, addr, new(big.Int), big.NewInt(1000),
+		"relay", common.MustEncodeAny([]interface{}{other}), 0, nil)
+	if err != nil {
+		t.Fatalf("fail to invoke err=%+v", err)
+	}
+	call := <-ctx.call
+	if !call.to.Equal(other) || call.method != "increase" || call.limit.Int64() != 873 {
+		t.Errorf("unexpected call to=%s method=%s limit=%s", call.to, call.method, call.limit)
+	}
+	err = p.SendResult(ctx, nil, big.NewInt(200), common.MustEncodeAny(7), 0, 0)
+	if err != nil {
+		t.Fatalf("fail to send result err=%+v", err)
+	}
+	r := <-ctx.result
+	if r.status != nil {
+		t.Fatalf("unexpected status=%+v", r.status)
+	}
+	if v := common.MustDecodeAny(r.result).(*common.HexInt); v.Int64() != 7 {
+		t.Errorf("unexpected result=%s", v)
+	}
+	if r.steps.Int64() != 327 {
+		t.Errorf("unexpected steps=%s", r.steps)
+	}
+}
+
+func TestGoEE_Kill(t *testing.T) {
+	code := newTestGoCode(t, "test.counter")
+	defer os.RemoveAll(code)
+
+	engine, _ := NewGoEE(log.GlobalLogger())
+	p := engine.(localEngine).newProxy()
+	ctx := newTestCallContext()
+	addr := common.NewAddressFromString("cx0000000000000000000000000000000000000100")
+
+	err := p.Invoke(ctx, code, false, nil, addr, new(big.Int), big.NewInt(1000000000000),
+		"spin", nil, 0, nil)
+	if err != nil {
+		t.Fatalf("fail to invoke err=%+v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := p.Kill(); err != nil {
+		t.Fatalf("fail to kill err=%+v", err)
+	}
+	count := string(ctx.store["count"])
+	time.Sleep(10 * time.Millisecond)
+	if string(ctx.store["count"]) != count {
+		t.Error("the killed contract changes the state")
+	}
+	select {
+	case r := <-ctx.result:
+		t.Errorf("unexpected result of the killed contract status=%+v", r.status)
+	default:
+	}
+
+	err = p.Invoke(ctx, code, false, nil, addr, new(big.Int), big.NewInt(1000),
+		"increase", nil, 0, nil)
+	if !errors.ExecutionFailError.Equals(err) {
+		t.Errorf("invoke on the killed proxy err=%+v", err)
+	}
+}
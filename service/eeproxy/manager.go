@@ -48,7 +48,7 @@ type Executor struct {
 	priority RequestPriority
 	manager  *executorManager
 	typeMap  map[string]int
-	proxies  []*proxy
+	proxies  []Proxy
 }
 
 func (e *Executor) Get(name string) Proxy {
@@ -190,17 +190,22 @@ func (em *executorManager) Close() error {
 }
 
 func (em *executorManager) createExecutorInLock(pr RequestPriority) *Executor {
-	ps := make([]*proxy, len(em.engines))
-	for i, e := range em.engines {
-		if e.ready == nil {
+	for _, e := range em.engines {
+		if _, ok := e.engine.(localEngine); !ok && e.ready == nil {
 			return nil
 		}
-		ps[i] = e.ready
 	}
-	for i, p := range ps {
+	ps := make([]Proxy, len(em.engines))
+	for i, e := range em.engines {
+		if le, ok := e.engine.(localEngine); ok {
+			ps[i] = le.newProxy()
+			continue
+		}
+		p := e.ready
 		p.detach()
-		p.attachTo(&em.engines[i].using)
+		p.attachTo(&e.using)
 		p.reserve()
+		ps[i] = p
 	}
 	return &Executor{
 		priority: pr,
@@ -15,6 +15,8 @@ const (
 	NotContractAddressError
 	InvalidPatchDataError
 	CommittedTransactionError
+	RejectedTransactionError
+	TooManyTransactionsError
 )
 
 var (
@@ -24,4 +26,6 @@ var (
 	ErrTransitionInterrupted   = errors.NewBase(TransitionInterruptedError, "TransitionInterrupted")
 	ErrInvalidTransaction      = errors.NewBase(InvalidTransactionError, "InvalidTransaction")
 	ErrCommittedTransaction    = errors.NewBase(CommittedTransactionError, "CommittedTransaction")
+	ErrRejectedTransaction     = errors.NewBase(RejectedTransactionError, "RejectedTransaction")
+	ErrTooManyTransactions     = errors.NewBase(TooManyTransactionsError, "TooManyTransactions")
 )
@@ -3,6 +3,7 @@ package service
 import (
 	"encoding/json"
 	"math/big"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -51,8 +52,16 @@ type manager struct {
 	log log.Logger
 
 	skipTxPatch atomic.Value
+
+	evidenceLock sync.Mutex
+	evidences    []module.Evidence
+	evidenceSet  map[string]bool
 }
 
+// configMaxPendingEvidences is the maximum number of evidences waiting to be
+// recorded. The oldest one is dropped on overflow.
+const configMaxPendingEvidences = 64
+
 func NewManager(chain module.Chain, nm module.NetworkManager,
 	eem eeproxy.Manager, contractDir string,
 ) (module.ServiceManager, error) {
@@ -76,6 +85,7 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 		chain.PatchTxPoolSize(), bk, pMetric, logger)
 	nTxPool := NewTransactionPool(module.TransactionGroupNormal,
 		chain.NormalTxPoolSize(), bk, nMetric, logger)
+	nTxPool.SetPolicies(TxPoliciesOf(chain)...)
 	tsc := NewTimestampChecker()
 	tm := NewTransactionManager(chain.NID(), tsc, pTxPool, nTxPool, bk, logger)
 	syncm := ssync.NewSyncManager(chain.Database(), chain.NetworkManager(), logger)
@@ -175,6 +185,29 @@ func (m *manager) SendPatch(data module.Patch) error {
 		}
 		m.skipTxPatch.Store(patch)
 		return nil
+	} else if data.Type() == module.PatchTypeDoubleSign {
+		evidence, ok := data.(module.Evidence)
+		if !ok {
+			return InvalidPatchDataError.New("Invalid Double Sign Patch Data")
+		}
+		if err := evidence.Verify(); err != nil {
+			return InvalidPatchDataError.Wrap(err, "InvalidEvidence")
+		}
+		m.evidenceLock.Lock()
+		defer m.evidenceLock.Unlock()
+		if m.evidenceSet == nil {
+			m.evidenceSet = make(map[string]bool)
+		}
+		if m.evidenceSet[string(evidence.Hash())] {
+			return nil
+		}
+		if len(m.evidences) >= configMaxPendingEvidences {
+			delete(m.evidenceSet, string(m.evidences[0].Hash()))
+			m.evidences = m.evidences[1:]
+		}
+		m.evidences = append(m.evidences, evidence)
+		m.evidenceSet[string(evidence.Hash())] = true
+		return nil
 	} else {
 		return InvalidPatchDataError.New("UnknownPatch")
 	}
@@ -214,9 +247,50 @@ func (m *manager) GetPatches(parent module.Transition, bi module.BlockInfo) modu
 			txs = append(txs, tx)
 		}
 	}
+	// evidences are accepted from Revision10
+	if wc.Revision() >= module.Revision10 {
+		for _, e := range m.pendingEvidences(ws, wc.BlockHeight()) {
+			if e.Height() > wc.BlockHeight() {
+				continue
+			}
+			tx, err := transaction.NewPatchTransaction(
+				e, m.chain.NID(), wc.BlockTimeStamp(), m.chain.Wallet())
+			if err != nil {
+				m.log.Panicf("Fail to make transaction from patch err=%+v", err)
+			}
+			if size+len(tx.Bytes()) > m.chain.MaxBlockTxBytes() {
+				break
+			}
+			size += len(tx.Bytes())
+			txs = append(txs, tx)
+		}
+	}
 	return transaction.NewTransactionListFromSlice(m.db, txs)
 }
 
+// pendingEvidences returns evidences not recorded in the world state. It
+// drops recorded ones, expired ones and ones for the validators before the
+// last change, which can't be recorded.
+func (m *manager) pendingEvidences(ws state.WorldState, height int64) []module.Evidence {
+	m.evidenceLock.Lock()
+	defer m.evidenceLock.Unlock()
+
+	as := ws.GetAccountState(state.SystemID)
+	recorded := scoredb.NewDictDB(as, state.VarEvidences, 1)
+	vh := scoredb.NewVarDB(as, state.VarValidatorsHeight).Int64()
+	evidences := m.evidences[:0]
+	for _, e := range m.evidences {
+		if recorded.Get(e.Hash()) == nil && (vh == 0 || e.Height() >= vh) &&
+			e.Height()+module.EvidenceExpiry >= height {
+			evidences = append(evidences, e)
+		} else {
+			delete(m.evidenceSet, string(e.Hash()))
+		}
+	}
+	m.evidences = evidences
+	return append([]module.Evidence{}, evidences...)
+}
+
 // PatchTransition creates a Transition by overwriting patches on the transition.
 // It doesn't return same instance as transition, but new Transition instance.
 func (m *manager) PatchTransition(t module.Transition, patchTxList module.TransactionList,
@@ -445,6 +519,112 @@ func (m *manager) ValidatorListFromHash(hash []byte) module.ValidatorList {
 	return valList
 }
 
+// HasWorldState returns whether the world state of the result is available
+// in the database. It's not available if it's pruned.
+func HasWorldState(database db.Database, result []byte) (bool, error) {
+	stateHash, _, _, err := ParseResult(result)
+	if err != nil {
+		return false, err
+	}
+	if len(stateHash) == 0 {
+		return true, nil
+	}
+	bk, err := database.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return false, err
+	}
+	return bk.Has(stateHash), nil
+}
+
+// CopyWorldState copies nodes of the world state of the result from src to
+// dst. Nodes already in dst are skipped along with their descendants.
+func CopyWorldState(src db.Database, result []byte, dst db.Database) error {
+	stateHash, _, _, err := ParseResult(result)
+	if err != nil || len(stateHash) == 0 {
+		return err
+	}
+	e := merkle.NewCopyContext(src, dst)
+	if _, err := state.NewWorldSnapshotWithBuilder(e.Builder(), stateHash, nil); err != nil {
+		return err
+	}
+	return e.Run()
+}
+
+// RequestWorldState requests nodes of the world state of the result to the
+// builder.
+func RequestWorldState(builder merkle.Builder, result []byte) error {
+	stateHash, _, _, err := ParseResult(result)
+	if err != nil || len(stateHash) == 0 {
+		return err
+	}
+	_, err = state.NewWorldSnapshotWithBuilder(builder, stateHash, nil)
+	return err
+}
+
+// RequestReceipts requests nodes of the receipt lists of the result to the
+// builder.
+func RequestReceipts(builder merkle.Builder, result []byte) error {
+	_, patchHash, normalHash, err := ParseResult(result)
+	if err != nil {
+		return err
+	}
+	txresult.NewReceiptListWithBuilder(builder, normalHash)
+	txresult.NewReceiptListWithBuilder(builder, patchHash)
+	return nil
+}
+
+// SetSnapshotStore sets the store of the state snapshots served to the
+// peers through the state sync protocol.
+func SetSnapshotStore(sm module.ServiceManager, store ssync.SnapshotStore) {
+	if m, ok := sm.(*manager); ok {
+		m.syncer.SetSnapshotStore(store)
+	}
+}
+
+// FetchNodes fetches the values of the hashes from the peers through the
+// state sync protocol. Values not found in the peers are not in the result.
+func FetchNodes(sm module.ServiceManager, hashes [][]byte, stop <-chan struct{}) (map[string][]byte, error) {
+	m, ok := sm.(*manager)
+	if !ok {
+		return nil, errors.UnsupportedError.New("StateSyncNotSupported")
+	}
+	return m.syncer.FetchNodes(hashes, stop)
+}
+
+// FetchSnapshot returns the source of the state snapshot at the height,
+// which fetches chunks from the peers. Snapshots whose manifest fails the
+// check are ignored.
+func FetchSnapshot(sm module.ServiceManager, height int64, check func(mf *ssync.SnapshotManifest) error, stop <-chan struct{}) (ssync.SnapshotSource, error) {
+	m, ok := sm.(*manager)
+	if !ok {
+		return nil, errors.UnsupportedError.New("SnapshotSyncNotSupported")
+	}
+	return m.syncer.FetchSnapshot(height, check, stop)
+}
+
+// CopyReceipts copies nodes of the receipt lists of the result from src to
+// dst. Nodes already in dst are skipped along with their descendants.
+func CopyReceipts(src db.Database, result []byte, dst db.Database) error {
+	_, patchHash, normalHash, err := ParseResult(result)
+	if err != nil {
+		return err
+	}
+	e := merkle.NewCopyContext(src, dst)
+	txresult.NewReceiptListWithBuilder(e.Builder(), normalHash)
+	txresult.NewReceiptListWithBuilder(e.Builder(), patchHash)
+	return e.Run()
+}
+
+// CopyTransactions copies nodes of the transaction list from src to dst.
+func CopyTransactions(src db.Database, h []byte, dst db.Database) error {
+	if len(h) == 0 {
+		return nil
+	}
+	e := merkle.NewCopyContext(src, dst)
+	transaction.NewTransactionListWithBuilder(e.Builder(), h)
+	return e.Run()
+}
+
 func (m *manager) GetBalance(result []byte, addr module.Address) (*big.Int, error) {
 	wss, err := m.trc.GetWorldSnapshot(result, nil)
 	if err != nil {
@@ -599,6 +779,20 @@ func (m *manager) ImportResult(result []byte, vh []byte, src db.Database) error
 }
 
 func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo) (module.Receipt, error) {
+	return m.executeTransaction(result, vh, js, bi, nil)
+}
+
+func (m *manager) ExecuteTransactionForTrace(result []byte, vh []byte, js []byte, bi module.BlockInfo, cb module.TraceCallback) (module.Receipt, error) {
+	rct, err := m.executeTransaction(result, vh, js, bi, &module.TraceInfo{
+		Group:    module.TransactionGroupNormal,
+		Index:    0,
+		Callback: cb,
+	})
+	cb.OnEnd(err)
+	return rct, err
+}
+
+func (m *manager) executeTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo, ti *module.TraceInfo) (module.Receipt, error) {
 	tx, err := transaction.NewTransactionFromJSON(js)
 	if err != nil {
 		return nil, err
@@ -623,7 +817,7 @@ func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi mod
 	} else {
 		return nil, err
 	}
-	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil)
+	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, ti)
 	ctx.SetTransactionInfo(&state.TransactionInfo{
 		Group:     module.TransactionGroupNormal,
 		Index:     0,
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func TestHasWorldState(t *testing.T) {
+	database := db.NewMapDB()
+	tr := &transitionResult{
+		StateHash: []byte("state-hash"),
+	}
+
+	ok, err := HasWorldState(database, nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = HasWorldState(database, tr.Bytes())
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	bk, err := database.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set(tr.StateHash, []byte("root")))
+
+	ok, err = HasWorldState(database, tr.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestCopyWorldState(t *testing.T) {
+	src := db.NewMapDB()
+	addr := common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	ws := state.NewWorldState(src, nil, nil)
+	ws.GetAccountState(addr.ID()).SetBalance(big.NewInt(100))
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+	tr := &transitionResult{
+		StateHash: wss.StateHash(),
+	}
+
+	dst := db.NewMapDB()
+	assert.NoError(t, CopyWorldState(src, tr.Bytes(), dst))
+
+	ok, err := HasWorldState(dst, tr.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	wss2 := state.NewWorldSnapshot(dst, tr.StateHash, nil)
+	ass := wss2.GetAccountSnapshot(addr.ID())
+	assert.Equal(t, big.NewInt(100), ass.GetBalance())
+
+	// nodes already in the target are not requested
+	assert.NoError(t, CopyWorldState(db.NewMapDB(), tr.Bytes(), dst))
+}
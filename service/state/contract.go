@@ -49,6 +49,7 @@ const (
 const (
 	CTAppZip    = "application/zip"
 	CTAppJava   = "application/java"
+	CTAppGo     = "application/x.score.go"
 	CTAppSystem = "application/x.score.system"
 )
 
@@ -9,6 +9,7 @@ type EEType string
 const (
 	PythonEE EEType = "python"
 	JavaEE   EEType = "java"
+	GoEE     EEType = "go"
 	SystemEE EEType = "system"
 )
 
@@ -16,6 +17,7 @@ var (
 	installMethods = map[EEType]string{
 		PythonEE: "on_install",
 		JavaEE:   "<init>",
+		GoEE:     "<init>",
 		SystemEE: "<Install>",
 	}
 	updateMethods = map[EEType]string{
@@ -66,13 +68,16 @@ func (e EEType) String() string {
 	return string(e)
 }
 
-// Only "application/zip" and "application/java" are allowed as contentType by server validator.
+// Only "application/zip", "application/java" and "application/x.score.go" are allowed as contentType by server validator.
+// "application/x.score.go" is rejected on deploy before Revision9.
 func EETypeFromContentType(ct string) EEType {
 	switch ct {
 	case CTAppZip:
 		return PythonEE
 	case CTAppJava:
 		return JavaEE
+	case CTAppGo:
+		return GoEE
 	case CTAppSystem:
 		return SystemEE
 	default:
@@ -33,6 +33,8 @@ const (
 	VarRoundLimitFactor   = "round_limit_factor"
 	VarMinimizeBlockGen   = "minimize_block_gen"
 	VarTxHashToAddress    = "tx_to_address"
+	VarEvidences          = "evidences"
+	VarValidatorsHeight   = "validators_height"
 )
 
 const (
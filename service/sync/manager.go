@@ -28,6 +28,7 @@ type Manager struct {
 	pool    *peerPool
 	server  *server
 	client  *client
+	fetcher *snapshotFetcher
 	db      db.Database
 	syncing bool
 	syncer  *syncer
@@ -51,12 +52,17 @@ func (m *Manager) OnReceive(pi module.ProtocolInfo, b []byte,
 		return false, nil
 	}
 	switch pi {
-	case protoHasNode, protoRequestNodeData:
+	case protoHasNode, protoRequestNodeData, protoRequestSnapshot, protoRequestChunk:
 		m.server.onReceive(pi, b, p)
+	case protoSnapshot, protoChunk:
+		m.fetcher.onReceive(pi, b, p)
 	case protoResult, protoNodeData:
 		if m.syncing {
 			m.syncer.onReceive(pi, b, p)
 		}
+		if pi == protoNodeData {
+			m.fetcher.onReceive(pi, b, p)
+		}
 	}
 	return false, nil
 }
@@ -130,6 +136,7 @@ func NewSyncManager(db db.Database, nm module.NetworkManager, logger log.Logger)
 
 	client := newClient(ph, logger)
 	m.client = client
+	m.fetcher = newSnapshotFetcher(ph, logger)
 	m.pool = newPeerPool(logger)
 	return m
 }
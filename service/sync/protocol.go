@@ -12,6 +12,10 @@ const (
 	protoResult
 	protoRequestNodeData
 	protoNodeData
+	protoRequestSnapshot
+	protoSnapshot
+	protoRequestChunk
+	protoChunk
 )
 
 var protocol = []module.ProtocolInfo{
@@ -19,6 +23,10 @@ var protocol = []module.ProtocolInfo{
 	protoResult,
 	protoRequestNodeData,
 	protoNodeData,
+	protoRequestSnapshot,
+	protoSnapshot,
+	protoRequestChunk,
+	protoChunk,
 }
 
 type errCode int
@@ -83,3 +91,45 @@ func (r *nodeData) String() string {
 	return fmt.Sprintf("ReqID(%d), Status(%d), Data(%#x)",
 		r.ReqID, r.Status, r.Data)
 }
+
+type requestSnapshot struct {
+	ReqID  uint32
+	Height int64
+}
+
+func (r *requestSnapshot) String() string {
+	return fmt.Sprintf("ReqID(%d), Height(%d)", r.ReqID, r.Height)
+}
+
+type snapshotData struct {
+	ReqID    uint32
+	Status   errCode
+	Manifest *SnapshotManifest
+}
+
+func (r *snapshotData) String() string {
+	return fmt.Sprintf("ReqID(%d), Status(%d), Manifest(%v)",
+		r.ReqID, r.Status, r.Manifest)
+}
+
+type requestChunk struct {
+	ReqID  uint32
+	Height int64
+	Index  int
+}
+
+func (r *requestChunk) String() string {
+	return fmt.Sprintf("ReqID(%d), Height(%d), Index(%d)",
+		r.ReqID, r.Height, r.Index)
+}
+
+type chunkData struct {
+	ReqID  uint32
+	Status errCode
+	Data   []byte
+}
+
+func (r *chunkData) String() string {
+	return fmt.Sprintf("ReqID(%d), Status(%d), Size(%d)",
+		r.ReqID, r.Status, len(r.Data))
+}
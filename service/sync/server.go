@@ -1,6 +1,8 @@
 package sync
 
 import (
+	"sync"
+
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
@@ -12,6 +14,9 @@ type server struct {
 	log         log.Logger
 	merkleTrie  db.Bucket
 	bytesByHash db.Bucket
+
+	mutex sync.Mutex
+	store SnapshotStore
 }
 
 func (s *server) onReceive(pi module.ProtocolInfo, b []byte, p *peer) {
@@ -20,6 +25,10 @@ func (s *server) onReceive(pi module.ProtocolInfo, b []byte, p *peer) {
 		go s.hasNode(b, p)
 	case protoRequestNodeData:
 		go s.requestNode(b, p)
+	case protoRequestSnapshot:
+		go s.requestSnapshot(b, p)
+	case protoRequestChunk:
+		go s.requestChunk(b, p)
 	default:
 		s.log.Infof("Invalid pi(%v)\n", pi)
 	}
@@ -100,6 +109,64 @@ func (s *server) requestNode(msg []byte, p *peer) {
 	}
 }
 
+func (s *server) setSnapshotStore(store SnapshotStore) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.store = store
+}
+
+func (s *server) snapshotStore() SnapshotStore {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.store
+}
+
+func (s *server) requestSnapshot(msg []byte, p *peer) {
+	req := new(requestSnapshot)
+	if _, err := c.UnmarshalFromBytes(msg, req); err != nil {
+		s.log.Infof("Failed to unmarshal error(%+v), (%#x)\n", err, msg)
+		return
+	}
+	res := &snapshotData{ReqID: req.ReqID, Status: ErrNoData}
+	if store := s.snapshotStore(); store != nil {
+		if mf, err := store.Manifest(req.Height); err == nil {
+			res.Status = NoError
+			res.Manifest = mf
+		} else {
+			s.log.Tracef("requestSnapshot NoData err(%v) height(%d)\n", err, req.Height)
+		}
+	}
+	s.log.Tracef("responseSnapshot(%s) to peer(%s)\n", res, p)
+	if b, err := c.MarshalToBytes(res); err != nil {
+		s.log.Warnf("Failed to marshal snapshotData error(%+v)\n", err)
+	} else if err = s.ph.Unicast(protoSnapshot, b, p.id); err != nil {
+		s.log.Infof("Failed to send snapshotData error(%+v)\n", err)
+	}
+}
+
+func (s *server) requestChunk(msg []byte, p *peer) {
+	req := new(requestChunk)
+	if _, err := c.UnmarshalFromBytes(msg, req); err != nil {
+		s.log.Infof("Failed to unmarshal error(%+v), (%#x)\n", err, msg)
+		return
+	}
+	res := &chunkData{ReqID: req.ReqID, Status: ErrNoData}
+	if store := s.snapshotStore(); store != nil {
+		if data, err := store.Chunk(req.Height, req.Index); err == nil {
+			res.Status = NoError
+			res.Data = data
+		} else {
+			s.log.Tracef("requestChunk NoData err(%v) req(%s)\n", err, req)
+		}
+	}
+	s.log.Tracef("responseChunk(%s) to peer(%s)\n", res, p)
+	if b, err := c.MarshalToBytes(res); err != nil {
+		s.log.Warnf("Failed to marshal chunkData error(%+v)\n", err)
+	} else if err = s.ph.Unicast(protoChunk, b, p.id); err != nil {
+		s.log.Infof("Failed to send chunkData error(%+v)\n", err)
+	}
+}
+
 func newServer(database db.Database, ph module.ProtocolHandler, log log.Logger) *server {
 	mb, err := database.GetBucket(db.MerkleTrie)
 	if err != nil {
@@ -0,0 +1,448 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// Snapshot file has chunks of the world state followed by the manifest.
+// The last 8 bytes of the file is the offset of the manifest. Each chunk
+// has merkle trie nodes and other hashed data reachable from the state
+// hash, so that they can be verified with their hashes.
+const (
+	SnapshotVersion    = 1
+	SnapshotFileSuffix = ".snap"
+
+	snapshotChunkSize   = 512 * 1024
+	snapshotTrailerSize = 8
+)
+
+type SnapshotChunk struct {
+	Hash   []byte
+	Offset int64
+	Size   int32
+}
+
+// SnapshotManifest describes the snapshot of the world state of the block.
+// It's not signed, so the state hash should be checked against the block
+// which the node trusts, like the block of the pruned genesis.
+type SnapshotManifest struct {
+	Version   int
+	NID       int
+	Height    int64
+	BlockID   []byte
+	StateHash []byte
+	Chunks    []SnapshotChunk
+}
+
+// Verify checks the format of the manifest.
+func (mf *SnapshotManifest) Verify() error {
+	if mf.Version != SnapshotVersion {
+		return errors.UnsupportedError.Errorf(
+			"UnsupportedSnapshot(version=%d)", mf.Version)
+	}
+	return nil
+}
+
+func (mf *SnapshotManifest) String() string {
+	return fmt.Sprintf("Snapshot(height=%d,block=%#x,state=%#x,chunks=%d)",
+		mf.Height, mf.BlockID, mf.StateHash, len(mf.Chunks))
+}
+
+func (mf *SnapshotManifest) verifyChunk(idx int, data []byte) error {
+	if idx < 0 || idx >= len(mf.Chunks) {
+		return errors.IllegalArgumentError.Errorf("InvalidChunkIndex(idx=%d)", idx)
+	}
+	if !bytes.Equal(crypto.SHA3Sum256(data), mf.Chunks[idx].Hash) {
+		return errors.InvalidStateError.Errorf("InvalidChunk(idx=%d)", idx)
+	}
+	return nil
+}
+
+type snapshotChunkData struct {
+	Nodes [][]byte
+	Bytes [][]byte
+}
+
+// SnapshotFileName returns the name of the snapshot file of the height.
+func SnapshotFileName(height int64) string {
+	return strconv.FormatInt(height, 10) + SnapshotFileSuffix
+}
+
+type snapshotWriter struct {
+	fd       *os.File
+	mf       *SnapshotManifest
+	offset   int64
+	size     int
+	entries  int
+	chunk    snapshotChunkData
+	progress func(entries int) error
+}
+
+func (w *snapshotWriter) add(id db.BucketID, value []byte) error {
+	switch id {
+	case db.MerkleTrie:
+		w.chunk.Nodes = append(w.chunk.Nodes, value)
+	case db.BytesByHash:
+		w.chunk.Bytes = append(w.chunk.Bytes, value)
+	default:
+		return errors.InvalidStateError.Errorf("UnknownBucket(id=%q)", id)
+	}
+	w.size += len(value)
+	w.entries += 1
+	if w.size >= snapshotChunkSize {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *snapshotWriter) flush() error {
+	if w.size == 0 {
+		return nil
+	}
+	bs, err := c.MarshalToBytes(&w.chunk)
+	if err != nil {
+		return err
+	}
+	if _, err := w.fd.Write(bs); err != nil {
+		return err
+	}
+	w.mf.Chunks = append(w.mf.Chunks, SnapshotChunk{
+		Hash:   crypto.SHA3Sum256(bs),
+		Offset: w.offset,
+		Size:   int32(len(bs)),
+	})
+	w.offset += int64(len(bs))
+	w.chunk = snapshotChunkData{}
+	w.size = 0
+	if w.progress != nil {
+		return w.progress(w.entries)
+	}
+	return nil
+}
+
+func (w *snapshotWriter) close() error {
+	bs, err := c.MarshalToBytes(w.mf)
+	if err != nil {
+		return err
+	}
+	if _, err := w.fd.Write(bs); err != nil {
+		return err
+	}
+	var trailer [snapshotTrailerSize]byte
+	binary.BigEndian.PutUint64(trailer[:], uint64(w.offset))
+	_, err = w.fd.Write(trailer[:])
+	return err
+}
+
+// collectDatabase passes data written by the builder to the writer. It
+// keeps them also, so shared nodes are written only once.
+type collectDatabase struct {
+	db.Database
+	writer *snapshotWriter
+}
+
+func (d *collectDatabase) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := d.Database.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	return &collectBucket{Bucket: bk, id: id, writer: d.writer}, nil
+}
+
+func (d *collectDatabase) NewBatch() db.Batch {
+	return db.NewBucketBatch(d)
+}
+
+type collectBucket struct {
+	db.Bucket
+	id     db.BucketID
+	writer *snapshotWriter
+}
+
+func (b *collectBucket) Set(key []byte, value []byte) error {
+	if err := b.Bucket.Set(key, value); err != nil {
+		return err
+	}
+	return b.writer.add(b.id, value)
+}
+
+// WriteSnapshot writes the snapshot of the world state of the manifest to
+// the file. It fills the chunks of the manifest and signs it with the
+// wallet. on is called with number of entries written for each chunk.
+func WriteSnapshot(database db.Database, mf *SnapshotManifest, file string, on func(entries int) error) (ret error) {
+	tmpDir, err := ioutil.TempDir(path.Dir(file), ".snapshot")
+	if err != nil {
+		return errors.Wrap(err, "FailToMakeTempDir")
+	}
+	defer os.RemoveAll(tmpDir)
+	tdb, err := db.Open(tmpDir, string(db.GoLevelDBBackend), "seen")
+	if err != nil {
+		return err
+	}
+	defer tdb.Close()
+
+	tmp := file + ".tmp"
+	fd, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		fd.Close()
+		if ret != nil {
+			os.Remove(tmp)
+		}
+	}()
+
+	mf.Version = SnapshotVersion
+	mf.Chunks = nil
+	writer := &snapshotWriter{fd: fd, mf: mf, progress: on}
+	e := merkle.NewCopyContext(database, &collectDatabase{tdb, writer})
+	if _, err := state.NewWorldSnapshotWithBuilder(e.Builder(), mf.StateHash, nil); err != nil {
+		return err
+	}
+	if err := e.Run(); err != nil {
+		return err
+	}
+	if err := writer.flush(); err != nil {
+		return err
+	}
+	if err := writer.close(); err != nil {
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// SnapshotSource provides the manifest and the chunks of a snapshot.
+type SnapshotSource interface {
+	Manifest() *SnapshotManifest
+	Chunk(idx int) ([]byte, error)
+}
+
+type SnapshotFile struct {
+	fd *os.File
+	mf *SnapshotManifest
+}
+
+func (f *SnapshotFile) Manifest() *SnapshotManifest {
+	return f.mf
+}
+
+func (f *SnapshotFile) Chunk(idx int) ([]byte, error) {
+	if idx < 0 || idx >= len(f.mf.Chunks) {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidChunkIndex(idx=%d)", idx)
+	}
+	chunk := f.mf.Chunks[idx]
+	bs := make([]byte, chunk.Size)
+	if _, err := f.fd.ReadAt(bs, chunk.Offset); err != nil {
+		return nil, errors.Wrapf(err, "FailToReadChunk(idx=%d)", idx)
+	}
+	return bs, nil
+}
+
+func (f *SnapshotFile) Close() error {
+	return f.fd.Close()
+}
+
+// OpenSnapshotFile opens the snapshot file and reads its manifest.
+func OpenSnapshotFile(file string) (*SnapshotFile, error) {
+	fd, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	mf, err := readSnapshotManifest(fd)
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	return &SnapshotFile{fd: fd, mf: mf}, nil
+}
+
+func readSnapshotManifest(fd *os.File) (*SnapshotManifest, error) {
+	fi, err := fd.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+	if size < snapshotTrailerSize {
+		return nil, errors.IllegalArgumentError.New("InvalidSnapshotFile")
+	}
+	var trailer [snapshotTrailerSize]byte
+	if _, err := fd.ReadAt(trailer[:], size-snapshotTrailerSize); err != nil {
+		return nil, err
+	}
+	offset := int64(binary.BigEndian.Uint64(trailer[:]))
+	if offset < 0 || offset > size-snapshotTrailerSize {
+		return nil, errors.IllegalArgumentError.New("InvalidSnapshotFile")
+	}
+	bs := make([]byte, size-snapshotTrailerSize-offset)
+	if _, err := fd.ReadAt(bs, offset); err != nil {
+		return nil, err
+	}
+	mf := new(SnapshotManifest)
+	if _, err := c.UnmarshalFromBytes(bs, mf); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidSnapshotManifest")
+	}
+	return mf, nil
+}
+
+// RestoreSnapshot restores the world state of the snapshot to the database.
+// Chunks are verified with the manifest, and the restored world state is
+// verified with the state hash of the manifest. on is called for each
+// chunk before it's fetched.
+func RestoreSnapshot(database db.Database, src SnapshotSource, tmpDir string, on func(idx int) error) error {
+	mf := src.Manifest()
+	if err := mf.Verify(); err != nil {
+		return err
+	}
+	dir, err := ioutil.TempDir(tmpDir, ".snapshot")
+	if err != nil {
+		return errors.Wrap(err, "FailToMakeTempDir")
+	}
+	defer os.RemoveAll(dir)
+	tdb, err := db.Open(dir, string(db.GoLevelDBBackend), "chunks")
+	if err != nil {
+		return err
+	}
+	defer tdb.Close()
+
+	mb, err := tdb.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	bb, err := tdb.GetBucket(db.BytesByHash)
+	if err != nil {
+		return err
+	}
+	for idx := range mf.Chunks {
+		if on != nil {
+			if err := on(idx); err != nil {
+				return err
+			}
+		}
+		data, err := src.Chunk(idx)
+		if err != nil {
+			return err
+		}
+		if err := mf.verifyChunk(idx, data); err != nil {
+			return err
+		}
+		var chunk snapshotChunkData
+		if _, err := c.UnmarshalFromBytes(data, &chunk); err != nil {
+			return errors.IllegalArgumentError.Wrapf(err, "InvalidChunk(idx=%d)", idx)
+		}
+		for _, v := range chunk.Nodes {
+			if err := mb.Set(crypto.SHA3Sum256(v), v); err != nil {
+				return err
+			}
+		}
+		for _, v := range chunk.Bytes {
+			if err := bb.Set(crypto.SHA3Sum256(v), v); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Copying from the chunks verifies every node and the completeness of
+	// the world state.
+	ldb := db.NewLayerDB(database)
+	e := merkle.NewCopyContext(tdb, ldb)
+	if _, err := state.NewWorldSnapshotWithBuilder(e.Builder(), mf.StateHash, nil); err != nil {
+		return err
+	}
+	if err := e.Run(); err != nil {
+		return errors.InvalidStateError.Wrap(err, "IncompleteSnapshot")
+	}
+	return ldb.Flush(true)
+}
+
+// SnapshotStore provides snapshots to the peers.
+type SnapshotStore interface {
+	// Manifest returns the manifest of the snapshot at the height. If
+	// height is zero, it returns the latest one.
+	Manifest(height int64) (*SnapshotManifest, error)
+	Chunk(height int64, idx int) ([]byte, error)
+}
+
+type snapshotDir struct {
+	dir string
+}
+
+func (s *snapshotDir) latest() (int64, error) {
+	fis, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return 0, err
+	}
+	var heights []int64
+	for _, fi := range fis {
+		name := fi.Name()
+		if !fi.Mode().IsRegular() || !strings.HasSuffix(name, SnapshotFileSuffix) {
+			continue
+		}
+		h, err := strconv.ParseInt(strings.TrimSuffix(name, SnapshotFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		heights = append(heights, h)
+	}
+	if len(heights) == 0 {
+		return 0, errors.NotFoundError.New("NoSnapshot")
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+	return heights[0], nil
+}
+
+func (s *snapshotDir) open(height int64) (*SnapshotFile, error) {
+	if height == 0 {
+		var err error
+		if height, err = s.latest(); err != nil {
+			return nil, err
+		}
+	}
+	f, err := OpenSnapshotFile(path.Join(s.dir, SnapshotFileName(height)))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundError.Errorf("NoSnapshot(height=%d)", height)
+	}
+	return f, err
+}
+
+func (s *snapshotDir) Manifest(height int64) (*SnapshotManifest, error) {
+	f, err := s.open(height)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Manifest(), nil
+}
+
+func (s *snapshotDir) Chunk(height int64, idx int) ([]byte, error) {
+	f, err := s.open(height)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Chunk(idx)
+}
+
+// NewSnapshotDir returns the store of the snapshot files in the directory.
+func NewSnapshotDir(dir string) SnapshotStore {
+	return &snapshotDir{dir: dir}
+}
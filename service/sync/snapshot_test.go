@@ -0,0 +1,176 @@
+package sync
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func newTestState(t *testing.T, database db.Database, n int) []byte {
+	ws := state.NewWorldState(database, nil, nil)
+	for i := 0; i < n; i++ {
+		key := []byte{byte(i >> 8), byte(i)}
+		as := ws.GetAccountState(key)
+		as.SetValue(key, bytes.Repeat(key, 128))
+	}
+	wss := ws.GetSnapshot()
+	if err := wss.Flush(); err != nil {
+		t.Fatalf("fail to flush err=%+v", err)
+	}
+	return wss.StateHash()
+}
+
+func writeTestSnapshot(t *testing.T, dir string, database db.Database, stateHash []byte) string {
+	file := path.Join(dir, SnapshotFileName(10))
+	mf := &SnapshotManifest{
+		NID:       1,
+		Height:    10,
+		BlockID:   []byte("block"),
+		StateHash: stateHash,
+	}
+	if err := WriteSnapshot(database, mf, file, nil); err != nil {
+		t.Fatalf("fail to write snapshot err=%+v", err)
+	}
+	return file
+}
+
+func TestSnapshot_FileRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db1 := db.NewMapDB()
+	stateHash := newTestState(t, db1, 4000)
+	file := writeTestSnapshot(t, dir, db1, stateHash)
+
+	f, err := OpenSnapshotFile(file)
+	if err != nil {
+		t.Fatalf("fail to open snapshot err=%+v", err)
+	}
+	defer f.Close()
+	mf := f.Manifest()
+	if err := mf.Verify(); err != nil {
+		t.Errorf("fail to verify manifest err=%+v", err)
+	}
+	if len(mf.Chunks) < 2 {
+		t.Errorf("unexpected number of chunks=%d", len(mf.Chunks))
+	}
+
+	db2 := db.NewMapDB()
+	chunks := 0
+	err = RestoreSnapshot(db2, f, dir, func(idx int) error {
+		chunks += 1
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fail to restore snapshot err=%+v", err)
+	}
+	if chunks != len(mf.Chunks) {
+		t.Errorf("restored chunks=%d exp=%d", chunks, len(mf.Chunks))
+	}
+	wss := state.NewWorldSnapshot(db2, stateHash, nil)
+	as := wss.GetAccountSnapshot([]byte{0x03, 0xe7})
+	if v, err := as.GetValue([]byte{0x03, 0xe7}); err != nil || len(v) != 256 {
+		t.Errorf("unexpected value=%#x err=%v", v, err)
+	}
+}
+
+func TestSnapshot_InvalidManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db1 := db.NewMapDB()
+	stateHash := newTestState(t, db1, 10)
+	file := writeTestSnapshot(t, dir, db1, stateHash)
+
+	f, err := OpenSnapshotFile(file)
+	if err != nil {
+		t.Fatalf("fail to open snapshot err=%+v", err)
+	}
+	defer f.Close()
+
+	mf := f.Manifest()
+	mf.Version += 1
+	if err := mf.Verify(); err == nil {
+		t.Errorf("manifest with unknown version is verified")
+	}
+	if err := RestoreSnapshot(db.NewMapDB(), f, dir, nil); err == nil {
+		t.Errorf("snapshot with invalid manifest is restored")
+	}
+	mf.Version -= 1
+
+	mf.StateHash = bytes.Repeat([]byte{0x01}, len(mf.StateHash))
+	if err := RestoreSnapshot(db.NewMapDB(), f, dir, nil); err == nil {
+		t.Errorf("snapshot with modified state hash is restored")
+	}
+}
+
+func TestSnapshot_Fetch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db1 := db.NewMapDB()
+	db2 := db.NewMapDB()
+	stateHash := newTestState(t, db1, 4000)
+	writeTestSnapshot(t, dir, db1, stateHash)
+
+	nm := newTNetworkManager(createAPeerID())
+	nm2 := newTNetworkManager(createAPeerID())
+	syncm := NewSyncManager(db1, nm, log.New())
+	syncm2 := NewSyncManager(db2, nm2, log.New())
+	syncm.SetSnapshotStore(NewSnapshotDir(dir))
+	nm.join(nm2)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	src, err := syncm2.FetchSnapshot(0, nil, stop)
+	if err != nil {
+		t.Fatalf("fail to fetch snapshot err=%+v", err)
+	}
+	if mf := src.Manifest(); mf.Height != 10 || !bytes.Equal(mf.StateHash, stateHash) {
+		t.Errorf("unexpected manifest %s", mf)
+	}
+	if err := RestoreSnapshot(db2, src, dir, nil); err != nil {
+		t.Fatalf("fail to restore snapshot err=%+v", err)
+	}
+	wss := state.NewWorldSnapshot(db2, stateHash, nil)
+	if !bytes.Equal(wss.StateHash(), stateHash) {
+		t.Errorf("invalid state hash=%#x exp=%#x", wss.StateHash(), stateHash)
+	}
+}
+
+func TestManager_FetchNodes(t *testing.T) {
+	db1 := db.NewMapDB()
+	stateHash := newTestState(t, db1, 10)
+
+	nm := newTNetworkManager(createAPeerID())
+	nm2 := newTNetworkManager(createAPeerID())
+	NewSyncManager(db1, nm, log.New())
+	syncm2 := NewSyncManager(db.NewMapDB(), nm2, log.New())
+	nm.join(nm2)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	unknown := bytes.Repeat([]byte{0xff}, 32)
+	values, err := syncm2.FetchNodes([][]byte{stateHash, unknown}, stop)
+	if err != nil {
+		t.Fatalf("fail to fetch nodes err=%+v", err)
+	}
+	if len(values) != 1 || values[string(stateHash)] == nil {
+		t.Errorf("unexpected values=%v", values)
+	}
+}
@@ -0,0 +1,261 @@
+package sync
+
+import (
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	configSnapshotTimeout = 5 * time.Second
+	configSnapshotRetry   = time.Second
+)
+
+type snapshotFetcher struct {
+	ph      module.ProtocolHandler
+	log     log.Logger
+	mutex   sync.Mutex
+	reqID   uint32
+	pending map[uint32]chan interface{}
+}
+
+func (f *snapshotFetcher) onReceive(pi module.ProtocolInfo, b []byte, p *peer) {
+	var reqID uint32
+	var msg interface{}
+	switch pi {
+	case protoSnapshot:
+		r := new(snapshotData)
+		if _, err := c.UnmarshalFromBytes(b, r); err != nil {
+			f.log.Infof("Failed to unmarshal snapshotData error(%+v)\n", err)
+			return
+		}
+		reqID, msg = r.ReqID, r
+	case protoChunk:
+		r := new(chunkData)
+		if _, err := c.UnmarshalFromBytes(b, r); err != nil {
+			f.log.Infof("Failed to unmarshal chunkData error(%+v)\n", err)
+			return
+		}
+		reqID, msg = r.ReqID, r
+	case protoNodeData:
+		r := new(nodeData)
+		if _, err := c.UnmarshalFromBytes(b, r); err != nil {
+			f.log.Infof("Failed to unmarshal nodeData error(%+v)\n", err)
+			return
+		}
+		reqID, msg = r.ReqID, r
+	default:
+		return
+	}
+	f.mutex.Lock()
+	ch, ok := f.pending[reqID]
+	delete(f.pending, reqID)
+	f.mutex.Unlock()
+	if ok {
+		ch <- msg
+	}
+}
+
+func (f *snapshotFetcher) request(pi module.ProtocolInfo, id module.PeerID,
+	msg func(reqID uint32) interface{}, stop <-chan struct{}) (interface{}, error) {
+	ch := make(chan interface{}, 1)
+	f.mutex.Lock()
+	f.reqID += 1
+	reqID := f.reqID
+	f.pending[reqID] = ch
+	f.mutex.Unlock()
+	defer func() {
+		f.mutex.Lock()
+		delete(f.pending, reqID)
+		f.mutex.Unlock()
+	}()
+
+	b, err := c.MarshalToBytes(msg(reqID))
+	if err != nil {
+		return nil, err
+	}
+	if err := f.ph.Unicast(pi, b, id); err != nil {
+		return nil, err
+	}
+	select {
+	case res := <-ch:
+		return res, nil
+	case <-stop:
+		return nil, errors.ErrInterrupted
+	case <-time.After(configSnapshotTimeout):
+		return nil, errors.TimeoutError.New("SnapshotRequestTimeout")
+	}
+}
+
+func newSnapshotFetcher(ph module.ProtocolHandler, logger log.Logger) *snapshotFetcher {
+	return &snapshotFetcher{
+		ph:      ph,
+		log:     logger,
+		pending: make(map[uint32]chan interface{}),
+	}
+}
+
+type peerSnapshot struct {
+	m    *Manager
+	mf   *SnapshotManifest
+	peer module.PeerID
+	stop <-chan struct{}
+}
+
+func (s *peerSnapshot) Manifest() *SnapshotManifest {
+	return s.mf
+}
+
+func (s *peerSnapshot) Chunk(idx int) ([]byte, error) {
+	for {
+		peers := s.m.peerIDs()
+		ids := make([]module.PeerID, 0, len(peers)+1)
+		ids = append(ids, s.peer)
+		for _, id := range peers {
+			if !id.Equal(s.peer) {
+				ids = append(ids, id)
+			}
+		}
+		for _, id := range ids {
+			res, err := s.m.fetcher.request(protoRequestChunk, id,
+				func(reqID uint32) interface{} {
+					return &requestChunk{reqID, s.mf.Height, idx}
+				}, s.stop)
+			if errors.InterruptedError.Equals(err) {
+				return nil, err
+			} else if err != nil {
+				s.m.log.Debugf("Fail to request chunk peer(%s) err(%+v)\n", id, err)
+				continue
+			}
+			cd := res.(*chunkData)
+			if cd.Status != NoError {
+				continue
+			}
+			if err := s.mf.verifyChunk(idx, cd.Data); err != nil {
+				s.m.log.Infof("Invalid chunk from peer(%s) err(%+v)\n", id, err)
+				continue
+			}
+			s.peer = id
+			return cd.Data, nil
+		}
+		select {
+		case <-s.stop:
+			return nil, errors.ErrInterrupted
+		case <-time.After(configSnapshotRetry):
+		}
+	}
+}
+
+func (m *Manager) peerIDs() []module.PeerID {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	peers := m.pool.peerList()
+	ids := make([]module.PeerID, len(peers))
+	for i, p := range peers {
+		ids[i] = p.id
+	}
+	return ids
+}
+
+// FetchSnapshot finds a peer having the snapshot at the height, and returns
+// the source fetching its chunks from the peers. Snapshots whose manifest
+// fails the check are ignored. It retries until one is found or stop is
+// closed.
+func (m *Manager) FetchSnapshot(height int64, check func(mf *SnapshotManifest) error, stop <-chan struct{}) (SnapshotSource, error) {
+	for {
+		for _, id := range m.peerIDs() {
+			res, err := m.fetcher.request(protoRequestSnapshot, id,
+				func(reqID uint32) interface{} {
+					return &requestSnapshot{reqID, height}
+				}, stop)
+			if errors.InterruptedError.Equals(err) {
+				return nil, err
+			} else if err != nil {
+				m.log.Debugf("Fail to request snapshot peer(%s) err(%+v)\n", id, err)
+				continue
+			}
+			sd := res.(*snapshotData)
+			if sd.Status != NoError || sd.Manifest == nil {
+				continue
+			}
+			mf := sd.Manifest
+			if err := mf.Verify(); err != nil {
+				m.log.Infof("Invalid snapshot from peer(%s) err(%+v)\n", id, err)
+				continue
+			}
+			if height != 0 && mf.Height != height {
+				continue
+			}
+			if check != nil {
+				if err := check(mf); err != nil {
+					m.log.Infof("Ignore snapshot from peer(%s) err(%+v)\n", id, err)
+					continue
+				}
+			}
+			m.log.Infof("Found %s from peer(%s)\n", mf, id)
+			return &peerSnapshot{m: m, mf: mf, peer: id, stop: stop}, nil
+		}
+		select {
+		case <-stop:
+			return nil, errors.ErrInterrupted
+		case <-time.After(configSnapshotRetry):
+		}
+	}
+}
+
+// FetchNodes requests the values of the hashes to the peers, and returns
+// the values found. Each peer is requested once for the hashes which are
+// not found yet. It waits for the peers until stop is closed if there is
+// no peer.
+func (m *Manager) FetchNodes(hashes [][]byte, stop <-chan struct{}) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+	for {
+		ids := m.peerIDs()
+		for _, id := range ids {
+			remains := make([][]byte, 0, len(hashes))
+			for _, h := range hashes {
+				if _, ok := values[string(h)]; !ok {
+					remains = append(remains, h)
+				}
+			}
+			for len(remains) > 0 {
+				req := remains
+				if len(req) > configMaxRequestHash {
+					req = req[:configMaxRequestHash]
+				}
+				remains = remains[len(req):]
+				res, err := m.fetcher.request(protoRequestNodeData, id,
+					func(reqID uint32) interface{} {
+						return &requestNodeData{reqID, syncWorldState, req}
+					}, stop)
+				if errors.InterruptedError.Equals(err) {
+					return nil, err
+				} else if err != nil {
+					m.log.Debugf("Fail to request nodes peer(%s) err(%+v)\n", id, err)
+					break
+				}
+				nd := res.(*nodeData)
+				for _, v := range nd.Data {
+					values[string(crypto.SHA3Sum256(v))] = v
+				}
+			}
+		}
+		if len(ids) > 0 {
+			return values, nil
+		}
+		select {
+		case <-stop:
+			return nil, errors.ErrInterrupted
+		case <-time.After(configSnapshotRetry):
+		}
+	}
+}
+
+// SetSnapshotStore sets the store of the snapshots served to the peers.
+func (m *Manager) SetSnapshotStore(store SnapshotStore) {
+	m.server.setSnapshotStore(store)
+}
@@ -0,0 +1,177 @@
+package trace
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+type FrameType string
+
+const (
+	FrameTypeCall     FrameType = "call"
+	FrameTypeDeploy   FrameType = "deploy"
+	FrameTypeAccept   FrameType = "accept"
+	FrameTypeTransfer FrameType = "transfer"
+	FrameTypePatch    FrameType = "patch"
+)
+
+// CallTracer is an optional interface of module.TraceCallback.
+// If the callback implements it, the frames of the call context and
+// storage writes in them are notified to the callback.
+type CallTracer interface {
+	OnFrameEnter(typ FrameType, from, to module.Address, value *big.Int, method string, limit *big.Int)
+	OnFrameExit(status error, stepUsed *big.Int, addr module.Address)
+	OnStorageWrite(key, value, old []byte)
+}
+
+type Failure struct {
+	Code    module.Status `json:"code"`
+	Message string        `json:"message"`
+}
+
+type StorageWrite struct {
+	Key   common.HexBytes `json:"key"`
+	Old   common.HexBytes `json:"old"`
+	Value common.HexBytes `json:"value"`
+}
+
+type CallFrame struct {
+	Type      FrameType       `json:"type"`
+	From      *common.Address `json:"from,omitempty"`
+	To        *common.Address `json:"to,omitempty"`
+	Value     *common.HexInt  `json:"value,omitempty"`
+	Method    string          `json:"method,omitempty"`
+	StepLimit *common.HexInt  `json:"stepLimit,omitempty"`
+	StepUsed  common.HexInt   `json:"stepUsed"`
+	Status    string          `json:"status"`
+	Failure   *Failure        `json:"failure,omitempty"`
+	Writes    []*StorageWrite `json:"writes,omitempty"`
+	Calls     []*CallFrame    `json:"calls,omitempty"`
+}
+
+func addressOf(addr module.Address) *common.Address {
+	if addr == nil {
+		return nil
+	}
+	return common.NewAddress(addr.Bytes())
+}
+
+func hexIntOf(v *big.Int) *common.HexInt {
+	if v == nil {
+		return nil
+	}
+	i := new(common.HexInt)
+	i.Set(v)
+	return i
+}
+
+// CallTree builds the tree of frames from notifications of CallTracer.
+type CallTree struct {
+	lock  sync.Mutex
+	calls []*CallFrame
+	stack []*CallFrame
+}
+
+func (t *CallTree) OnFrameEnter(typ FrameType, from, to module.Address, value *big.Int, method string, limit *big.Int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	f := &CallFrame{
+		Type:      typ,
+		From:      addressOf(from),
+		To:        addressOf(to),
+		Value:     hexIntOf(value),
+		Method:    method,
+		StepLimit: hexIntOf(limit),
+	}
+	if len(t.stack) > 0 {
+		p := t.stack[len(t.stack)-1]
+		p.Calls = append(p.Calls, f)
+	} else {
+		t.calls = append(t.calls, f)
+	}
+	t.stack = append(t.stack, f)
+}
+
+func (t *CallTree) OnFrameExit(status error, stepUsed *big.Int, addr module.Address) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(t.stack) == 0 {
+		return
+	}
+	f := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	if stepUsed != nil {
+		f.StepUsed.Set(stepUsed)
+	}
+	if status == nil {
+		f.Status = "0x1"
+	} else {
+		f.Status = "0x0"
+		code, _ := scoreresult.StatusOf(status)
+		f.Failure = &Failure{
+			Code:    code,
+			Message: status.Error(),
+		}
+	}
+	// Deploy frames get the address of the deployed contract on success.
+	if f.Type == FrameTypeDeploy && addr != nil {
+		f.To = addressOf(addr)
+	}
+}
+
+func (t *CallTree) OnStorageWrite(key, value, old []byte) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(t.stack) == 0 {
+		return
+	}
+	f := t.stack[len(t.stack)-1]
+	f.Writes = append(f.Writes, &StorageWrite{
+		Key:   append([]byte(nil), key...),
+		Old:   append([]byte(nil), old...),
+		Value: append([]byte(nil), value...),
+	})
+}
+
+// Calls returns frames called by the transaction.
+func (t *CallTree) Calls() []*CallFrame {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.calls
+}
+
+// Accounts returns addresses of accounts involved in the frames in the order
+// of their first appearance.
+func (t *CallTree) Accounts() []*common.Address {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var addrs []*common.Address
+	seen := make(map[string]bool)
+	add := func(addr *common.Address) {
+		if addr == nil || seen[string(addr.Bytes())] {
+			return
+		}
+		seen[string(addr.Bytes())] = true
+		addrs = append(addrs, addr)
+	}
+	var walk func(frames []*CallFrame)
+	walk = func(frames []*CallFrame) {
+		for _, f := range frames {
+			add(f.From)
+			add(f.To)
+			walk(f.Calls)
+		}
+	}
+	walk(t.calls)
+	return addrs
+}
@@ -0,0 +1,100 @@
+package trace
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+type testCallback struct {
+	CallTree
+	logs int
+}
+
+func (cb *testCallback) OnLog(level module.TraceLevel, msg string) {
+	cb.logs++
+}
+
+func (cb *testCallback) OnEnd(e error) {
+}
+
+func TestCallTree(t *testing.T) {
+	eoa := common.NewAddressFromString("hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31")
+	score1 := common.NewAddressFromString("cx059e19601bcb1424884f4ef19addc0a03de9e9cd")
+	score2 := common.NewAddressFromString("cx8d3ef83a63d8bbd3f08c4a8b8a18fbba1ef5e8f6")
+
+	cb := new(testCallback)
+	logger := NewLogger(log.GlobalLogger(), cb)
+
+	logger.OnFrameEnter(FrameTypeCall, eoa, score1, nil, "transfer", big.NewInt(1000))
+	logger.OnStorageWrite([]byte{0x01}, []byte{0x02}, nil)
+	logger.OnFrameEnter(FrameTypeTransfer, score1, eoa, big.NewInt(10), "", big.NewInt(500))
+	logger.OnFrameExit(nil, big.NewInt(100), nil)
+	logger.OnFrameEnter(FrameTypeCall, score1, score2, nil, "fail", big.NewInt(400))
+	logger.OnStorageWrite([]byte{0x03}, nil, []byte{0x04})
+	logger.OnFrameExit(scoreresult.ErrOutOfStep, big.NewInt(400), nil)
+	logger.WithFields(log.Fields{}).(*Logger).OnStorageWrite([]byte{0x05}, []byte{0x06}, []byte{0x07})
+	logger.OnFrameExit(nil, big.NewInt(900), nil)
+	logger.TSystem("log")
+
+	calls := cb.Calls()
+	assert.Equal(t, 1, len(calls))
+	root := calls[0]
+	assert.Equal(t, FrameTypeCall, root.Type)
+	assert.Equal(t, "0x1", root.Status)
+	assert.Equal(t, int64(900), root.StepUsed.Int64())
+	assert.Equal(t, 2, len(root.Writes))
+	assert.Equal(t, common.HexBytes{0x07}, root.Writes[1].Old)
+	assert.Equal(t, 2, len(root.Calls))
+
+	tx := root.Calls[0]
+	assert.Equal(t, FrameTypeTransfer, tx.Type)
+	assert.True(t, tx.To.Equal(eoa))
+	assert.Equal(t, int64(10), tx.Value.Int64())
+
+	failed := root.Calls[1]
+	assert.Equal(t, "0x0", failed.Status)
+	assert.Equal(t, module.StatusOutOfStep, failed.Failure.Code)
+	assert.Nil(t, failed.Writes[0].Value)
+	assert.Equal(t, 1, cb.logs)
+
+	accounts := cb.Accounts()
+	assert.Equal(t, 3, len(accounts))
+	assert.True(t, accounts[0].Equal(eoa))
+	assert.True(t, accounts[1].Equal(score1))
+	assert.True(t, accounts[2].Equal(score2))
+
+	bs, err := json.Marshal(calls)
+	assert.NoError(t, err)
+	var decoded []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bs, &decoded))
+	assert.Equal(t, "0x384", decoded[0]["stepUsed"])
+	assert.Equal(t, score1.String(), decoded[0]["to"])
+}
+
+func TestCallTree_Deploy(t *testing.T) {
+	var tree CallTree
+	score := common.NewAddressFromString("cx059e19601bcb1424884f4ef19addc0a03de9e9cd")
+
+	tree.OnFrameEnter(FrameTypeDeploy, score, nil, nil, "", nil)
+	tree.OnFrameExit(nil, big.NewInt(10), score)
+	calls := tree.Calls()
+	assert.Equal(t, 1, len(calls))
+	assert.True(t, calls[0].To.Equal(score))
+	assert.Nil(t, calls[0].StepLimit)
+}
+
+func TestLogger_WithoutCallTracer(t *testing.T) {
+	logger := NewLogger(log.GlobalLogger(), nil)
+	// must not panic without tracer
+	logger.OnFrameEnter(FrameTypeCall, nil, nil, nil, "", nil)
+	logger.OnStorageWrite([]byte{0x01}, nil, nil)
+	logger.OnFrameExit(nil, nil, nil)
+}
@@ -2,6 +2,7 @@ package trace
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
@@ -11,6 +12,7 @@ type Logger struct {
 	log.Logger
 	isTrace bool
 	onLog   func(lv module.TraceLevel, msg string)
+	tracer  CallTracer
 }
 
 func (l *Logger) TLog(lv module.TraceLevel, a ...interface{}) {
@@ -70,6 +72,25 @@ func (l *Logger) WithFields(f log.Fields) log.Logger {
 		Logger:  l.Logger.WithFields(f),
 		isTrace: l.isTrace,
 		onLog:   l.onLog,
+		tracer:  l.tracer,
+	}
+}
+
+func (l *Logger) OnFrameEnter(typ FrameType, from, to module.Address, value *big.Int, method string, limit *big.Int) {
+	if l.tracer != nil {
+		l.tracer.OnFrameEnter(typ, from, to, value, method, limit)
+	}
+}
+
+func (l *Logger) OnFrameExit(status error, stepUsed *big.Int, addr module.Address) {
+	if l.tracer != nil {
+		l.tracer.OnFrameExit(status, stepUsed, addr)
+	}
+}
+
+func (l *Logger) OnStorageWrite(key, value, old []byte) {
+	if l.tracer != nil {
+		l.tracer.OnStorageWrite(key, value, old)
 	}
 }
 
@@ -79,10 +100,12 @@ func dummyLog(lv module.TraceLevel, msg string) {
 
 func NewLogger(l log.Logger, t module.TraceCallback) *Logger {
 	if t != nil {
+		tracer, _ := t.(CallTracer)
 		return &Logger{
 			Logger:  l,
 			isTrace: true,
 			onLog:   t.OnLog,
+			tracer:  tracer,
 		}
 	} else {
 		return &Logger{
@@ -148,13 +148,6 @@ func (tx *transactionV3) Version() int {
 	return module.TransactionVersion3
 }
 
-func (tx *transactionV3) isDeployType(cType string) bool {
-	if cType == state.CTAppZip || cType == state.CTAppJava {
-		return true
-	}
-	return false
-}
-
 func (tx *transactionV3) Verify() error {
 	// value >= 0
 	if tx.Value != nil && tx.Value.Sign() < 0 {
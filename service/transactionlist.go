@@ -150,6 +150,8 @@ func (l *transactionList) Add(tx transaction.Transaction, ts bool) error {
 			e.srcNext = insertPos
 			insertPos.srcPrev = e
 		} else {
+			e.srcPrev = t2
+			t2.srcNext = e
 			l.srcMapToLast[uidBk][uidSlot] = e
 		}
 	} else {
@@ -247,6 +249,16 @@ func (l *transactionList) HasTx(id []byte) bool {
 	return ok
 }
 
+// CountOf returns the number of transactions from the sender.
+func (l *transactionList) CountOf(from module.Address) int {
+	uidBk, uidSlot := indexAndBucketKeyFromKey(string(from.ID()))
+	cnt := 0
+	for e := l.srcMapToLast[uidBk][uidSlot]; e != nil; e = e.srcPrev {
+		cnt += 1
+	}
+	return cnt
+}
+
 func (l *transactionList) GetBloom() *TxBloom {
 	if l.listFront == nil {
 		return &TxBloom{}
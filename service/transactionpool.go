@@ -68,7 +68,8 @@ type TransactionPool struct {
 	size int
 	txdb db.Bucket
 
-	list *transactionList
+	list     *transactionList
+	policies []TxPolicy
 
 	mutex sync.Mutex
 
@@ -124,6 +125,10 @@ func (tp *TransactionPool) DropOldTXs(bts int64) {
 }
 
 // It returns all candidates for a negative integer n.
+// Candidates are collected in arrival order. They are not ordered by fee,
+// because step price is set by the chain for every transaction and the step
+// limit is only a cap of the fee, so a transaction can't pay more for
+// earlier inclusion.
 func (tp *TransactionPool) Candidate(wc state.WorldContext, maxBytes int, maxCount int) (
 	[]module.Transaction, int,
 ) {
@@ -238,6 +243,7 @@ func (tp *TransactionPool) CheckTxs(wc state.WorldContext) bool {
 /*
 	return nil if tx is nil or tx is added to pool
 	return ErrTransactionPoolOverFlow if pool is full
+	return error of the policy if the policy rejects it
 */
 func (tp *TransactionPool) Add(tx transaction.Transaction, direct bool) error {
 	if tx == nil {
@@ -250,6 +256,18 @@ func (tp *TransactionPool) Add(tx transaction.Transaction, direct bool) error {
 		return ErrTransactionPoolOverFlow
 	}
 
+	if len(tp.policies) > 0 {
+		if tp.list.HasTx(tx.ID()) {
+			return ErrDuplicateTransaction
+		}
+		pending := tp.list.CountOf(tx.From())
+		for _, p := range tp.policies {
+			if err := p.CheckTx(tx, pending); err != nil {
+				return err
+			}
+		}
+	}
+
 	err := tp.list.Add(tx, direct)
 	if err == nil {
 		tp.monitor.OnAddTx(len(tx.Bytes()), direct)
@@ -317,6 +335,15 @@ func (tp *TransactionPool) SetTxManager(txm TxWaiterManager) {
 	tp.txm = txm
 }
 
+// SetPolicies sets admission policies of the pool. Transactions already in
+// the pool are not affected.
+func (tp *TransactionPool) SetPolicies(policies ...TxPolicy) {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	tp.policies = policies
+}
+
 func (tp *TransactionPool) SetPoolCapacityMonitor(pcm PoolCapacityMonitor) {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
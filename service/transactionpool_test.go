@@ -42,3 +42,49 @@ func TestTransactionPool_Add(t *testing.T) {
 		t.Error("Fail to add transaction with valid network ID")
 	}
 }
+
+func TestTransactionPool_Policies(t *testing.T) {
+	dbase := db.NewMapDB()
+	bk, _ := dbase.GetBucket(db.TransactionLocatorByHash)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 5000, bk, &mockMonitor{}, log.New())
+
+	addr1 := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.NewAddressFromString("hx2222222222222222222222222222222222222222")
+	addr3 := common.NewAddressFromString("hx3333333333333333333333333333333333333333")
+	pool.SetPolicies(
+		NewAddressListPolicy(nil, []module.Address{addr3}),
+		NewSenderQuotaPolicy(2),
+	)
+
+	if err := pool.Add(newMockTransaction([]byte("tx1"), addr1, 1), true); err != nil {
+		t.Fatalf("Fail to add transaction err=%+v", err)
+	}
+	if err := pool.Add(newMockTransaction([]byte("tx2"), addr1, 2), true); err != nil {
+		t.Fatalf("Fail to add transaction err=%+v", err)
+	}
+	if err := pool.Add(newMockTransaction([]byte("tx3"), addr1, 3), true); !TooManyTransactionsError.Equals(err) {
+		t.Errorf("It should return TooManyTransactionsError err=%+v", err)
+	}
+	if err := pool.Add(newMockTransaction([]byte("tx1"), addr1, 1), true); err != ErrDuplicateTransaction {
+		t.Errorf("It should return ErrDuplicateTransaction err=%+v", err)
+	}
+	if err := pool.Add(newMockTransaction([]byte("tx4"), addr2, 1), true); err != nil {
+		t.Errorf("Fail to add transaction err=%+v", err)
+	}
+	if err := pool.Add(newMockTransaction([]byte("tx5"), addr3, 1), true); !RejectedTransactionError.Equals(err) {
+		t.Errorf("It should return RejectedTransactionError for denied sender err=%+v", err)
+	}
+}
+
+func TestAddressListPolicy(t *testing.T) {
+	addr1 := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.NewAddressFromString("hx2222222222222222222222222222222222222222")
+
+	p := NewAddressListPolicy([]module.Address{addr1}, nil)
+	if err := p.CheckTx(newMockTransaction([]byte("tx1"), addr1, 1), 0); err != nil {
+		t.Errorf("Allowed sender is rejected err=%+v", err)
+	}
+	if err := p.CheckTx(newMockTransaction([]byte("tx2"), addr2, 1), 0); !RejectedTransactionError.Equals(err) {
+		t.Errorf("Not allowed sender should be rejected err=%+v", err)
+	}
+}
@@ -42,6 +42,7 @@ func (r *TransactionReactor) OnReceive(subProtocol module.ProtocolInfo, buf []by
 		if err != nil {
 			r.log.Warnf("InvalidPacket(PropagateTransaction) from=%s", peerId.String())
 			r.log.Debugf("Failed to unmarshal transaction. buf=%x, err=%+v", buf, err)
+			r.membership.Penalize(peerId, module.PenaltyMajor, "InvalidPacket(PropagateTransaction)")
 			return false, err
 		}
 
@@ -54,6 +55,7 @@ func (r *TransactionReactor) OnReceive(subProtocol module.ProtocolInfo, buf []by
 		if err != nil {
 			r.log.Warnf("InvalidPacket(ResponseTransaction) from=%s", peerId.String())
 			r.log.Debugf("Failed to unmarshal transaction. buf=%x, err=%+v", buf, err)
+			r.membership.Penalize(peerId, module.PenaltyMajor, "InvalidPacket(ResponseTransaction)")
 			return false, err
 		}
 
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"sync"
@@ -138,6 +139,18 @@ func (tr *transitionResult) Bytes() []byte {
 	}
 }
 
+// ParseResult returns the hashes composing the result of the block.
+func ParseResult(result []byte) (stateHash, patchReceiptHash, normalReceiptHash []byte, err error) {
+	if len(result) == 0 {
+		return nil, nil, nil, nil
+	}
+	tr, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return nil, nil, nil, errors.InvalidStateError.Wrap(err, "InvalidResult")
+	}
+	return tr.StateHash, tr.PatchReceiptHash, tr.NormalReceiptHash, nil
+}
+
 func patchTransition(t *transition, patchTXs module.TransactionList) *transition {
 	if patchTXs == nil {
 		patchTXs = transaction.NewTransactionListFromSlice(t.db, nil)
@@ -493,6 +506,7 @@ func (t *transition) doExecute(alreadyValidated bool) {
 	}
 	ctx := contract.NewContext(wc, t.cm, t.eem, t.chain, t.log, t.ti)
 	ctx.ClearCache()
+	validators := ctx.GetValidatorState().GetSnapshot()
 
 	startTime := time.Now()
 
@@ -506,6 +520,9 @@ func (t *transition) doExecute(alreadyValidated bool) {
 		t.reportExecution(err)
 		return
 	}
+	if ctx.Revision() >= module.Revision10 {
+		updateValidatorsHeight(ctx, validators)
+	}
 
 	cumulativeSteps := big.NewInt(0)
 	gatheredFee := big.NewInt(0)
@@ -555,6 +572,18 @@ func (t *transition) doExecute(alreadyValidated bool) {
 	t.reportExecution(nil)
 }
 
+// updateValidatorsHeight records the height of the votes from which the
+// validators of the world state are used, if they are changed from the
+// validators before the execution. The validators after the execution of
+// the block are used for the votes of the block after the next block.
+func updateValidatorsHeight(wc state.WorldContext, old state.ValidatorSnapshot) {
+	as := wc.GetAccountState(state.SystemID)
+	vh := scoredb.NewVarDB(as, state.VarValidatorsHeight)
+	if vh.Int64() == 0 || !bytes.Equal(old.Hash(), wc.GetValidatorState().GetSnapshot().Hash()) {
+		vh.Set(wc.BlockHeight() + 2)
+	}
+}
+
 func (t *transition) validateTxs(l module.TransactionList, wc state.WorldContext, tsr TimestampRange) (int, error) {
 	if l == nil {
 		return 0, nil
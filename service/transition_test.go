@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResult(t *testing.T) {
+	tr := &transitionResult{
+		StateHash:         []byte("state"),
+		PatchReceiptHash:  []byte("patch"),
+		NormalReceiptHash: []byte("normal"),
+	}
+	sh, prh, nrh, err := ParseResult(tr.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, tr.StateHash, sh)
+	assert.Equal(t, tr.PatchReceiptHash, prh)
+	assert.Equal(t, tr.NormalReceiptHash, nrh)
+
+	sh, _, _, err = ParseResult(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, sh)
+
+	_, _, _, err = ParseResult([]byte{0x01, 0x02})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+// TxPolicy decides whether the transaction can be admitted to the pool.
+// pending is the number of transactions of the sender in the pool.
+//
+// There is no policy for the minimum step price. Step price is set by the
+// governance for every transaction of the chain, and a transaction can't
+// offer its own price.
+type TxPolicy interface {
+	CheckTx(tx transaction.Transaction, pending int) error
+}
+
+type senderQuotaPolicy int
+
+// NewSenderQuotaPolicy returns the policy limiting the number of pending
+// transactions of a sender.
+func NewSenderQuotaPolicy(max int) TxPolicy {
+	return senderQuotaPolicy(max)
+}
+
+func (p senderQuotaPolicy) CheckTx(tx transaction.Transaction, pending int) error {
+	if pending >= int(p) {
+		return TooManyTransactionsError.Errorf(
+			"TooManyTransactions(from=%s,pending=%d,max=%d)", tx.From(), pending, int(p))
+	}
+	return nil
+}
+
+type addressListPolicy struct {
+	allow map[string]bool
+	deny  map[string]bool
+}
+
+// NewAddressListPolicy returns the policy checking the sender of
+// the transaction. If allow is not empty, only the senders in the list
+// are allowed. Senders in deny are rejected.
+func NewAddressListPolicy(allow, deny []module.Address) TxPolicy {
+	p := &addressListPolicy{}
+	if len(allow) > 0 {
+		p.allow = make(map[string]bool)
+		for _, addr := range allow {
+			p.allow[string(addr.Bytes())] = true
+		}
+	}
+	p.deny = make(map[string]bool)
+	for _, addr := range deny {
+		p.deny[string(addr.Bytes())] = true
+	}
+	return p
+}
+
+func (p *addressListPolicy) CheckTx(tx transaction.Transaction, pending int) error {
+	from := string(tx.From().Bytes())
+	if p.deny[from] {
+		return RejectedTransactionError.Errorf("DeniedSender(from=%s)", tx.From())
+	}
+	if p.allow != nil && !p.allow[from] {
+		return RejectedTransactionError.Errorf("NotAllowedSender(from=%s)", tx.From())
+	}
+	return nil
+}
+
+// TxPoliciesOf returns admission policies for normal transactions
+// configured for the chain.
+func TxPoliciesOf(chain module.Chain) []TxPolicy {
+	var policies []TxPolicy
+	if allow, deny := chain.TxPoolAllowList(), chain.TxPoolDenyList(); len(allow) > 0 || len(deny) > 0 {
+		policies = append(policies, NewAddressListPolicy(allow, deny))
+	}
+	if max := chain.TxPoolMaxPerSender(); max > 0 {
+		policies = append(policies, NewSenderQuotaPolicy(max))
+	}
+	return policies
+}
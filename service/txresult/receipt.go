@@ -23,6 +23,7 @@ import (
 
 const (
 	EventLogICXTransfer = "ICXTransfer(Address,Address,int)"
+	EventLogDoubleSign  = "DoubleSign(Address,int,bytes)"
 )
 
 type eventLogJSON struct {
@@ -115,6 +116,27 @@ func (log *eventLog) ToJSON(module.JSONVersion) (*eventLogJSON, error) {
 	return eljson, nil
 }
 
+// EventLogToJSON returns the JSON object of the event log, which is same as
+// the one in eventLogs of the receipt.
+func EventLogToJSON(el module.EventLog, version module.JSONVersion) (map[string]interface{}, error) {
+	log, ok := el.(*eventLog)
+	if !ok {
+		log = new(eventLog)
+		log.eventLogData.Addr.SetBytes(el.Address().Bytes())
+		log.eventLogData.Indexed = el.Indexed()
+		log.eventLogData.Data = el.Data()
+	}
+	eljson, err := log.ToJSON(version)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"scoreAddress": &eljson.Addr,
+		"indexed":      eljson.Indexed,
+		"data":         eljson.Data,
+	}, nil
+}
+
 type Version int
 
 const (
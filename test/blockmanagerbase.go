@@ -54,6 +54,18 @@ func (_r *BlockManagerBase) GetTransactionInfo(id []byte) (module.TransactionInf
 	panic("not implemented")
 }
 
+func (_r *BlockManagerBase) GetTransactionCountByAddress(addr module.Address) (int64, error) {
+	panic("not implemented")
+}
+
+func (_r *BlockManagerBase) GetTransactionsByAddress(addr module.Address, start int64, limit int) ([][]byte, error) {
+	panic("not implemented")
+}
+
+func (_r *BlockManagerBase) GetBlockProof(height int64, atHeight int64) (int64, [][]byte, error) {
+	panic("not implemented")
+}
+
 func (_r *BlockManagerBase) Term() {
 	panic("not implemented")
 }
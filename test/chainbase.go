@@ -52,6 +52,38 @@ func (_r *ChainBase) MaxBlockTxBytes() int {
 	panic("not implemented")
 }
 
+func (_r *ChainBase) AccountIndex() bool {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) MaxLogsRange() int {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) MaxLogsResult() int {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) PrunedStateHeight() (int64, error) {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) TxPoolMaxPerSender() int {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) TxPoolAllowList() []module.Address {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) TxPoolDenyList() []module.Address {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) TxRateLimit() int {
+	panic("not implemented")
+}
+
 func (_r *ChainBase) DefaultWaitTimeout() time.Duration {
 	panic("not implemented")
 }
@@ -112,11 +144,23 @@ func (_r *ChainBase) Import(src string, height int64) error {
 	panic("not implemented")
 }
 
+func (_r *ChainBase) ImportArchive(file string) error {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) ImportSnapshot(file string) error {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) Export(file string, from int64, to int64, receipts bool) error {
+	panic("not implemented")
+}
+
 func (_r *ChainBase) Prune(gs string, dbt string, height int64) error {
 	panic("not implemented")
 }
 
-func (_r *ChainBase) Backup(file string, extra []string) error {
+func (_r *ChainBase) Backup(file string, extra []string, online bool, base string) error {
 	panic("not implemented")
 }
 
@@ -144,6 +188,14 @@ func (_r *ChainBase) Verify() error {
 	panic("not implemented")
 }
 
+func (_r *ChainBase) Check(file string, repair bool) error {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) FindOrphans(limit int) (int64, [][]byte, error) {
+	panic("not implemented")
+}
+
 func (_r *ChainBase) MetricContext() context.Context {
 	panic("not implemented")
 }
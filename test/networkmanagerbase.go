@@ -60,3 +60,11 @@ func (_r *NetworkManagerBase) SetTrustSeeds(seeds string) {
 func (_r *NetworkManagerBase) SetInitialRoles(roles ...module.Role) {
 	panic("not implemented")
 }
+
+func (_r *NetworkManagerBase) SetStaticPeers(peers string) {
+	panic("not implemented")
+}
+
+func (_r *NetworkManagerBase) SetPeerAllowList(peers string) {
+	panic("not implemented")
+}
@@ -141,3 +141,7 @@ func (_r *ServiceManagerBase) ImportResult(result []byte, vh []byte, src db.Data
 func (_r *ServiceManagerBase) ExecuteTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo) (module.Receipt, error) {
 	panic("not implemented")
 }
+
+func (_r *ServiceManagerBase) ExecuteTransactionForTrace(result []byte, vh []byte, js []byte, bi module.BlockInfo, cb module.TraceCallback) (module.Receipt, error) {
+	panic("not implemented")
+}